@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/yuno-payments/papaya-payout-engine/internal/backtest"
+)
+
+type BacktestService interface {
+	Submit(ctx context.Context, req backtest.Request, requestedBy string) (*backtest.Job, error)
+	Get(ctx context.Context, id uuid.UUID) (*backtest.Job, error)
+	List(ctx context.Context, limit, offset int) ([]backtest.Job, int64, error)
+	Report(ctx context.Context, id uuid.UUID) (*backtest.Report, error)
+}
+
+type BacktestHandler struct {
+	backtests BacktestService
+}
+
+func NewBacktestHandler(backtests BacktestService) *BacktestHandler {
+	return &BacktestHandler{backtests: backtests}
+}
+
+// Create starts a backtest and returns it while it runs.
+func (h *BacktestHandler) Create(c echo.Context) error {
+	var req backtest.Request
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	requestedBy := strings.TrimSpace(c.Request().Header.Get(analystHeader))
+	job, err := h.backtests.Submit(c.Request().Context(), req, requestedBy)
+	if err != nil {
+		return backtestError(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, job)
+}
+
+func (h *BacktestHandler) List(c echo.Context) error {
+	limit, offset := paginationParams(c)
+
+	jobs, total, err := h.backtests.List(c.Request().Context(), limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"backtests": jobs,
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+func (h *BacktestHandler) Get(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid backtest ID"})
+	}
+
+	job, err := h.backtests.Get(c.Request().Context(), id)
+	if err != nil {
+		return backtestError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
+
+// Report downloads a completed backtest with one row per replayed decision,
+// as JSON (the default) or CSV with ?format=csv.
+func (h *BacktestHandler) Report(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid backtest ID"})
+	}
+
+	format := strings.ToLower(c.QueryParam("format"))
+	if format != "" && format != "json" && format != "csv" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be json or csv"})
+	}
+
+	report, err := h.backtests.Report(c.Request().Context(), id)
+	if err != nil {
+		return backtestError(c, err)
+	}
+
+	if format == "csv" {
+		c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=backtest-%s.csv", id))
+		c.Response().WriteHeader(http.StatusOK)
+		return report.WriteCSV(c.Request().Context(), c.Response())
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=backtest-%s.json", id))
+	c.Response().WriteHeader(http.StatusOK)
+	return report.WriteJSON(c.Request().Context(), c.Response())
+}
+
+func backtestError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, backtest.ErrInvalidRequest):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, backtest.ErrJobNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "backtest not found"})
+	case errors.Is(err, backtest.ErrJobNotCompleted):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
@@ -52,7 +52,7 @@ func (h *BatchHandler) BatchEvaluate(c echo.Context) error {
 		})
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request().Context(), constants.BatchTimeout)
+	ctx, cancel := context.WithTimeout(requestContext(c), constants.BatchTimeout)
 	defer cancel()
 
 	batchID := uuid.New()
@@ -124,6 +124,7 @@ func (h *BatchHandler) BatchEvaluate(c echo.Context) error {
 		})
 	}
 
+	localize(c, decisions...)
 	summary := h.generateSummary(c.Request().Context(), decisions)
 	highRiskMerchants := h.identifyHighRiskMerchants(decisions)
 
@@ -186,16 +187,18 @@ func (h *BatchHandler) identifyHighRiskMerchants(decisions []*risk.RiskDecision)
 	highRisk := make([]map[string]interface{}, 0)
 
 	for _, d := range decisions {
-		if d.RiskScore > 60 {
+		if d.RiskScore > 60 || d.RiskLevel == risk.RiskLevelHigh || d.RiskLevel == risk.RiskLevelCritical {
 			concerns := make([]string, 0)
+			reasonCodes := make([]string, 0)
 			for _, factor := range d.Reasoning.PrimaryFactors {
-				if factor.Impact == "NEGATIVE" || factor.Impact == "CRITICAL" {
+				if factor.Impact == "NEGATIVE" || factor.Impact == "CRITICAL" || factor.Impact == "HARD_STOP" {
 					concerns = append(concerns, factor.Contribution)
+					reasonCodes = append(reasonCodes, factor.ReasonCode)
 				}
 			}
 
 			action := "Manual review required"
-			if d.RiskScore > 80 {
+			if d.RiskScore > 80 || d.RiskLevel == risk.RiskLevelCritical {
 				action = "Immediate manual approval required"
 			}
 
@@ -204,6 +207,7 @@ func (h *BatchHandler) identifyHighRiskMerchants(decisions []*risk.RiskDecision)
 				"risk_score":          d.RiskScore,
 				"risk_level":          d.RiskLevel,
 				"primary_concerns":    concerns,
+				"reason_codes":        reasonCodes,
 				"recommended_action":  action,
 			})
 		}
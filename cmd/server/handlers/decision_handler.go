@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/yuno-payments/papaya-payout-engine/internal/platform/constants"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+type DecisionStore interface {
+	ListByModelVersion(ctx context.Context, version string, limit, offset int) ([]risk.RiskDecision, int64, error)
+	ListModelVersions(ctx context.Context) ([]risk.ModelVersionSummary, error)
+	ListPending(ctx context.Context, limit, offset int) ([]risk.RiskDecision, int64, error)
+}
+
+type DecisionApprover interface {
+	ApproveDecision(ctx context.Context, id uuid.UUID, reviewedBy, note string) (*risk.RiskDecision, error)
+	RejectDecision(ctx context.Context, id uuid.UUID, reviewedBy, note string) (*risk.RiskDecision, error)
+}
+
+type DecisionHandler struct {
+	decisionStore DecisionStore
+	approver      DecisionApprover
+	activeVersion string
+}
+
+func NewDecisionHandler(decisionStore DecisionStore, approver DecisionApprover, activeVersion string) *DecisionHandler {
+	return &DecisionHandler{
+		decisionStore: decisionStore,
+		approver:      approver,
+		activeVersion: activeVersion,
+	}
+}
+
+type ReviewDecisionRequest struct {
+	Note string `json:"note"`
+}
+
+// ListModelVersions returns every scoring model version that produced a
+// persisted decision, plus the version currently applied to new evaluations.
+func (h *DecisionHandler) ListModelVersions(c echo.Context) error {
+	versions, err := h.decisionStore.ListModelVersions(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	activeSeen := false
+	for i := range versions {
+		if versions[i].Version == h.activeVersion {
+			versions[i].Active = true
+			activeSeen = true
+		}
+	}
+	if !activeSeen {
+		versions = append([]risk.ModelVersionSummary{{Version: h.activeVersion, Active: true}}, versions...)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"active_version": h.activeVersion,
+		"versions":       versions,
+	})
+}
+
+func (h *DecisionHandler) List(c echo.Context) error {
+	limit, offset := paginationParams(c)
+
+	decisions, total, err := h.decisionStore.ListByModelVersion(
+		c.Request().Context(), c.QueryParam("model_version"), limit, offset,
+	)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	for i := range decisions {
+		localize(c, &decisions[i])
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"decisions": decisions,
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// ListPending returns decisions awaiting approval, oldest first.
+func (h *DecisionHandler) ListPending(c echo.Context) error {
+	limit, offset := paginationParams(c)
+
+	decisions, total, err := h.decisionStore.ListPending(c.Request().Context(), limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	for i := range decisions {
+		localize(c, &decisions[i])
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"decisions": decisions,
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+func (h *DecisionHandler) Approve(c echo.Context) error {
+	return h.review(c, h.approver.ApproveDecision)
+}
+
+func (h *DecisionHandler) Reject(c echo.Context) error {
+	return h.review(c, h.approver.RejectDecision)
+}
+
+func (h *DecisionHandler) review(c echo.Context, apply func(context.Context, uuid.UUID, string, string) (*risk.RiskDecision, error)) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid decision ID"})
+	}
+
+	reviewer := c.Request().Header.Get(analystHeader)
+	if reviewer == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": analystHeader + " header is required"})
+	}
+
+	var req ReviewDecisionRequest
+	if c.Request().ContentLength > 0 {
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		}
+	}
+
+	decision, err := apply(c.Request().Context(), id, reviewer, req.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, risk.ErrDecisionNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		case errors.Is(err, risk.ErrNotApprover):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		case errors.Is(err, risk.ErrDecisionNotPending):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case errors.Is(err, risk.ErrApprovalDisabled):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	localize(c, decision)
+	return c.JSON(http.StatusOK, decision)
+}
+
+func paginationParams(c echo.Context) (int, int) {
+	limit := constants.DefaultQueryLimit
+	offset := 0
+
+	if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > constants.MaxQueryLimit {
+		limit = constants.MaxQueryLimit
+	}
+	if o, err := strconv.Atoi(c.QueryParam("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+
+	return limit, offset
+}
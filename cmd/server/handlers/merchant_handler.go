@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -79,6 +80,34 @@ func (h *MerchantHandler) Get(c echo.Context) error {
 	return c.JSON(http.StatusOK, m)
 }
 
+// UpdateMetrics applies a partial metrics update. A change to a score-relevant
+// metric queues a re-evaluation whose decision links back to the change.
+func (h *MerchantHandler) UpdateMetrics(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid merchant ID"})
+	}
+
+	var req merchant.MetricsUpdate
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	change, err := h.merchantService.UpdateMetrics(c.Request().Context(), id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, merchant.ErrInvalidMetricsUpdate):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, merchant.ErrMerchantNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "merchant not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, change)
+}
+
 func (h *MerchantHandler) List(c echo.Context) error {
 	limitStr := c.QueryParam("limit")
 	offsetStr := c.QueryParam("offset")
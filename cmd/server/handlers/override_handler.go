@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+// analystHeader identifies the analyst creating or revoking an override.
+const analystHeader = "X-User-ID"
+
+type ManualOverrideService interface {
+	Create(ctx context.Context, override *risk.ManualOverride) (*risk.ManualOverride, error)
+	List(ctx context.Context, merchantID uuid.UUID) ([]risk.ManualOverride, error)
+	Revoke(ctx context.Context, merchantID, id uuid.UUID, revokedBy, reason string) (*risk.ManualOverride, error)
+}
+
+type OverrideHandler struct {
+	overrides ManualOverrideService
+}
+
+func NewOverrideHandler(overrides ManualOverrideService) *OverrideHandler {
+	return &OverrideHandler{overrides: overrides}
+}
+
+type CreateManualOverrideRequest struct {
+	HoldPeriod        risk.HoldPeriod `json:"hold_period"`
+	ReservePercentage *int            `json:"reserve_percentage"`
+	Justification     string          `json:"justification"`
+	ExpiresAt         *time.Time      `json:"expires_at"`
+}
+
+type RevokeManualOverrideRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (h *OverrideHandler) Create(c echo.Context) error {
+	merchantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid merchant ID"})
+	}
+
+	author := strings.TrimSpace(c.Request().Header.Get(analystHeader))
+	if author == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": analystHeader + " header is required"})
+	}
+
+	var req CreateManualOverrideRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	if req.ReservePercentage == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "reserve_percentage is required"})
+	}
+	if req.ExpiresAt == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "expires_at is required"})
+	}
+
+	override, err := h.overrides.Create(c.Request().Context(), &risk.ManualOverride{
+		MerchantID:        merchantID,
+		HoldPeriod:        req.HoldPeriod,
+		ReservePercentage: *req.ReservePercentage,
+		Justification:     req.Justification,
+		CreatedBy:         author,
+		ExpiresAt:         *req.ExpiresAt,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, risk.ErrInvalidManualOverride):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, risk.ErrActiveManualOverride):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, override)
+}
+
+// List returns the merchant's full override history, including expired and
+// revoked overrides.
+func (h *OverrideHandler) List(c echo.Context) error {
+	merchantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid merchant ID"})
+	}
+
+	overrides, err := h.overrides.List(c.Request().Context(), merchantID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"overrides": overrides,
+	})
+}
+
+func (h *OverrideHandler) Revoke(c echo.Context) error {
+	merchantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid merchant ID"})
+	}
+
+	id, err := uuid.Parse(c.Param("override_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid manual override ID"})
+	}
+
+	author := strings.TrimSpace(c.Request().Header.Get(analystHeader))
+	if author == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": analystHeader + " header is required"})
+	}
+
+	var req RevokeManualOverrideRequest
+	if c.Request().ContentLength > 0 {
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		}
+	}
+
+	override, err := h.overrides.Revoke(c.Request().Context(), merchantID, id, author, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, risk.ErrInvalidManualOverride):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, risk.ErrManualOverrideRevoked):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case errors.Is(err, risk.ErrManualOverrideMissing):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "manual override not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, override)
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+type TierTableService interface {
+	Draft(ctx context.Context, version string, tiers []risk.PolicyTier) (*risk.PolicyTierTable, error)
+	Get(ctx context.Context, id uuid.UUID) (*risk.PolicyTierTable, error)
+	List(ctx context.Context) ([]risk.PolicyTierTable, error)
+	Activate(ctx context.Context, id uuid.UUID, from time.Time, until *time.Time) (*risk.PolicyTierTable, error)
+	Active(ctx context.Context, at time.Time) (*risk.PolicyTierTable, error)
+}
+
+type PolicyHandler struct {
+	tierTables TierTableService
+	overrides  PolicyOverrideService
+}
+
+func NewPolicyHandler(tierTables TierTableService, overrides PolicyOverrideService) *PolicyHandler {
+	return &PolicyHandler{
+		tierTables: tierTables,
+		overrides:  overrides,
+	}
+}
+
+type DraftTierTableRequest struct {
+	Version string            `json:"version"`
+	Tiers   []risk.PolicyTier `json:"tiers"`
+}
+
+type ActivateTierTableRequest struct {
+	EffectiveFrom  *time.Time `json:"effective_from"`
+	EffectiveUntil *time.Time `json:"effective_until"`
+}
+
+type TierValidationResponse struct {
+	Valid    bool     `json:"valid"`
+	Problems []string `json:"problems"`
+}
+
+func (h *PolicyHandler) DraftTierTable(c echo.Context) error {
+	var req DraftTierTableRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	if req.Version == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "version is required"})
+	}
+
+	table, err := h.tierTables.Draft(c.Request().Context(), req.Version, req.Tiers)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"tier_table": table,
+		"validation": tierValidation(table.Tiers),
+	})
+}
+
+func (h *PolicyHandler) ListTierTables(c echo.Context) error {
+	tables, err := h.tierTables.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"tier_tables": tables,
+	})
+}
+
+func (h *PolicyHandler) GetTierTable(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid tier table ID"})
+	}
+
+	table, err := h.tierTables.Get(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "tier table not found"})
+	}
+
+	return c.JSON(http.StatusOK, table)
+}
+
+// GetActiveTierTable returns the tier table in force now, or at the time given
+// in the "at" query parameter (RFC 3339).
+func (h *PolicyHandler) GetActiveTierTable(c echo.Context) error {
+	at := time.Now()
+	if atStr := c.QueryParam("at"); atStr != "" {
+		parsed, err := time.Parse(time.RFC3339, atStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "at must be an RFC 3339 timestamp"})
+		}
+		at = parsed
+	}
+
+	table, err := h.tierTables.Active(c.Request().Context(), at)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, table)
+}
+
+func (h *PolicyHandler) ValidateTierTable(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid tier table ID"})
+	}
+
+	table, err := h.tierTables.Get(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "tier table not found"})
+	}
+
+	return c.JSON(http.StatusOK, tierValidation(table.Tiers))
+}
+
+func (h *PolicyHandler) ActivateTierTable(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid tier table ID"})
+	}
+
+	var req ActivateTierTableRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	var from time.Time
+	if req.EffectiveFrom != nil {
+		from = *req.EffectiveFrom
+	}
+
+	table, err := h.tierTables.Activate(c.Request().Context(), id, from, req.EffectiveUntil)
+	if err != nil {
+		var validationErr *risk.TierValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			return c.JSON(http.StatusUnprocessableEntity, TierValidationResponse{
+				Valid:    false,
+				Problems: validationErr.Problems,
+			})
+		case errors.Is(err, risk.ErrTierTableNotDraft):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case errors.Is(err, risk.ErrInvalidEffective):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, table)
+}
+
+func tierValidation(tiers []risk.PolicyTier) TierValidationResponse {
+	response := TierValidationResponse{Valid: true, Problems: []string{}}
+
+	var validationErr *risk.TierValidationError
+	if err := risk.ValidateTiers(tiers); errors.As(err, &validationErr) {
+		response.Valid = false
+		response.Problems = validationErr.Problems
+	}
+
+	return response
+}
+
+type PolicyOverrideService interface {
+	Create(ctx context.Context, override *risk.PolicyOverride) (*risk.PolicyOverride, error)
+	Get(ctx context.Context, id uuid.UUID) (*risk.PolicyOverride, error)
+	List(ctx context.Context) ([]risk.PolicyOverride, error)
+	Deactivate(ctx context.Context, id uuid.UUID) error
+}
+
+type CreatePolicyOverrideRequest struct {
+	Name                string                  `json:"name"`
+	Description         string                  `json:"description"`
+	Country             string                  `json:"country"`
+	Industry            string                  `json:"industry"`
+	Mode                risk.PolicyOverrideMode `json:"mode"`
+	Tiers               []risk.PolicyTier       `json:"tiers"`
+	HoldAdjustmentSteps int                     `json:"hold_adjustment_steps"`
+	ReserveAdjustment   int                     `json:"reserve_adjustment"`
+}
+
+func (h *PolicyHandler) CreateOverride(c echo.Context) error {
+	var req CreatePolicyOverrideRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	override, err := h.overrides.Create(c.Request().Context(), &risk.PolicyOverride{
+		Name:                req.Name,
+		Description:         req.Description,
+		Country:             req.Country,
+		Industry:            req.Industry,
+		Mode:                req.Mode,
+		Tiers:               req.Tiers,
+		HoldAdjustmentSteps: req.HoldAdjustmentSteps,
+		ReserveAdjustment:   req.ReserveAdjustment,
+	})
+	if err != nil {
+		if errors.Is(err, risk.ErrInvalidPolicyOverride) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, override)
+}
+
+func (h *PolicyHandler) ListOverrides(c echo.Context) error {
+	overrides, err := h.overrides.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"overrides": overrides,
+	})
+}
+
+func (h *PolicyHandler) GetOverride(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid policy override ID"})
+	}
+
+	override, err := h.overrides.Get(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "policy override not found"})
+	}
+
+	return c.JSON(http.StatusOK, override)
+}
+
+func (h *PolicyHandler) DeactivateOverride(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid policy override ID"})
+	}
+
+	if err := h.overrides.Deactivate(c.Request().Context(), id); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+type PortfolioSimulator interface {
+	SimulatePortfolio(ctx context.Context, scenario risk.PortfolioScenario) (*risk.PortfolioSimulation, error)
+}
+
+type PortfolioHandler struct {
+	simulator PortfolioSimulator
+}
+
+func NewPortfolioHandler(simulator PortfolioSimulator) *PortfolioHandler {
+	return &PortfolioHandler{simulator: simulator}
+}
+
+// Simulate applies scoring thresholds and/or tier changes to every merchant
+// matching the filter and returns baseline and scenario aggregates. Nothing is
+// persisted.
+func (h *PortfolioHandler) Simulate(c echo.Context) error {
+	var scenario risk.PortfolioScenario
+	if err := c.Bind(&scenario); err != nil {
+		return bindError(c, err)
+	}
+
+	simulation, err := h.simulator.SimulatePortfolio(requestContext(c), scenario)
+	var fields risk.OverrideErrors
+	if errors.As(err, &fields) {
+		return overrideErrors(c, fields)
+	}
+	if errors.Is(err, risk.ErrInvalidPortfolioScenario) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, simulation)
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/yuno-payments/papaya-payout-engine/internal/review"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+type ReviewService interface {
+	Get(ctx context.Context, id uuid.UUID) (*review.Case, error)
+	List(ctx context.Context, filter review.Filter) ([]review.Case, int64, error)
+	Assign(ctx context.Context, id uuid.UUID, assignee, by string) (*review.Case, error)
+	Transition(ctx context.Context, id uuid.UUID, status review.Status, by, comment string) (*review.Case, error)
+	AddComment(ctx context.Context, id uuid.UUID, author, body string) (*review.Comment, error)
+}
+
+type ReviewHandler struct {
+	reviews ReviewService
+}
+
+func NewReviewHandler(reviews ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviews: reviews}
+}
+
+type AssignCaseRequest struct {
+	Assignee string `json:"assignee"`
+}
+
+type TransitionCaseRequest struct {
+	Status  review.Status `json:"status"`
+	Comment string        `json:"comment"`
+}
+
+type AddCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// List returns the review queue, most urgent SLA first. Filters: status
+// (comma-separated, defaults to unresolved), risk_level, assignee,
+// min_age_hours, max_age_hours and sla_breached.
+func (h *ReviewHandler) List(c echo.Context) error {
+	limit, offset := paginationParams(c)
+	filter := review.Filter{
+		RiskLevel: risk.RiskLevel(strings.ToUpper(c.QueryParam("risk_level"))),
+		Assignee:  c.QueryParam("assignee"),
+		Limit:     limit,
+		Offset:    offset,
+	}
+
+	if statuses := c.QueryParam("status"); statuses != "" {
+		for _, s := range strings.Split(statuses, ",") {
+			status := review.Status(strings.TrimSpace(s))
+			if !status.Valid() {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid status: " + s})
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	var err error
+	if filter.MinAge, err = hoursParam(c, "min_age_hours"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if filter.MaxAge, err = hoursParam(c, "max_age_hours"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	filter.SLABreached = c.QueryParam("sla_breached") == "true"
+
+	cases, total, err := h.reviews.List(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"cases":  cases,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+func (h *ReviewHandler) Get(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid review case ID"})
+	}
+
+	rc, err := h.reviews.Get(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "review case not found"})
+	}
+
+	return c.JSON(http.StatusOK, rc)
+}
+
+func (h *ReviewHandler) Assign(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid review case ID"})
+	}
+
+	var req AssignCaseRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	rc, err := h.reviews.Assign(c.Request().Context(), id, req.Assignee, c.Request().Header.Get(analystHeader))
+	if err != nil {
+		return reviewError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rc)
+}
+
+func (h *ReviewHandler) Transition(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid review case ID"})
+	}
+
+	var req TransitionCaseRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	rc, err := h.reviews.Transition(c.Request().Context(), id, req.Status, c.Request().Header.Get(analystHeader), req.Comment)
+	if err != nil {
+		return reviewError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rc)
+}
+
+func (h *ReviewHandler) AddComment(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid review case ID"})
+	}
+
+	var req AddCommentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	comment, err := h.reviews.AddComment(c.Request().Context(), id, c.Request().Header.Get(analystHeader), req.Body)
+	if err != nil {
+		return reviewError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, comment)
+}
+
+func reviewError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, review.ErrInvalidCase):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, review.ErrCaseNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "review case not found"})
+	case errors.Is(err, review.ErrInvalidTransition):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+func hoursParam(c echo.Context, name string) (time.Duration, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	hours, err := strconv.ParseFloat(value, 64)
+	if err != nil || hours < 0 {
+		return 0, errors.New(name + " must be a non-negative number")
+	}
+	return time.Duration(hours * float64(time.Hour)), nil
+}
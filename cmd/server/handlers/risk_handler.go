@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -12,8 +13,10 @@ import (
 
 type RiskService interface {
 	EvaluateMerchant(ctx context.Context, merchantID uuid.UUID, simulation bool) (*risk.RiskDecision, error)
-	SimulateMerchant(ctx context.Context, merchantID uuid.UUID, overrides map[string]interface{}) (*risk.RiskDecision, error)
+	SimulateMerchant(ctx context.Context, merchantID uuid.UUID, overrides risk.SimulationOverrides) (*risk.SimulationResult, error)
 	GetMerchantProfile(ctx context.Context, merchantID uuid.UUID) (*merchant.MerchantProfile, error)
+	PathToNextTier(ctx context.Context, merchantID uuid.UUID) (*risk.TierPath, error)
+	ReasonCodes() []risk.ReasonCode
 }
 
 type RiskHandler struct {
@@ -30,8 +33,8 @@ type EvaluateRequest struct {
 }
 
 type SimulateRequest struct {
-	MerchantID string                 `json:"merchant_id"`
-	Overrides  map[string]interface{} `json:"overrides"`
+	MerchantID string                   `json:"merchant_id"`
+	Overrides  risk.SimulationOverrides `json:"overrides"`
 }
 
 func (h *RiskHandler) Evaluate(c echo.Context) error {
@@ -45,18 +48,21 @@ func (h *RiskHandler) Evaluate(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid merchant ID"})
 	}
 
-	decision, err := h.riskService.EvaluateMerchant(c.Request().Context(), merchantID, req.Simulation)
+	decision, err := h.riskService.EvaluateMerchant(requestContext(c), merchantID, req.Simulation)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
+	localize(c, decision)
 	return c.JSON(http.StatusOK, decision)
 }
 
+// Simulate scores the merchant with the given overrides. Unknown or invalid
+// overrides are rejected with an error per field.
 func (h *RiskHandler) Simulate(c echo.Context) error {
 	var req SimulateRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return bindError(c, err)
 	}
 
 	merchantID, err := uuid.Parse(req.MerchantID)
@@ -64,12 +70,20 @@ func (h *RiskHandler) Simulate(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid merchant ID"})
 	}
 
-	decision, err := h.riskService.SimulateMerchant(c.Request().Context(), merchantID, req.Overrides)
+	result, err := h.riskService.SimulateMerchant(requestContext(c), merchantID, req.Overrides)
+	var fields risk.OverrideErrors
+	if errors.As(err, &fields) {
+		return overrideErrors(c, fields)
+	}
+	if errors.Is(err, risk.ErrInvalidScoringMode) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	return c.JSON(http.StatusOK, decision)
+	localize(c, result.RiskDecision, result.Baseline)
+	return c.JSON(http.StatusOK, result)
 }
 
 func (h *RiskHandler) GetProfile(c echo.Context) error {
@@ -86,3 +100,66 @@ func (h *RiskHandler) GetProfile(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, profile)
 }
+
+// GetNextTier returns ranked metric changes that would move the merchant to a
+// better tier.
+func (h *RiskHandler) GetNextTier(c echo.Context) error {
+	merchantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid merchant ID"})
+	}
+
+	path, err := h.riskService.PathToNextTier(requestContext(c), merchantID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, path)
+}
+
+// ListReasonCodes publishes the reason codes decisions can carry, so
+// integrations can key on codes instead of explanation text.
+func (h *RiskHandler) ListReasonCodes(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"reason_codes": h.riskService.ReasonCodes(),
+	})
+}
+
+// bindError rejects a request body that could not be decoded, listing the
+// offending fields when simulation overrides or thresholds were invalid.
+func bindError(c echo.Context, err error) error {
+	var fields risk.OverrideErrors
+	if errors.As(err, &fields) {
+		return overrideErrors(c, fields)
+	}
+	return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+}
+
+func overrideErrors(c echo.Context, fields risk.OverrideErrors) error {
+	return c.JSON(http.StatusBadRequest, map[string]interface{}{
+		"error":  risk.ErrInvalidOverrides.Error(),
+		"fields": fields,
+	})
+}
+
+// requestContext carries the language negotiated from Accept-Language so
+// text the service renders for the response, such as counterfactuals, is
+// rendered in it.
+func requestContext(c echo.Context) context.Context {
+	lang := risk.NegotiateLanguage(c.Request().Header.Get("Accept-Language"))
+	return risk.WithLanguage(c.Request().Context(), lang)
+}
+
+// localize renders the decisions' reasoning, stored in the default language,
+// in the language negotiated from Accept-Language.
+func localize(c echo.Context, decisions ...*risk.RiskDecision) {
+	lang := risk.NegotiateLanguage(c.Request().Header.Get("Accept-Language"))
+	if lang == risk.DefaultLanguage {
+		return
+	}
+	for _, d := range decisions {
+		if d != nil {
+			d.Reasoning.Localize(lang)
+		}
+	}
+}
@@ -19,8 +19,10 @@ import (
 
 type mockRiskService struct {
 	evaluateMerchant    func(ctx context.Context, merchantID uuid.UUID, simulation bool) (*risk.RiskDecision, error)
-	simulateMerchant    func(ctx context.Context, merchantID uuid.UUID, overrides map[string]interface{}) (*risk.RiskDecision, error)
+	simulateMerchant    func(ctx context.Context, merchantID uuid.UUID, overrides risk.SimulationOverrides) (*risk.SimulationResult, error)
 	getMerchantProfile  func(ctx context.Context, merchantID uuid.UUID) (*merchant.MerchantProfile, error)
+	pathToNextTier      func(ctx context.Context, merchantID uuid.UUID) (*risk.TierPath, error)
+	reasonCodes         func() []risk.ReasonCode
 }
 
 func (m *mockRiskService) EvaluateMerchant(ctx context.Context, merchantID uuid.UUID, simulation bool) (*risk.RiskDecision, error) {
@@ -30,7 +32,7 @@ func (m *mockRiskService) EvaluateMerchant(ctx context.Context, merchantID uuid.
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockRiskService) SimulateMerchant(ctx context.Context, merchantID uuid.UUID, overrides map[string]interface{}) (*risk.RiskDecision, error) {
+func (m *mockRiskService) SimulateMerchant(ctx context.Context, merchantID uuid.UUID, overrides risk.SimulationOverrides) (*risk.SimulationResult, error) {
 	if m.simulateMerchant != nil {
 		return m.simulateMerchant(ctx, merchantID, overrides)
 	}
@@ -44,6 +46,20 @@ func (m *mockRiskService) GetMerchantProfile(ctx context.Context, merchantID uui
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockRiskService) PathToNextTier(ctx context.Context, merchantID uuid.UUID) (*risk.TierPath, error) {
+	if m.pathToNextTier != nil {
+		return m.pathToNextTier(ctx, merchantID)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockRiskService) ReasonCodes() []risk.ReasonCode {
+	if m.reasonCodes != nil {
+		return m.reasonCodes()
+	}
+	return nil
+}
+
 func TestRiskHandler_Evaluate(t *testing.T) {
 	merchantID := uuid.New()
 
@@ -126,6 +142,42 @@ func TestRiskHandler_Evaluate(t *testing.T) {
 		}
 	})
 
+	t.Run("accept-language selects reasoning language", func(t *testing.T) {
+		var gotLang string
+		service := &mockRiskService{
+			evaluateMerchant: func(ctx context.Context, id uuid.UUID, simulation bool) (*risk.RiskDecision, error) {
+				gotLang = risk.LanguageFromContext(ctx)
+				return &risk.RiskDecision{MerchantID: id, Reasoning: risk.Reasoning{
+					PrimaryFactors: []risk.FactorExplanation{risk.NewExplainer().ExplainChargebackScore(10, 0.7)},
+				}}, nil
+			},
+		}
+
+		handler := NewRiskHandler(service)
+		e := echo.New()
+		reqBody := `{"merchant_id":"` + merchantID.String() + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/evaluate", strings.NewReader(reqBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Accept-Language", "fr-FR, pt;q=0.9, en;q=0.5")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := handler.Evaluate(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotLang != "pt-BR" {
+			t.Errorf("expected language pt-BR, got %q", gotLang)
+		}
+
+		var decision risk.RiskDecision
+		if err := json.Unmarshal(rec.Body.Bytes(), &decision); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if got := decision.Reasoning.PrimaryFactors[0].Contribution; got != "Taxa de 0.70% - Faixa aceitável" {
+			t.Errorf("expected reasoning in pt-BR, got %q", got)
+		}
+	})
+
 	t.Run("invalid JSON", func(t *testing.T) {
 		handler := NewRiskHandler(&mockRiskService{})
 		e := echo.New()
@@ -194,17 +246,20 @@ func TestRiskHandler_Simulate(t *testing.T) {
 
 	t.Run("successful simulation", func(t *testing.T) {
 		service := &mockRiskService{
-			simulateMerchant: func(ctx context.Context, id uuid.UUID, overrides map[string]interface{}) (*risk.RiskDecision, error) {
+			simulateMerchant: func(ctx context.Context, id uuid.UUID, overrides risk.SimulationOverrides) (*risk.SimulationResult, error) {
 				if id != merchantID {
 					t.Errorf("expected merchant ID %v, got %v", merchantID, id)
 				}
-				if overrides["chargeback_rate"] != 2.5 {
-					t.Errorf("expected chargeback_rate 2.5, got %v", overrides["chargeback_rate"])
+				if overrides.ChargebackRate == nil || overrides.ChargebackRate.InexactFloat64() != 2.5 {
+					t.Errorf("expected chargeback_rate 2.5, got %v", overrides.ChargebackRate)
 				}
-				return &risk.RiskDecision{
-					MerchantID: id,
-					RiskScore:  35,
-					Simulation: true,
+				return &risk.SimulationResult{
+					RiskDecision: &risk.RiskDecision{
+						MerchantID: id,
+						RiskScore:  35,
+						Simulation: true,
+					},
+					AppliedOverrides: overrides,
 				}, nil
 			},
 		}
@@ -225,6 +280,48 @@ func TestRiskHandler_Simulate(t *testing.T) {
 		if rec.Code != http.StatusOK {
 			t.Errorf("expected status 200, got %d", rec.Code)
 		}
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		applied, _ := body["applied_overrides"].(map[string]interface{})
+		if body["risk_score"] != 35.0 || len(applied) != 1 || applied["chargeback_rate"] != "2.5" {
+			t.Errorf("expected decision with the applied chargeback_rate echoed, got %v", body)
+		}
+	})
+
+	t.Run("invalid overrides", func(t *testing.T) {
+		handler := NewRiskHandler(&mockRiskService{})
+		e := echo.New()
+		reqBody := `{"merchant_id":"` + merchantID.String() + `","overrides":{"chargeback_rate":"1.2","account_age_days":-3,` +
+			`"risk_appetite":1,"scoring_thresholds":{"velocity_high_risk":5,"velocity_extreme":9}}}`
+		req := httptest.NewRequest(http.MethodPost, "/simulate", strings.NewReader(reqBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := handler.Simulate(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d", rec.Code)
+		}
+
+		var body struct {
+			Fields map[string]string `json:"fields"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		for _, field := range []string{"chargeback_rate", "account_age_days", "risk_appetite", "scoring_thresholds.velocity_extreme"} {
+			if body.Fields[field] == "" {
+				t.Errorf("expected an error for %s, got %v", field, body.Fields)
+			}
+		}
+		if len(body.Fields) != 4 {
+			t.Errorf("expected 4 field errors, got %v", body.Fields)
+		}
 	})
 
 	t.Run("invalid JSON", func(t *testing.T) {
@@ -357,3 +454,101 @@ func TestRiskHandler_GetProfile(t *testing.T) {
 		}
 	})
 }
+
+func TestRiskHandler_GetNextTier(t *testing.T) {
+	merchantID := uuid.New()
+
+	t.Run("successful path", func(t *testing.T) {
+		service := &mockRiskService{
+			pathToNextTier: func(ctx context.Context, id uuid.UUID) (*risk.TierPath, error) {
+				return &risk.TierPath{
+					MerchantID:       id,
+					CurrentRiskLevel: risk.RiskLevelMediumLow,
+					Options: []risk.TierPathOption{{
+						Rank:               1,
+						Changes:            []risk.MetricChange{{Field: "chargeback_rate", Current: 1.2, Target: 0.49}},
+						ProjectedRiskLevel: risk.RiskLevelLow,
+					}},
+				}, nil
+			},
+		}
+
+		handler := NewRiskHandler(service)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/merchants/"+merchantID.String()+"/next-tier", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(merchantID.String())
+
+		if err := handler.GetNextTier(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", rec.Code)
+		}
+
+		var path risk.TierPath
+		if err := json.Unmarshal(rec.Body.Bytes(), &path); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(path.Options) != 1 || path.Options[0].ProjectedRiskLevel != risk.RiskLevelLow {
+			t.Errorf("unexpected options %+v", path.Options)
+		}
+	})
+
+	t.Run("invalid merchant ID", func(t *testing.T) {
+		handler := NewRiskHandler(&mockRiskService{})
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/merchants/invalid/next-tier", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("invalid-uuid")
+
+		if err := handler.GetNextTier(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestRiskHandler_ListReasonCodes(t *testing.T) {
+	service := &mockRiskService{
+		reasonCodes: func() []risk.ReasonCode {
+			return risk.ReasonCodeCatalog(risk.DefaultRuleset())
+		},
+	}
+
+	handler := NewRiskHandler(service)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/risk/reason-codes", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.ListReasonCodes(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+
+	var response struct {
+		ReasonCodes []risk.ReasonCode `json:"reason_codes"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	var found bool
+	for _, code := range response.ReasonCodes {
+		if code.Code == "CB_RATE_CRITICAL" {
+			found = code.Impact == "CRITICAL"
+		}
+	}
+	if !found {
+		t.Errorf("expected CB_RATE_CRITICAL in catalog, got %+v", response.ReasonCodes)
+	}
+}
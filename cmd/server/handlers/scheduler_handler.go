@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yuno-payments/papaya-payout-engine/internal/scheduler"
+)
+
+type Scheduler interface {
+	Status() scheduler.Status
+	Runs(ctx context.Context, limit, offset int) ([]scheduler.Run, int64, error)
+	Schedules(ctx context.Context, now time.Time) ([]scheduler.MerchantSchedule, error)
+	RunCycle(ctx context.Context) (*scheduler.Run, error)
+}
+
+type SchedulerHandler struct {
+	scheduler Scheduler
+}
+
+func NewSchedulerHandler(scheduler Scheduler) *SchedulerHandler {
+	return &SchedulerHandler{scheduler: scheduler}
+}
+
+func (h *SchedulerHandler) GetStatus(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.scheduler.Status())
+}
+
+func (h *SchedulerHandler) ListRuns(c echo.Context) error {
+	limit, offset := paginationParams(c)
+
+	runs, total, err := h.scheduler.Runs(c.Request().Context(), limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"runs":   runs,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ListSchedules returns each merchant's next evaluation time, most overdue
+// first. due=true limits the list to merchants already due.
+func (h *SchedulerHandler) ListSchedules(c echo.Context) error {
+	limit, offset := paginationParams(c)
+
+	schedules, err := h.scheduler.Schedules(c.Request().Context(), time.Now())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	if c.QueryParam("due") == "true" {
+		due := schedules[:0]
+		for _, schedule := range schedules {
+			if schedule.Due {
+				due = append(due, schedule)
+			}
+		}
+		schedules = due
+	}
+
+	total := len(schedules)
+	page := schedules[min(offset, total):min(offset+limit, total)]
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"merchants": page,
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// TriggerRun runs a cycle now. The cycle continues if the client disconnects.
+func (h *SchedulerHandler) TriggerRun(c echo.Context) error {
+	run, err := h.scheduler.RunCycle(context.WithoutCancel(c.Request().Context()))
+	if err != nil {
+		if errors.Is(err, scheduler.ErrCycleLocked) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		if run == nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, run)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+const defaultShadowWindow = 7 * 24 * time.Hour
+
+type ShadowReporter interface {
+	Challengers() []string
+	ShadowReports(ctx context.Context, challenger string, from, to time.Time) ([]risk.ShadowReport, error)
+}
+
+type ShadowHandler struct {
+	reporter ShadowReporter
+}
+
+func NewShadowHandler(reporter ShadowReporter) *ShadowHandler {
+	return &ShadowHandler{reporter: reporter}
+}
+
+// Report compares challengers with the champion over a window given by the
+// "from" and "to" query parameters (RFC 3339). The window defaults to the
+// last 7 days; "challenger" limits the report to one challenger.
+func (h *ShadowHandler) Report(c echo.Context) error {
+	to := time.Now()
+	if toStr := c.QueryParam("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "to must be an RFC 3339 timestamp"})
+		}
+		to = parsed
+	}
+
+	from := to.Add(-defaultShadowWindow)
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "from must be an RFC 3339 timestamp"})
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "from must be before to"})
+	}
+
+	reports, err := h.reporter.ShadowReports(c.Request().Context(), c.QueryParam("challenger"), from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"challengers": h.reporter.Challengers(),
+		"from":        from,
+		"to":          to,
+		"reports":     reports,
+	})
+}
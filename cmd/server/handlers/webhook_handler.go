@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/yuno-payments/papaya-payout-engine/internal/webhook"
+)
+
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, input webhook.SubscriptionInput) (*webhook.Subscription, error)
+	GetSubscription(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]webhook.Subscription, error)
+	UpdateSubscription(ctx context.Context, id uuid.UUID, input webhook.SubscriptionInput) (*webhook.Subscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]webhook.Delivery, int64, error)
+	RetryDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error)
+}
+
+type WebhookHandler struct {
+	webhooks WebhookService
+}
+
+func NewWebhookHandler(webhooks WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhooks: webhooks}
+}
+
+// CreatedSubscription is the only response that includes the signing secret.
+type CreatedSubscription struct {
+	*webhook.Subscription
+	Secret string `json:"secret"`
+}
+
+func (h *WebhookHandler) CreateSubscription(c echo.Context) error {
+	var req webhook.SubscriptionInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	sub, err := h.webhooks.CreateSubscription(c.Request().Context(), req)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, CreatedSubscription{Subscription: sub, Secret: sub.Secret})
+}
+
+func (h *WebhookHandler) ListSubscriptions(c echo.Context) error {
+	subs, err := h.webhooks.ListSubscriptions(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"subscriptions": subs,
+		"total":         len(subs),
+	})
+}
+
+func (h *WebhookHandler) GetSubscription(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid subscription ID"})
+	}
+
+	sub, err := h.webhooks.GetSubscription(c.Request().Context(), id)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, sub)
+}
+
+func (h *WebhookHandler) UpdateSubscription(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid subscription ID"})
+	}
+
+	var req webhook.SubscriptionInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	sub, err := h.webhooks.UpdateSubscription(c.Request().Context(), id, req)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, sub)
+}
+
+func (h *WebhookHandler) DeleteSubscription(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid subscription ID"})
+	}
+
+	if err := h.webhooks.DeleteSubscription(c.Request().Context(), id); err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListDeliveries returns the delivery log, newest first. Filters:
+// subscription_id and status.
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	limit, offset := paginationParams(c)
+	filter := webhook.DeliveryFilter{
+		Status: webhook.DeliveryStatus(strings.ToUpper(c.QueryParam("status"))),
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	if idStr := c.QueryParam("subscription_id"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid subscription ID"})
+		}
+		filter.SubscriptionID = &id
+	}
+
+	deliveries, total, err := h.webhooks.ListDeliveries(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"deliveries": deliveries,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
+func (h *WebhookHandler) RetryDelivery(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid delivery ID"})
+	}
+
+	delivery, err := h.webhooks.RetryDelivery(c.Request().Context(), id)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, delivery)
+}
+
+func webhookError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidSubscription):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, webhook.ErrSubscriptionNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "webhook subscription not found"})
+	case errors.Is(err, webhook.ErrDeliveryNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "webhook delivery not found"})
+	case errors.Is(err, webhook.ErrDeliveryNotDeadLetter):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
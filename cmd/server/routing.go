@@ -18,18 +18,77 @@ func setupRoutes(e *echo.Echo, h *Handlers) {
 	api.POST("/merchants", h.Merchant.Create)
 	api.GET("/merchants/:id", h.Merchant.Get)
 	api.GET("/merchants", h.Merchant.List)
+	api.PATCH("/merchants/:id/metrics", h.Merchant.UpdateMetrics)
 	api.POST("/merchants/seed", h.Merchant.Seed)
 
 	api.POST("/risk/evaluate", h.Risk.Evaluate)
 	api.POST("/risk/simulate", h.Risk.Simulate)
+	api.POST("/risk/simulate/portfolio", h.Portfolio.Simulate)
 	api.GET("/risk/merchants/:id/profile", h.Risk.GetProfile)
+	api.GET("/risk/merchants/:id/next-tier", h.Risk.GetNextTier)
+	api.GET("/risk/reason-codes", h.Risk.ListReasonCodes)
+
+	api.POST("/risk/merchants/:id/overrides", h.Override.Create)
+	api.GET("/risk/merchants/:id/overrides", h.Override.List)
+	api.DELETE("/risk/merchants/:id/overrides/:override_id", h.Override.Revoke)
 
 	api.POST("/risk/batch-evaluate", h.Batch.BatchEvaluate)
+
+	api.GET("/review/cases", h.Review.List)
+	api.GET("/review/cases/:id", h.Review.Get)
+	api.POST("/review/cases/:id/assign", h.Review.Assign)
+	api.POST("/review/cases/:id/status", h.Review.Transition)
+	api.POST("/review/cases/:id/comments", h.Review.AddComment)
+
+	api.GET("/scheduler/status", h.Scheduler.GetStatus)
+	api.GET("/scheduler/runs", h.Scheduler.ListRuns)
+	api.GET("/scheduler/merchants", h.Scheduler.ListSchedules)
+	api.POST("/scheduler/run", h.Scheduler.TriggerRun)
+
+	api.POST("/webhooks/subscriptions", h.Webhook.CreateSubscription)
+	api.GET("/webhooks/subscriptions", h.Webhook.ListSubscriptions)
+	api.GET("/webhooks/subscriptions/:id", h.Webhook.GetSubscription)
+	api.PATCH("/webhooks/subscriptions/:id", h.Webhook.UpdateSubscription)
+	api.DELETE("/webhooks/subscriptions/:id", h.Webhook.DeleteSubscription)
+	api.GET("/webhooks/deliveries", h.Webhook.ListDeliveries)
+	api.POST("/webhooks/deliveries/:id/retry", h.Webhook.RetryDelivery)
+
+	api.GET("/risk/models", h.Decision.ListModelVersions)
+	api.GET("/risk/challengers/report", h.Shadow.Report)
+	api.POST("/risk/backtests", h.Backtest.Create)
+	api.GET("/risk/backtests", h.Backtest.List)
+	api.GET("/risk/backtests/:id", h.Backtest.Get)
+	api.GET("/risk/backtests/:id/report", h.Backtest.Report)
+	api.GET("/risk/decisions", h.Decision.List)
+	api.GET("/risk/decisions/pending", h.Decision.ListPending)
+	api.POST("/risk/decisions/:id/approve", h.Decision.Approve)
+	api.POST("/risk/decisions/:id/reject", h.Decision.Reject)
+
+	api.POST("/policy/tier-tables", h.Policy.DraftTierTable)
+	api.GET("/policy/tier-tables", h.Policy.ListTierTables)
+	api.GET("/policy/tier-tables/active", h.Policy.GetActiveTierTable)
+	api.GET("/policy/tier-tables/:id", h.Policy.GetTierTable)
+	api.POST("/policy/tier-tables/:id/validate", h.Policy.ValidateTierTable)
+	api.POST("/policy/tier-tables/:id/activate", h.Policy.ActivateTierTable)
+
+	api.POST("/policy/overrides", h.Policy.CreateOverride)
+	api.GET("/policy/overrides", h.Policy.ListOverrides)
+	api.GET("/policy/overrides/:id", h.Policy.GetOverride)
+	api.DELETE("/policy/overrides/:id", h.Policy.DeactivateOverride)
 }
 
 type Handlers struct {
-	Health   *handlers.HealthHandler
-	Merchant *handlers.MerchantHandler
-	Risk     *handlers.RiskHandler
-	Batch    *handlers.BatchHandler
+	Health    *handlers.HealthHandler
+	Merchant  *handlers.MerchantHandler
+	Risk      *handlers.RiskHandler
+	Batch     *handlers.BatchHandler
+	Decision  *handlers.DecisionHandler
+	Policy    *handlers.PolicyHandler
+	Override  *handlers.OverrideHandler
+	Review    *handlers.ReviewHandler
+	Scheduler *handlers.SchedulerHandler
+	Webhook   *handlers.WebhookHandler
+	Shadow    *handlers.ShadowHandler
+	Backtest  *handlers.BacktestHandler
+	Portfolio *handlers.PortfolioHandler
 }
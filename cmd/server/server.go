@@ -3,24 +3,39 @@ package server
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/yuno-payments/papaya-payout-engine/cmd/server/handlers"
+	"github.com/yuno-payments/papaya-payout-engine/internal/backtest"
 	"github.com/yuno-payments/papaya-payout-engine/internal/health"
 	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+	"github.com/yuno-payments/papaya-payout-engine/internal/outbox"
 	"github.com/yuno-payments/papaya-payout-engine/internal/platform/config"
 	"github.com/yuno-payments/papaya-payout-engine/internal/platform/database"
+	"github.com/yuno-payments/papaya-payout-engine/internal/review"
 	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+	"github.com/yuno-payments/papaya-payout-engine/internal/scheduler"
 	"github.com/yuno-payments/papaya-payout-engine/internal/store"
+	"github.com/yuno-payments/papaya-payout-engine/internal/webhook"
 	"gorm.io/gorm"
 )
 
 type Server struct {
-	config   *config.Config
-	db       *gorm.DB
-	echo     *echo.Echo
-	handlers *Handlers
+	config        *config.Config
+	db            *gorm.DB
+	echo          *echo.Echo
+	handlers      *Handlers
+	scheduler     *scheduler.Scheduler
+	reevaluations *risk.ReevaluationQueue
+	relay         *outbox.Relay
+	dispatcher    *webhook.Dispatcher
+	backtests     *backtest.Service
+	closers       []io.Closer
 }
 
 func NewServer() (*Server, error) {
@@ -33,31 +48,257 @@ func NewServer() (*Server, error) {
 
 	merchantStore := store.NewMerchantStore(db)
 	decisionStore := store.NewDecisionStore(db)
+	tierTableStore := store.NewTierTableStore(db)
+	policyOverrideStore := store.NewPolicyOverrideStore(db)
+	metricSnapshotStore := store.NewMetricSnapshotStore(db)
+	manualOverrideStore := store.NewManualOverrideStore(db)
+	reviewStore := store.NewReviewStore(db)
+	schedulerStore := store.NewSchedulerStore(db)
+	outboxStore := store.NewOutboxStore(db)
+	webhookStore := store.NewWebhookStore(db)
+	shadowStore := store.NewShadowStore(db)
+	backtestStore := store.NewBacktestStore(db)
 
-	merchantService := merchant.NewService(merchantStore)
-	riskService := risk.NewService(merchantStore, decisionStore)
+	evaluator, err := newEvaluator(&cfg.Risk)
+	if err != nil {
+		return nil, err
+	}
+
+	scorer, err := newScorer(&cfg.Risk, evaluator)
+	if err != nil {
+		return nil, err
+	}
+
+	challengers, err := newChallengers(&cfg.Risk, scorer.Version(), evaluator.Ruleset())
+	if err != nil {
+		return nil, err
+	}
+
+	approval := risk.ApprovalPolicy{
+		MinRiskLevel:             risk.RiskLevel(cfg.Risk.ApprovalLevel),
+		DefaultHoldPeriod:        risk.HoldPeriod(cfg.Risk.ApprovalDefaultHold),
+		DefaultReservePercentage: cfg.Risk.ApprovalDefaultReserve,
+		Approvers:                cfg.Risk.Approvers,
+	}
+	if err := approval.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid approval policy: %w", err)
+	}
+
+	reviewService := review.NewService(reviewStore, review.SLA{
+		risk.RiskLevelHigh:     time.Duration(cfg.Review.HighSLAHours) * time.Hour,
+		risk.RiskLevelCritical: time.Duration(cfg.Review.CriticalSLAHours) * time.Hour,
+	})
+	riskService := risk.NewService(merchantStore, decisionStore,
+		risk.WithEvaluator(evaluator),
+		risk.WithScorer(scorer),
+		risk.WithTierTables(tierTableStore),
+		risk.WithPolicyOverrides(policyOverrideStore),
+		risk.WithMetricHistory(metricSnapshotStore),
+		risk.WithManualOverrides(manualOverrideStore),
+		risk.WithReviewQueue(reviewService),
+		risk.WithApproval(approval, decisionStore),
+		risk.WithChallengers(shadowStore, challengers...),
+		risk.WithPortfolio(merchantStore),
+		risk.WithHysteresis(risk.Hysteresis{
+			Margin:                 cfg.Risk.HysteresisMargin,
+			ConsecutiveEvaluations: cfg.Risk.HysteresisEvaluations,
+		}),
+	)
+	reevaluations := risk.NewReevaluationQueue(riskService, cfg.Risk.ReevaluationQueueSize, cfg.Risk.ReevaluationWorkers)
+	merchantService := merchant.NewService(merchantStore, reevaluations)
+	tierTableService := risk.NewTierTableService(tierTableStore)
+	policyOverrideService := risk.NewPolicyOverrideService(policyOverrideStore)
+	manualOverrideService := risk.NewManualOverrideService(manualOverrideStore)
+	backtestService := backtest.NewService(backtestStore, decisionStore, riskService)
 	healthService := health.NewService(db)
 
+	cadence, err := scheduler.ParseCadence(cfg.Scheduler.Cadence)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduler cadence: %w", err)
+	}
+	reevaluator := scheduler.New(riskService, schedulerStore, schedulerStore, scheduler.Config{
+		Cadence:    cadence,
+		Interval:   time.Duration(cfg.Scheduler.IntervalMinutes) * time.Minute,
+		BatchSize:  cfg.Scheduler.BatchSize,
+		InstanceID: instanceID(),
+	})
+
+	webhookService := webhook.NewService(webhookStore)
+	dispatcher := webhook.NewDispatcher(webhookStore, webhook.DispatcherConfig{
+		Interval:    time.Duration(cfg.Webhook.IntervalSeconds) * time.Second,
+		MaxAttempts: cfg.Webhook.MaxAttempts,
+		Timeout:     time.Duration(cfg.Webhook.TimeoutSeconds) * time.Second,
+	})
+
+	// Webhooks always receive outbox events; the configured sink is optional.
+	sink, closers, err := newPublisher(&cfg.Outbox)
+	if err != nil {
+		return nil, err
+	}
+	publishers := outbox.MultiPublisher{webhookService}
+	if sink != nil {
+		publishers = append(publishers, sink)
+	}
+	relay := outbox.NewRelay(outboxStore, publishers, outbox.RelayConfig{
+		Interval:  time.Duration(cfg.Outbox.IntervalSeconds) * time.Second,
+		BatchSize: cfg.Outbox.BatchSize,
+	})
+
 	h := &Handlers{
-		Health:   handlers.NewHealthHandler(healthService),
-		Merchant: handlers.NewMerchantHandler(merchantService),
-		Risk:     handlers.NewRiskHandler(riskService),
-		Batch:    handlers.NewBatchHandler(riskService, merchantStore),
+		Health:    handlers.NewHealthHandler(healthService),
+		Merchant:  handlers.NewMerchantHandler(merchantService),
+		Risk:      handlers.NewRiskHandler(riskService),
+		Batch:     handlers.NewBatchHandler(riskService, merchantStore),
+		Decision:  handlers.NewDecisionHandler(decisionStore, riskService, riskService.ModelVersion()),
+		Policy:    handlers.NewPolicyHandler(tierTableService, policyOverrideService),
+		Override:  handlers.NewOverrideHandler(manualOverrideService),
+		Review:    handlers.NewReviewHandler(reviewService),
+		Scheduler: handlers.NewSchedulerHandler(reevaluator),
+		Webhook:   handlers.NewWebhookHandler(webhookService),
+		Shadow:    handlers.NewShadowHandler(riskService),
+		Backtest:  handlers.NewBacktestHandler(backtestService),
+		Portfolio: handlers.NewPortfolioHandler(riskService),
 	}
 
 	e := echo.New()
 	setupRoutes(e, h)
 
 	return &Server{
-		config:   cfg,
-		db:       db,
-		echo:     e,
-		handlers: h,
+		config:        cfg,
+		db:            db,
+		echo:          e,
+		handlers:      h,
+		scheduler:     reevaluator,
+		reevaluations: reevaluations,
+		relay:         relay,
+		dispatcher:    dispatcher,
+		backtests:     backtestService,
+		closers:       closers,
 	}, nil
 }
 
+// newPublisher builds the outbox sink from config. It returns nil when the
+// sink is disabled, along with anything to close on shutdown.
+func newPublisher(cfg *config.OutboxConfig) (outbox.Publisher, []io.Closer, error) {
+	switch cfg.Publisher {
+	case outbox.PublisherNone:
+		log.Println("Outbox sink disabled; events go to webhooks only")
+		return nil, nil, nil
+	case "", outbox.PublisherStdout:
+		return outbox.NewWriterPublisher(os.Stdout), nil, nil
+	case outbox.PublisherFile:
+		publisher, f, err := outbox.NewFilePublisher(cfg.FilePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		log.Printf("Publishing outbox events to %s", cfg.FilePath)
+		return publisher, []io.Closer{f}, nil
+	case outbox.PublisherHTTP:
+		if cfg.HTTPURL == "" {
+			return nil, nil, fmt.Errorf("OUTBOX_HTTP_URL is required for the %s publisher", outbox.PublisherHTTP)
+		}
+		log.Printf("Publishing outbox events to %s", cfg.HTTPURL)
+		return outbox.NewHTTPPublisher(cfg.HTTPURL, time.Duration(cfg.HTTPTimeoutSeconds)*time.Second), nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown outbox publisher %q", cfg.Publisher)
+	}
+}
+
+// instanceID identifies this process in scheduler run history.
+func instanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+func newEvaluator(cfg *config.RiskConfig) (*risk.Evaluator, error) {
+	if cfg.RulesetPath == "" {
+		log.Println("Using built-in risk scoring ruleset")
+		return risk.NewEvaluator(), nil
+	}
+
+	rs, err := risk.LoadRuleset(cfg.RulesetPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load risk ruleset: %w", err)
+	}
+
+	log.Printf("Loaded risk scoring ruleset from %s", cfg.RulesetPath)
+	return risk.NewEvaluatorFromRuleset(rs), nil
+}
+
+// newScorer picks the scoring model from config. The additive scorer is the
+// evaluator itself; the logistic scorer loads its coefficients from ModelPath.
+func newScorer(cfg *config.RiskConfig, evaluator *risk.Evaluator) (risk.Scorer, error) {
+	switch cfg.Scorer {
+	case "", risk.ScorerAdditive:
+		return evaluator, nil
+	case risk.ScorerLogistic:
+		if cfg.ModelPath == "" {
+			return nil, fmt.Errorf("RISK_MODEL_PATH is required for the %s scorer", risk.ScorerLogistic)
+		}
+		model, err := risk.LoadLogisticModel(cfg.ModelPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load risk model: %w", err)
+		}
+		log.Printf("Loaded logistic risk model %s from %s", model.Version, cfg.ModelPath)
+		return risk.NewLogisticScorerFromRuleset(model, evaluator.Ruleset()), nil
+	default:
+		return nil, fmt.Errorf("unknown risk scorer %q", cfg.Scorer)
+	}
+}
+
+// newChallengers loads the shadow challengers from config. Each is named by
+// its model version, which must differ from the champion's and from every
+// other challenger's. Logistic challengers attribute against rules, the
+// champion's ruleset, as the champion's decisions are explained against it.
+func newChallengers(cfg *config.RiskConfig, champion string, rules *risk.Ruleset) ([]risk.Challenger, error) {
+	challengers := make([]risk.Challenger, 0, len(cfg.Challengers))
+	seen := map[string]bool{champion: true}
+	for _, spec := range cfg.Challengers {
+		kind, path, ok := strings.Cut(spec, ":")
+		if !ok || path == "" {
+			return nil, fmt.Errorf("invalid risk challenger %q: expected ruleset:<path> or logistic:<path>", spec)
+		}
+
+		var challenger risk.Challenger
+		switch kind {
+		case "ruleset":
+			rs, err := risk.LoadRuleset(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load challenger ruleset: %w", err)
+			}
+			challenger = risk.NewRulesetChallenger(rs)
+		case risk.ScorerLogistic:
+			model, err := risk.LoadLogisticModel(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load challenger model: %w", err)
+			}
+			challenger = risk.Challenger{Scorer: risk.NewLogisticScorerFromRuleset(model, rules)}
+		default:
+			return nil, fmt.Errorf("unknown risk challenger kind %q", kind)
+		}
+
+		if seen[challenger.Name()] {
+			return nil, fmt.Errorf("risk challenger %s duplicates the version of the champion or another challenger", challenger.Name())
+		}
+		seen[challenger.Name()] = true
+		log.Printf("Scoring challenger %s in shadow (loaded from %s)", challenger.Name(), path)
+		challengers = append(challengers, challenger)
+	}
+	return challengers, nil
+}
+
 func (s *Server) Start() error {
 	addr := fmt.Sprintf(":%s", s.config.Port)
+	s.reevaluations.Start()
+	s.relay.Start()
+	s.dispatcher.Start()
+	s.backtests.Start()
+	if s.config.Scheduler.Enabled {
+		s.scheduler.Start()
+	}
 	log.Printf("Starting server on %s", addr)
 	return s.echo.Start(addr)
 }
@@ -68,6 +309,24 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		return fmt.Errorf("failed to shutdown server: %w", err)
 	}
 
+	log.Println("Stopping scheduler...")
+	s.scheduler.Stop()
+
+	log.Println("Draining re-evaluation queue...")
+	s.reevaluations.Stop()
+
+	log.Println("Cancelling running backtests...")
+	s.backtests.Stop()
+
+	log.Println("Stopping outbox relay and webhook dispatcher...")
+	s.relay.Stop()
+	s.dispatcher.Stop()
+	for _, c := range s.closers {
+		if err := c.Close(); err != nil {
+			log.Printf("[WARN] Failed to close outbox sink: %v", err)
+		}
+	}
+
 	log.Println("Closing database connections...")
 	sqlDB, err := s.db.DB()
 	if err != nil {
@@ -0,0 +1,58 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+type Status string
+
+const (
+	StatusRunning   Status = "RUNNING"
+	StatusCompleted Status = "COMPLETED"
+	StatusFailed    Status = "FAILED"
+)
+
+// Job is one backtest: a candidate configuration replayed against the
+// decisions evaluated in [From, To). Its per-decision rows are kept
+// separately, as Rows, and only read for the report. The instance running
+// the job refreshes HeartbeatAt until it finishes.
+type Job struct {
+	ID               uuid.UUID              `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Status           Status                 `json:"status" gorm:"not null;default:'RUNNING'"`
+	From             time.Time              `json:"from" gorm:"column:window_start;not null"`
+	To               time.Time              `json:"to" gorm:"column:window_end;not null"`
+	Candidate        risk.BacktestCandidate `json:"candidate" gorm:"type:jsonb;not null"`
+	CandidateVersion string                 `json:"candidate_version" gorm:"not null"`
+	RequestedBy      string                 `json:"requested_by,omitempty" gorm:"not null;default:''"`
+	Summary          *risk.BacktestSummary  `json:"summary,omitempty" gorm:"type:jsonb"`
+	Error            string                 `json:"error,omitempty" gorm:"not null;default:''"`
+	CreatedAt        time.Time              `json:"created_at" gorm:"not null;default:now()"`
+	HeartbeatAt      time.Time              `json:"heartbeat_at" gorm:"not null;default:now()"`
+	FinishedAt       *time.Time             `json:"finished_at,omitempty"`
+}
+
+func (Job) TableName() string {
+	return "backtests"
+}
+
+// Row is one replayed decision of a backtest. Position orders the rows as
+// they were replayed.
+type Row struct {
+	BacktestID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Position   int       `gorm:"primaryKey"`
+	risk.BacktestRow
+}
+
+func (Row) TableName() string {
+	return "backtest_rows"
+}
+
+// Request starts a backtest of the embedded candidate over [From, To).
+type Request struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	risk.BacktestCandidate
+}
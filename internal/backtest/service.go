@@ -0,0 +1,405 @@
+package backtest
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+const (
+	defaultPageSize = 500
+
+	// heartbeatInterval is how often a running job's heartbeat is refreshed
+	// and stale jobs are swept. A job whose heartbeat is staleAfter old
+	// belongs to an instance that stopped without finishing it.
+	heartbeatInterval = 30 * time.Second
+	staleAfter        = 5 * time.Minute
+)
+
+var (
+	ErrInvalidRequest  = errors.New("invalid backtest request")
+	ErrJobNotFound     = errors.New("backtest not found")
+	ErrJobNotCompleted = errors.New("backtest has not completed")
+	ErrJobNotRunning   = errors.New("backtest is no longer running")
+)
+
+type Repository interface {
+	Create(ctx context.Context, job *Job) error
+	// Get returns the job without its rows.
+	Get(ctx context.Context, id uuid.UUID) (*Job, error)
+	List(ctx context.Context, limit, offset int) ([]Job, int64, error)
+	// AppendRows stores rows as the job's rows from position onwards.
+	AppendRows(ctx context.Context, id uuid.UUID, position int, rows []risk.BacktestRow) error
+	// ListRows returns up to limit of the job's rows from position onwards,
+	// in the order they were appended.
+	ListRows(ctx context.Context, id uuid.UUID, position, limit int) ([]risk.BacktestRow, error)
+	// Heartbeat refreshes a running job's heartbeat. It returns
+	// ErrJobNotRunning once the job has finished or been failed as stale.
+	Heartbeat(ctx context.Context, id uuid.UUID) error
+	// Finish records the outcome of a running job, discarding its rows if it
+	// failed. It returns ErrJobNotRunning if the job is no longer running.
+	Finish(ctx context.Context, job *Job) error
+	// FailStale fails the running jobs whose heartbeat is older than before,
+	// discarding their rows, and returns how many it failed.
+	FailStale(ctx context.Context, before time.Time, reason string) (int64, error)
+}
+
+// DecisionSource pages through persisted, non-rejected decisions evaluated
+// in [from, to), oldest first.
+type DecisionSource interface {
+	ListForBacktest(ctx context.Context, from, to time.Time, limit, offset int) ([]risk.RiskDecision, error)
+}
+
+type Replayer interface {
+	NewReplay(ctx context.Context, candidate risk.BacktestCandidate) (*risk.Replay, error)
+}
+
+// Service runs backtests in the background and keeps their reports.
+type Service struct {
+	store     Repository
+	decisions DecisionSource
+	replayer  Replayer
+	pageSize  int
+
+	heartbeatInterval time.Duration
+	staleAfter        time.Duration
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started sync.Once
+}
+
+func NewService(store Repository, decisions DecisionSource, replayer Replayer) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		store:     store,
+		decisions: decisions,
+		replayer:  replayer,
+		pageSize:  defaultPageSize,
+
+		heartbeatInterval: heartbeatInterval,
+		staleAfter:        staleAfter,
+
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start fails backtests left RUNNING by instances that stopped without
+// finishing them, now and then every heartbeat interval until Stop.
+func (s *Service) Start() {
+	s.started.Do(func() {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.sweep()
+		}()
+		log.Printf("[INFO] Backtest sweeper started (stale after %s)", s.staleAfter)
+	})
+}
+
+func (s *Service) sweep() {
+	ticker := time.NewTicker(s.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		failed, err := s.store.FailStale(s.ctx, time.Now().Add(-s.staleAfter), "interrupted: the instance running it stopped")
+		if err != nil && s.ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to sweep stale backtests: %v", err)
+		} else if failed > 0 {
+			log.Printf("[WARN] Failed %d stale backtests with no heartbeat for %s", failed, s.staleAfter)
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Submit validates the request, records a RUNNING job and replays it in the
+// background.
+func (s *Service) Submit(ctx context.Context, req Request, requestedBy string) (*Job, error) {
+	if req.From.IsZero() || req.To.IsZero() || !req.From.Before(req.To) {
+		return nil, fmt.Errorf("%w: from and to are required and from must be before to", ErrInvalidRequest)
+	}
+
+	replay, err := s.replayer.NewReplay(ctx, req.BacktestCandidate)
+	if err != nil {
+		if errors.Is(err, risk.ErrInvalidBacktest) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &Job{
+		Status:           StatusRunning,
+		From:             req.From,
+		To:               req.To,
+		Candidate:        req.BacktestCandidate,
+		CandidateVersion: replay.Version(),
+		RequestedBy:      requestedBy,
+		CreatedAt:        now,
+		HeartbeatAt:      now,
+	}
+	if err := s.store.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create backtest: %w", err)
+	}
+
+	log.Printf("[INFO] Backtest %s started: candidate %s over %s to %s",
+		job.ID, job.CandidateVersion, job.From.Format(time.RFC3339), job.To.Format(time.RFC3339))
+
+	// The run records its outcome on its own copy, not on the job returned.
+	running := *job
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(&running, replay)
+	}()
+
+	return job, nil
+}
+
+// Stop cancels running backtests, which are recorded as failed, stops the
+// sweeper and waits for both to finish.
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Service) run(job *Job, replay *risk.Replay) {
+	ctx, cancel := context.WithCancelCause(s.ctx)
+	defer cancel(nil)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.heartbeat(ctx, cancel, job.ID)
+	}()
+
+	position := 0
+	write := func(rows []risk.BacktestRow) error {
+		if err := s.store.AppendRows(ctx, job.ID, position, rows); err != nil {
+			return fmt.Errorf("failed to store backtest rows: %w", err)
+		}
+		position += len(rows)
+		return nil
+	}
+	summary, err := Run(ctx, s.decisions, replay, job.From, job.To, s.pageSize, write)
+	if cause := context.Cause(ctx); errors.Is(cause, ErrJobNotRunning) {
+		log.Printf("[WARN] Backtest %s abandoned: %v", job.ID, cause)
+		return
+	}
+
+	finished := time.Now()
+	job.FinishedAt = &finished
+	if err != nil {
+		job.Status = StatusFailed
+		job.Error = err.Error()
+		log.Printf("[ERROR] Backtest %s failed: %v", job.ID, err)
+	} else {
+		job.Status = StatusCompleted
+		job.Summary = summary
+		log.Printf("[INFO] Backtest %s completed: %d decisions replayed, %d tier changes, %d skipped without inputs",
+			job.ID, summary.Decisions, summary.TierChanges.Count, summary.SkippedWithoutInputs)
+	}
+
+	// Record the outcome even when the run was cancelled by shutdown.
+	if err := s.store.Finish(context.WithoutCancel(s.ctx), job); err != nil {
+		log.Printf("[ERROR] Failed to record backtest %s: %v", job.ID, err)
+	}
+}
+
+// heartbeat refreshes the job's heartbeat until ctx is done, and cancels the
+// run if the job was failed as stale in the meantime.
+func (s *Service) heartbeat(ctx context.Context, cancel context.CancelCauseFunc, id uuid.UUID) {
+	ticker := time.NewTicker(s.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := s.store.Heartbeat(ctx, id)
+		if errors.Is(err, ErrJobNotRunning) {
+			cancel(err)
+			return
+		}
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[WARN] Failed to record heartbeat for backtest %s: %v", id, err)
+		}
+	}
+}
+
+// Run replays every decision in [from, to) under replay, pageSize decisions
+// at a time, and passes each page's rows to write so they never all sit in
+// memory. Decisions recorded without inputs are counted and skipped.
+func Run(ctx context.Context, decisions DecisionSource, replay *risk.Replay, from, to time.Time, pageSize int,
+	write func([]risk.BacktestRow) error) (*risk.BacktestSummary, error) {
+	summary := risk.NewBacktestSummary()
+	rows := make([]risk.BacktestRow, 0, pageSize)
+
+	for offset := 0; ; offset += pageSize {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("backtest cancelled: %w", err)
+		}
+
+		page, err := decisions.ListForBacktest(ctx, from, to, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list decisions: %w", err)
+		}
+
+		rows = rows[:0]
+		for i := range page {
+			row, err := replay.Replay(ctx, &page[i])
+			if errors.Is(err, risk.ErrNoDecisionInputs) {
+				summary.SkippedWithoutInputs++
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			summary.Add(row)
+			rows = append(rows, *row)
+		}
+		if len(rows) > 0 {
+			if err := write(rows); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(page) < pageSize {
+			return summary, nil
+		}
+	}
+}
+
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*Job, error) {
+	return s.store.Get(ctx, id)
+}
+
+func (s *Service) List(ctx context.Context, limit, offset int) ([]Job, int64, error) {
+	return s.store.List(ctx, limit, offset)
+}
+
+// Report returns a completed job whose rows are read as the report is
+// written.
+func (s *Service) Report(ctx context.Context, id uuid.UUID) (*Report, error) {
+	job, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != StatusCompleted {
+		return nil, fmt.Errorf("%w: %s is %s", ErrJobNotCompleted, id, job.Status)
+	}
+	return &Report{Job: job, store: s.store, pageSize: s.pageSize}, nil
+}
+
+// Report is a completed job. Its rows are streamed from the store a page at
+// a time by WriteCSV and WriteJSON.
+type Report struct {
+	*Job
+	store    Repository
+	pageSize int
+}
+
+func (r *Report) eachRow(ctx context.Context, fn func(*risk.BacktestRow) error) error {
+	for position := 0; ; position += r.pageSize {
+		rows, err := r.store.ListRows(ctx, r.ID, position, r.pageSize)
+		if err != nil {
+			return err
+		}
+		for i := range rows {
+			if err := fn(&rows[i]); err != nil {
+				return err
+			}
+		}
+		if len(rows) < r.pageSize {
+			return nil
+		}
+	}
+}
+
+var csvHeader = []string{
+	"decision_id", "merchant_id", "evaluated_at", "transaction_volume_30d",
+	"baseline_score", "candidate_score",
+	"baseline_risk_level", "candidate_risk_level",
+	"baseline_hold_period", "candidate_hold_period",
+	"baseline_reserve_percentage", "candidate_reserve_percentage",
+}
+
+// WriteCSV writes the report's rows, one per replayed decision.
+func (r *Report) WriteCSV(ctx context.Context, w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(csvHeader); err != nil {
+		return err
+	}
+	err := r.eachRow(ctx, func(row *risk.BacktestRow) error {
+		return out.Write([]string{
+			row.DecisionID.String(),
+			row.MerchantID.String(),
+			row.EvaluatedAt.Format(time.RFC3339),
+			row.TransactionVolume30d.StringFixed(2),
+			strconv.Itoa(row.BaselineScore),
+			strconv.Itoa(row.CandidateScore),
+			string(row.BaselineRiskLevel),
+			string(row.CandidateRiskLevel),
+			string(row.BaselineHoldPeriod),
+			string(row.CandidateHoldPeriod),
+			strconv.Itoa(row.BaselineReservePercentage),
+			strconv.Itoa(row.CandidateReservePercentage),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteJSON writes the job as a JSON object with its rows under "rows".
+func (r *Report) WriteJSON(ctx context.Context, w io.Writer) error {
+	job, err := json.Marshal(r.Job)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(w)
+	// Reopen the job object to append the rows to it.
+	out.Write(job[:len(job)-1])
+	out.WriteString(`,"rows":[`)
+	first := true
+	err = r.eachRow(ctx, func(row *risk.BacktestRow) error {
+		if !first {
+			out.WriteByte(',')
+		}
+		first = false
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	out.WriteString("]}\n")
+	return out.Flush()
+}
@@ -0,0 +1,394 @@
+package backtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+type mockDecisionSource struct {
+	decisions []risk.RiskDecision
+	calls     int
+}
+
+func (m *mockDecisionSource) ListForBacktest(ctx context.Context, from, to time.Time, limit, offset int) ([]risk.RiskDecision, error) {
+	m.calls++
+	if offset >= len(m.decisions) {
+		return nil, nil
+	}
+	return m.decisions[offset:min(offset+limit, len(m.decisions))], nil
+}
+
+type mockRepository struct {
+	jobs     map[uuid.UUID]*Job
+	rows     map[uuid.UUID][]risk.BacktestRow
+	finished chan *Job
+
+	heartbeat  func(id uuid.UUID) error
+	failStale  func(before time.Time, reason string) (int64, error)
+	heartbeats chan uuid.UUID
+}
+
+func newMockRepository() *mockRepository {
+	return &mockRepository{jobs: make(map[uuid.UUID]*Job), rows: make(map[uuid.UUID][]risk.BacktestRow),
+		finished: make(chan *Job, 1)}
+}
+
+func (m *mockRepository) Create(ctx context.Context, job *Job) error {
+	job.ID = uuid.New()
+	stored := *job
+	m.jobs[job.ID] = &stored
+	return nil
+}
+
+func (m *mockRepository) Get(ctx context.Context, id uuid.UUID) (*Job, error) {
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+func (m *mockRepository) AppendRows(ctx context.Context, id uuid.UUID, position int, rows []risk.BacktestRow) error {
+	if position != len(m.rows[id]) {
+		return errors.New("rows appended out of order")
+	}
+	m.rows[id] = append(m.rows[id], rows...)
+	return nil
+}
+
+func (m *mockRepository) ListRows(ctx context.Context, id uuid.UUID, position, limit int) ([]risk.BacktestRow, error) {
+	rows := m.rows[id]
+	if position >= len(rows) {
+		return nil, nil
+	}
+	return rows[position:min(position+limit, len(rows))], nil
+}
+
+func (m *mockRepository) List(ctx context.Context, limit, offset int) ([]Job, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *mockRepository) Heartbeat(ctx context.Context, id uuid.UUID) error {
+	if m.heartbeat == nil {
+		return nil
+	}
+	return m.heartbeat(id)
+}
+
+func (m *mockRepository) Finish(ctx context.Context, job *Job) error {
+	m.finished <- job
+	return nil
+}
+
+func (m *mockRepository) FailStale(ctx context.Context, before time.Time, reason string) (int64, error) {
+	if m.failStale == nil {
+		return 0, nil
+	}
+	return m.failStale(before, reason)
+}
+
+// blockingDecisionSource blocks until the backtest is cancelled.
+type blockingDecisionSource struct{}
+
+func (blockingDecisionSource) ListForBacktest(ctx context.Context, from, to time.Time, limit, offset int) ([]risk.RiskDecision, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// recorded returns a decision for inputs as the built-in configuration
+// scored it.
+func recorded(t *testing.T, in risk.DecisionInputs, evaluatedAt time.Time) risk.RiskDecision {
+	t.Helper()
+	evaluator := risk.NewEvaluator()
+	policy := risk.NewPolicyMapper()
+	m := in.Merchant(uuid.New())
+
+	score, _ := evaluator.Score(m)
+	tier, _ := risk.ApplyHardStops(evaluator.Ruleset().HardStops, m, policy, policy.DeterminePolicyTier(score))
+	return risk.RiskDecision{
+		ID:                       uuid.New(),
+		MerchantID:               m.ID,
+		RiskScore:                score,
+		RiskLevel:                tier.RiskLevel,
+		PayoutHoldPeriod:         tier.HoldPeriod,
+		RollingReservePercentage: tier.ReservePercentage,
+		Inputs:                   &in,
+		EvaluatedAt:              evaluatedAt,
+	}
+}
+
+func testDecisions(t *testing.T) []risk.RiskDecision {
+	at := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	low := recorded(t, risk.DecisionInputs{Industry: "RETAIL", AccountAgeDays: 800, KYCVerified: true, KYCLevel: "ENHANCED",
+		TransactionVolume30d: decimal.NewFromInt(100000), ChargebackRate: decimal.NewFromFloat(0.3),
+		VelocityMultiplier: decimal.NewFromFloat(1.0), RefundRate: decimal.NewFromFloat(1.0)}, at)
+	mediumLow := recorded(t, risk.DecisionInputs{Industry: "RETAIL", AccountAgeDays: 200, KYCVerified: true, KYCLevel: "FULL",
+		TransactionVolume30d: decimal.NewFromInt(50000), ChargebackRate: decimal.NewFromFloat(0.6),
+		VelocityMultiplier: decimal.NewFromFloat(1.2), RefundRate: decimal.NewFromFloat(2.0)}, at)
+	high := recorded(t, risk.DecisionInputs{Industry: "TRAVEL", AccountAgeDays: 60, KYCVerified: true, KYCLevel: "BASIC",
+		TransactionVolume30d: decimal.NewFromInt(25000), ChargebackRate: decimal.NewFromFloat(1.0),
+		VelocityMultiplier: decimal.NewFromFloat(2.0), RefundRate: decimal.NewFromFloat(4.0)}, at)
+	legacy := risk.RiskDecision{ID: uuid.New(), MerchantID: uuid.New(), RiskLevel: risk.RiskLevelLow, EvaluatedAt: at}
+
+	if low.RiskLevel != risk.RiskLevelLow || low.RiskScore < 4 || mediumLow.RiskLevel != risk.RiskLevelMediumLow ||
+		mediumLow.RiskScore < 26 || high.RiskLevel != risk.RiskLevelHigh {
+		t.Fatalf("unexpected baseline decisions: %d %s, %d %s, %d %s", low.RiskScore, low.RiskLevel,
+			mediumLow.RiskScore, mediumLow.RiskLevel, high.RiskScore, high.RiskLevel)
+	}
+	return []risk.RiskDecision{low, mediumLow, legacy, high}
+}
+
+// stricterTiers narrows LOW and MEDIUM_LOW so both test merchants move up one
+// tier.
+func stricterTiers() []risk.PolicyTier {
+	tiers := risk.DefaultPolicyTiers()
+	tiers[0].MaxScore = 3
+	tiers[1].MinScore, tiers[1].MaxScore = 4, 25
+	tiers[2].MinScore = 26
+	return tiers
+}
+
+func TestRun(t *testing.T) {
+	source := &mockDecisionSource{decisions: testDecisions(t)}
+	replay, err := risk.NewService(nil, nil).NewReplay(context.Background(), risk.BacktestCandidate{Tiers: stricterTiers()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var rows []risk.BacktestRow
+	writes := 0
+	summary, err := Run(context.Background(), source, replay, time.Time{}, time.Now(), 2, func(page []risk.BacktestRow) error {
+		writes++
+		rows = append(rows, page...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if source.calls != 3 || writes != 2 {
+		t.Errorf("expected 3 pages of 2 with rows written for 2, got %d calls and %d writes", source.calls, writes)
+	}
+	if summary.Decisions != 3 || summary.SkippedWithoutInputs != 1 || len(rows) != 3 {
+		t.Fatalf("expected 3 replayed and 1 skipped, got %d, %d (%d rows)",
+			summary.Decisions, summary.SkippedWithoutInputs, len(rows))
+	}
+
+	if rows[0].CandidateRiskLevel != risk.RiskLevelMediumLow || rows[0].CandidateHoldPeriod != risk.HoldPeriod7Days {
+		t.Errorf("expected LOW merchant to move to MEDIUM_LOW, got %+v", rows[0])
+	}
+	if rows[1].CandidateRiskLevel != risk.RiskLevelMedium || rows[1].CandidateReservePercentage != 10 {
+		t.Errorf("expected MEDIUM_LOW merchant to move to MEDIUM, got %+v", rows[1])
+	}
+
+	tiers := summary.TierChanges
+	if tiers.Count != 2 || tiers.Increased != 2 || !tiers.Volume.Equal(decimal.NewFromInt(150000)) {
+		t.Errorf("expected 2 tier increases on 150000 volume, got %+v", tiers)
+	}
+	if share := tiers.VolumeShare; share < 0.857 || share > 0.858 {
+		t.Errorf("expected 150000/175000 of volume to change tier, got %v", share)
+	}
+	if summary.HoldChanges.Increased != 2 || summary.ReserveChanges.Count != 1 ||
+		!summary.ReserveChanges.Volume.Equal(decimal.NewFromInt(50000)) {
+		t.Errorf("unexpected hold or reserve changes: %+v, %+v", summary.HoldChanges, summary.ReserveChanges)
+	}
+	if !summary.BaselineReserve.Equal(decimal.NewFromInt(5000)) || !summary.ReserveDelta.Equal(decimal.NewFromInt(5000)) {
+		t.Errorf("expected reserve 5000 -> 10000, got %s (delta %s)", summary.BaselineReserve, summary.ReserveDelta)
+	}
+	if summary.TierMigration.Counts[0][1] != 1 || summary.TierMigration.Counts[1][2] != 1 || summary.TierMigration.Counts[3][3] != 1 {
+		t.Errorf("unexpected tier migration matrix %v", summary.TierMigration.Counts)
+	}
+}
+
+func TestRunNoOpCandidate(t *testing.T) {
+	decisions := testDecisions(t)
+
+	// The recorded outcomes differ from a plain replay: hysteresis held one
+	// merchant in HIGH, trend points raised another's score, an analyst pinned
+	// a third, and a market override that has since ended lengthened the
+	// fourth's hold.
+	held := &decisions[0]
+	held.RiskScore, held.RiskLevel, held.PayoutHoldPeriod = 65, risk.RiskLevelHigh, risk.HoldPeriod14Days
+	held.Reasoning.Hysteresis = &risk.HysteresisHold{ScoredRiskLevel: risk.RiskLevelLow, HeldRiskLevel: risk.RiskLevelHigh}
+	trended := &decisions[1]
+	trended.RiskScore += 10
+	trended.Reasoning.PrimaryFactors = []risk.FactorExplanation{{Factor: "Trend: CHARGEBACK_RISING", Score: 10}}
+	pinned := &decisions[3]
+	pinned.PayoutHoldPeriod, pinned.RollingReservePercentage = risk.HoldPeriodImmediate, 0
+	pinned.Reasoning.ManualOverride = &risk.AppliedManualOverride{HoldPeriod: risk.HoldPeriodImmediate}
+	adjusted := recorded(t, *decisions[0].Inputs, decisions[0].EvaluatedAt)
+	adjusted.PayoutHoldPeriod = risk.HoldPeriod7Days
+	adjusted.Reasoning.PolicyOverride = &risk.AppliedPolicyOverride{ID: uuid.New(), Name: "retail",
+		Mode: risk.PolicyOverrideModeAdjust, HoldAdjustmentSteps: 1}
+	decisions = append(decisions, adjusted)
+
+	replay, err := risk.NewService(nil, nil).NewReplay(context.Background(), risk.BacktestCandidate{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rows []risk.BacktestRow
+	summary, err := Run(context.Background(), &mockDecisionSource{decisions: decisions}, replay, time.Time{}, time.Now(), 10,
+		func(page []risk.BacktestRow) error {
+			rows = append(rows, page...)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary.TierChanges.Count != 0 || summary.HoldChanges.Count != 0 || summary.ReserveChanges.Count != 0 ||
+		!summary.ReserveDelta.IsZero() {
+		t.Errorf("expected no changes from a no-op candidate, got %+v", summary)
+	}
+	for _, row := range rows {
+		if row.BaselineScore != row.CandidateScore {
+			t.Errorf("decision %s: baseline score %d, candidate %d", row.DecisionID, row.BaselineScore, row.CandidateScore)
+		}
+	}
+	if last := rows[len(rows)-1]; last.CandidateHoldPeriod != risk.HoldPeriod7Days {
+		t.Errorf("expected the recorded market override to lengthen the hold, got %s", last.CandidateHoldPeriod)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	repo := newMockRepository()
+	service := NewService(repo, &mockDecisionSource{decisions: testDecisions(t)}, risk.NewService(nil, nil))
+	service.pageSize = 2
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 3, 0)
+
+	t.Run("invalid window", func(t *testing.T) {
+		_, err := service.Submit(context.Background(), Request{From: to, To: from}, "")
+		if !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("expected ErrInvalidRequest, got %v", err)
+		}
+	})
+
+	t.Run("invalid candidate", func(t *testing.T) {
+		tiers := stricterTiers()[1:]
+		_, err := service.Submit(context.Background(), Request{From: from, To: to,
+			BacktestCandidate: risk.BacktestCandidate{Tiers: tiers}}, "")
+		if !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("expected ErrInvalidRequest, got %v", err)
+		}
+	})
+
+	t.Run("report after completion", func(t *testing.T) {
+		job, err := service.Submit(context.Background(), Request{From: from, To: to,
+			BacktestCandidate: risk.BacktestCandidate{Tiers: stricterTiers()}}, "analyst-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if job.Status != StatusRunning || job.CandidateVersion != "builtin-v3" {
+			t.Errorf("expected running job for builtin-v3, got %s %s", job.Status, job.CandidateVersion)
+		}
+		if _, err := service.Report(context.Background(), job.ID); !errors.Is(err, ErrJobNotCompleted) {
+			t.Errorf("expected ErrJobNotCompleted while running, got %v", err)
+		}
+
+		finished := <-repo.finished
+		if finished.Status != StatusCompleted || finished.Summary.Decisions != 3 {
+			t.Fatalf("expected completed job with 3 decisions, got %s: %s", finished.Status, finished.Error)
+		}
+		repo.jobs[job.ID] = finished
+
+		report, err := service.Report(context.Background(), job.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var buf bytes.Buffer
+		if err := report.WriteCSV(context.Background(), &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		records, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid CSV: %v", err)
+		}
+		if len(records) != 4 || records[0][0] != "decision_id" || records[1][3] != "100000.00" || records[1][7] != "MEDIUM_LOW" {
+			t.Errorf("unexpected CSV %v", records)
+		}
+
+		buf.Reset()
+		if err := report.WriteJSON(context.Background(), &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var body struct {
+			ID      uuid.UUID             `json:"id"`
+			Status  Status                `json:"status"`
+			Summary *risk.BacktestSummary `json:"summary"`
+			Rows    []risk.BacktestRow    `json:"rows"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if body.ID != job.ID || body.Status != StatusCompleted || body.Summary == nil || len(body.Rows) != 3 ||
+			body.Rows[2].CandidateRiskLevel != risk.RiskLevelHigh {
+			t.Errorf("unexpected JSON report %+v", body)
+		}
+	})
+
+	service.Stop()
+}
+
+func TestStaleJobs(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 3, 0)
+
+	t.Run("sweeper fails jobs without a recent heartbeat", func(t *testing.T) {
+		repo := newMockRepository()
+		cutoffs := make(chan time.Time, 1)
+		repo.failStale = func(before time.Time, reason string) (int64, error) {
+			if reason == "" {
+				t.Error("expected a reason for failing stale jobs")
+			}
+			select {
+			case cutoffs <- before:
+			default:
+			}
+			return 1, nil
+		}
+		service := NewService(repo, &mockDecisionSource{}, risk.NewService(nil, nil))
+
+		started := time.Now()
+		service.Start()
+		before := <-cutoffs
+		service.Stop()
+
+		if expected := started.Add(-staleAfter); before.Before(expected) || before.After(time.Now().Add(-staleAfter)) {
+			t.Errorf("expected a cutoff %s before now, got %s", staleAfter, before)
+		}
+	})
+
+	t.Run("run stops once its job was failed as stale", func(t *testing.T) {
+		repo := newMockRepository()
+		beats := 0
+		repo.heartbeat = func(id uuid.UUID) error {
+			beats++
+			return ErrJobNotRunning
+		}
+		service := NewService(repo, blockingDecisionSource{}, risk.NewService(nil, nil))
+		service.heartbeatInterval = time.Millisecond
+
+		if _, err := service.Submit(context.Background(), Request{From: from, To: to}, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		select {
+		case job := <-repo.finished:
+			t.Errorf("expected an abandoned run not to record an outcome, got %s", job.Status)
+		case <-time.After(50 * time.Millisecond):
+		}
+		service.Stop()
+		if beats != 1 {
+			t.Errorf("expected the run to stop after its first rejected heartbeat, got %d", beats)
+		}
+	})
+}
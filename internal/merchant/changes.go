@@ -0,0 +1,138 @@
+package merchant
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var ErrInvalidMetricsUpdate = errors.New("invalid metrics update")
+
+// scoreRelevantFields are the metrics risk scoring reads, whether through a
+// factor, a hard stop or volume-based smoothing. Average ticket size is only
+// reported.
+var scoreRelevantFields = map[string]bool{
+	"transaction_volume_30d": true,
+	"transaction_count_30d":  true,
+	"chargeback_count_30d":   true,
+	"chargeback_rate":        true,
+	"refund_rate":            true,
+	"velocity_multiplier":    true,
+	"kyc_verified":           true,
+	"kyc_level":              true,
+}
+
+// MetricsUpdate is a partial update of a merchant's 30-day metrics. Nil fields
+// are left unchanged.
+type MetricsUpdate struct {
+	TransactionVolume30d *decimal.Decimal `json:"transaction_volume_30d"`
+	TransactionCount30d  *int             `json:"transaction_count_30d"`
+	AvgTicketSize        *decimal.Decimal `json:"avg_ticket_size"`
+	ChargebackCount30d   *int             `json:"chargeback_count_30d"`
+	ChargebackRate       *decimal.Decimal `json:"chargeback_rate"`
+	RefundRate           *decimal.Decimal `json:"refund_rate"`
+	VelocityMultiplier   *decimal.Decimal `json:"velocity_multiplier"`
+	KYCVerified          *bool            `json:"kyc_verified"`
+	KYCLevel             *string          `json:"kyc_level"`
+}
+
+func (u MetricsUpdate) Validate() error {
+	hundred := decimal.NewFromInt(100)
+	for field, rate := range map[string]*decimal.Decimal{
+		"chargeback_rate": u.ChargebackRate,
+		"refund_rate":     u.RefundRate,
+	} {
+		if rate != nil && (rate.IsNegative() || rate.GreaterThan(hundred)) {
+			return fmt.Errorf("%w: %s must be between 0 and 100", ErrInvalidMetricsUpdate, field)
+		}
+	}
+	for field, amount := range map[string]*decimal.Decimal{
+		"transaction_volume_30d": u.TransactionVolume30d,
+		"avg_ticket_size":        u.AvgTicketSize,
+		"velocity_multiplier":    u.VelocityMultiplier,
+	} {
+		if amount != nil && amount.IsNegative() {
+			return fmt.Errorf("%w: %s must not be negative", ErrInvalidMetricsUpdate, field)
+		}
+	}
+	for field, count := range map[string]*int{
+		"transaction_count_30d": u.TransactionCount30d,
+		"chargeback_count_30d":  u.ChargebackCount30d,
+	} {
+		if count != nil && *count < 0 {
+			return fmt.Errorf("%w: %s must not be negative", ErrInvalidMetricsUpdate, field)
+		}
+	}
+	if u.KYCLevel != nil && *u.KYCLevel == "" {
+		return fmt.Errorf("%w: kyc_level must not be empty", ErrInvalidMetricsUpdate)
+	}
+	return nil
+}
+
+// Diff compares the update against m's current metrics. It returns the fields
+// whose value would change and the column updates that apply them.
+func (u MetricsUpdate) Diff(m *Merchant) ([]FieldChange, map[string]interface{}) {
+	var changes []FieldChange
+	updates := make(map[string]interface{})
+
+	record := func(field string, from, to interface{}) {
+		changes = append(changes, FieldChange{Field: field, From: from, To: to})
+		updates[field] = to
+	}
+	decimalField := func(field string, current decimal.Decimal, next *decimal.Decimal) {
+		if next != nil && !next.Equal(current) {
+			record(field, current, *next)
+		}
+	}
+	intField := func(field string, current int, next *int) {
+		if next != nil && *next != current {
+			record(field, current, *next)
+		}
+	}
+
+	decimalField("transaction_volume_30d", m.TransactionVolume30d, u.TransactionVolume30d)
+	intField("transaction_count_30d", m.TransactionCount30d, u.TransactionCount30d)
+	decimalField("avg_ticket_size", m.AvgTicketSize, u.AvgTicketSize)
+	intField("chargeback_count_30d", m.ChargebackCount30d, u.ChargebackCount30d)
+	decimalField("chargeback_rate", m.ChargebackRate, u.ChargebackRate)
+	decimalField("refund_rate", m.RefundRate, u.RefundRate)
+	decimalField("velocity_multiplier", m.VelocityMultiplier, u.VelocityMultiplier)
+	if u.KYCVerified != nil && *u.KYCVerified != m.KYCVerified {
+		record("kyc_verified", m.KYCVerified, *u.KYCVerified)
+	}
+	if u.KYCLevel != nil && *u.KYCLevel != m.KYCLevel {
+		record("kyc_level", m.KYCLevel, *u.KYCLevel)
+	}
+
+	return changes, updates
+}
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// MetricChange is an applied metrics update. SnapshotID is the metric
+// snapshot recording the merchant's metrics after the change; decisions
+// triggered by the change refer to it. Both are zero when nothing changed.
+type MetricChange struct {
+	MerchantID         uuid.UUID     `json:"merchant_id"`
+	SnapshotID         uuid.UUID     `json:"snapshot_id"`
+	ChangedAt          time.Time     `json:"changed_at"`
+	Changes            []FieldChange `json:"changes"`
+	ReevaluationQueued bool          `json:"reevaluation_queued"`
+}
+
+// ScoreRelevant reports whether any changed field can move the risk score.
+func (c MetricChange) ScoreRelevant() bool {
+	for _, change := range c.Changes {
+		if scoreRelevantFields[change.Field] {
+			return true
+		}
+	}
+	return false
+}
@@ -44,6 +44,8 @@ type MerchantProfile struct {
 	AccountAgeDays   int           `json:"account_age_days"`
 	RiskMetrics      RiskMetrics   `json:"risk_metrics"`
 	CurrentPolicy    *PolicyInfo   `json:"current_policy,omitempty"`
+	ManualOverride   *ManualOverrideInfo `json:"manual_override,omitempty"`
+	PendingPolicy    *PolicyInfo   `json:"pending_policy,omitempty"`
 }
 
 type RiskMetrics struct {
@@ -58,9 +60,88 @@ type RiskMetrics struct {
 	KYCLevel             string          `json:"kyc_level"`
 }
 
+// PolicyInfo describes a decision's payout policy. Status is EFFECTIVE or
+// PENDING_APPROVAL, or SAFE_DEFAULT when no decision is in force yet and the
+// approval default applies.
 type PolicyInfo struct {
+	DecisionID                *uuid.UUID `json:"decision_id,omitempty"`
 	RiskScore                 int       `json:"risk_score"`
+	RiskLevel                 string    `json:"risk_level,omitempty"`
 	PayoutHoldPeriod          string    `json:"payout_hold_period"`
 	RollingReservePercentage  int       `json:"rolling_reserve_percentage"`
 	LastEvaluatedAt           time.Time `json:"last_evaluated_at"`
+	Status                    string    `json:"status,omitempty"`
+}
+
+// ManualOverrideInfo describes an analyst override that currently pins the
+// merchant's payout policy in place of the computed one.
+type ManualOverrideInfo struct {
+	ID                uuid.UUID `json:"id"`
+	HoldPeriod        string    `json:"hold_period"`
+	ReservePercentage int       `json:"reserve_percentage"`
+	Justification     string    `json:"justification"`
+	CreatedBy         string    `json:"created_by"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// MetricSnapshot records a merchant's 30-day aggregates as they stood at
+// CapturedAt. A snapshot is written whenever the merchant's metrics are
+// created or updated, so the table holds the full history of changes.
+type MetricSnapshot struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MerchantID uuid.UUID `json:"merchant_id" gorm:"type:uuid;not null"`
+
+	TransactionVolume30d decimal.Decimal `json:"transaction_volume_30d" gorm:"column:transaction_volume_30d;type:decimal(15,2);not null"`
+	TransactionCount30d  int             `json:"transaction_count_30d" gorm:"column:transaction_count_30d;not null"`
+	AvgTicketSize        decimal.Decimal `json:"avg_ticket_size" gorm:"column:avg_ticket_size;type:decimal(10,2);not null"`
+
+	ChargebackCount30d int             `json:"chargeback_count_30d" gorm:"column:chargeback_count_30d;not null"`
+	ChargebackRate     decimal.Decimal `json:"chargeback_rate" gorm:"column:chargeback_rate;type:decimal(5,2);not null"`
+	RefundRate         decimal.Decimal `json:"refund_rate" gorm:"column:refund_rate;type:decimal(5,2);not null"`
+	VelocityMultiplier decimal.Decimal `json:"velocity_multiplier" gorm:"column:velocity_multiplier;type:decimal(5,2);not null"`
+
+	KYCVerified bool   `json:"kyc_verified" gorm:"column:kyc_verified;not null"`
+	KYCLevel    string `json:"kyc_level" gorm:"column:kyc_level;not null"`
+
+	// ReevaluationDue is set when a score-relevant metric changed. The
+	// scheduler re-evaluates the merchant until a decision is newer than the
+	// snapshot.
+	ReevaluationDue bool `json:"reevaluation_due" gorm:"not null;default:false"`
+
+	CapturedAt time.Time `json:"captured_at" gorm:"not null;default:now()"`
+}
+
+func (MetricSnapshot) TableName() string {
+	return "merchant_metric_snapshots"
+}
+
+// NewMetricSnapshot captures m's current metrics at the given time.
+func NewMetricSnapshot(m *Merchant, at time.Time) MetricSnapshot {
+	return MetricSnapshot{
+		MerchantID:           m.ID,
+		TransactionVolume30d: m.TransactionVolume30d,
+		TransactionCount30d:  m.TransactionCount30d,
+		AvgTicketSize:        m.AvgTicketSize,
+		ChargebackCount30d:   m.ChargebackCount30d,
+		ChargebackRate:       m.ChargebackRate,
+		RefundRate:           m.RefundRate,
+		VelocityMultiplier:   m.VelocityMultiplier,
+		KYCVerified:          m.KYCVerified,
+		KYCLevel:             m.KYCLevel,
+		CapturedAt:           at,
+	}
+}
+
+// ApplyTo overwrites m's metrics with the snapshot's, leaving identity and
+// account age untouched.
+func (s MetricSnapshot) ApplyTo(m *Merchant) {
+	m.TransactionVolume30d = s.TransactionVolume30d
+	m.TransactionCount30d = s.TransactionCount30d
+	m.AvgTicketSize = s.AvgTicketSize
+	m.ChargebackCount30d = s.ChargebackCount30d
+	m.ChargebackRate = s.ChargebackRate
+	m.RefundRate = s.RefundRate
+	m.VelocityMultiplier = s.VelocityMultiplier
+	m.KYCVerified = s.KYCVerified
+	m.KYCLevel = s.KYCLevel
 }
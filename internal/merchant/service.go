@@ -2,24 +2,38 @@ package merchant
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 
 	"github.com/google/uuid"
 )
 
+var ErrMerchantNotFound = errors.New("merchant not found")
+
 type MerchantRepository interface {
 	Create(ctx context.Context, m *Merchant) error
 	Get(ctx context.Context, id uuid.UUID) (*Merchant, error)
 	List(ctx context.Context, limit, offset int) ([]Merchant, int64, error)
+	Update(ctx context.Context, id uuid.UUID, updates map[string]interface{}, reevaluationDue bool) (*MetricSnapshot, error)
 	BulkCreate(ctx context.Context, merchants []Merchant) error
 }
 
+// ReevaluationQueue accepts merchants whose score-relevant metrics changed. It
+// reports false when the change could not be queued.
+type ReevaluationQueue interface {
+	Enqueue(change MetricChange) bool
+}
+
 type Service struct {
-	store MerchantRepository
+	store         MerchantRepository
+	reevaluations ReevaluationQueue
 }
 
-func NewService(store MerchantRepository) *Service {
-	return &Service{store: store}
+// NewService builds the merchant service. reevaluations may be nil, in which
+// case metric changes are saved without triggering a re-evaluation.
+func NewService(store MerchantRepository, reevaluations ReevaluationQueue) *Service {
+	return &Service{store: store, reevaluations: reevaluations}
 }
 
 func (s *Service) Create(ctx context.Context, m *Merchant) (*Merchant, error) {
@@ -37,6 +51,46 @@ func (s *Service) List(ctx context.Context, limit, offset int) ([]Merchant, int6
 	return s.store.List(ctx, limit, offset)
 }
 
+// UpdateMetrics applies the changed fields of update and records them as a new
+// metric snapshot. When a score-relevant field changed, the snapshot is marked
+// due for re-evaluation and the merchant is queued, and the resulting decision
+// refers to the snapshot. If the queue is full or the change is lost on
+// restart, the scheduler picks up the marked snapshot on its next cycle.
+func (s *Service) UpdateMetrics(ctx context.Context, id uuid.UUID, update MetricsUpdate) (*MetricChange, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	m, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &MetricChange{MerchantID: id}
+	var updates map[string]interface{}
+	change.Changes, updates = update.Diff(m)
+	if len(change.Changes) == 0 {
+		return change, nil
+	}
+
+	reevaluate := s.reevaluations != nil && change.ScoreRelevant()
+	snapshot, err := s.store.Update(ctx, id, updates, reevaluate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update merchant metrics: %w", err)
+	}
+	change.SnapshotID = snapshot.ID
+	change.ChangedAt = snapshot.CapturedAt
+
+	if reevaluate {
+		change.ReevaluationQueued = s.reevaluations.Enqueue(*change)
+		if !change.ReevaluationQueued {
+			log.Printf("[WARN] Re-evaluation queue full; merchant %s is left for the scheduler", id)
+		}
+	}
+
+	return change, nil
+}
+
 func (s *Service) Seed(ctx context.Context, count int) ([]Merchant, error) {
 	generator := NewGenerator()
 	merchants := generator.Generate(count)
@@ -0,0 +1,132 @@
+package merchant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type mockMerchantRepository struct {
+	merchant        *Merchant
+	updates         map[string]interface{}
+	reevaluationDue bool
+}
+
+func (m *mockMerchantRepository) Create(ctx context.Context, merchant *Merchant) error {
+	return nil
+}
+
+func (m *mockMerchantRepository) Get(ctx context.Context, id uuid.UUID) (*Merchant, error) {
+	if m.merchant == nil || m.merchant.ID != id {
+		return nil, fmt.Errorf("%w: %s", ErrMerchantNotFound, id)
+	}
+	found := *m.merchant
+	return &found, nil
+}
+
+func (m *mockMerchantRepository) List(ctx context.Context, limit, offset int) ([]Merchant, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *mockMerchantRepository) Update(ctx context.Context, id uuid.UUID, updates map[string]interface{}, reevaluationDue bool) (*MetricSnapshot, error) {
+	m.updates = updates
+	m.reevaluationDue = reevaluationDue
+	return &MetricSnapshot{ID: uuid.New(), MerchantID: id, ReevaluationDue: reevaluationDue, CapturedAt: time.Now()}, nil
+}
+
+func (m *mockMerchantRepository) BulkCreate(ctx context.Context, merchants []Merchant) error {
+	return nil
+}
+
+type mockReevaluationQueue struct {
+	changes []MetricChange
+}
+
+func (m *mockReevaluationQueue) Enqueue(change MetricChange) bool {
+	m.changes = append(m.changes, change)
+	return true
+}
+
+func decimalPtr(v float64) *decimal.Decimal {
+	d := decimal.NewFromFloat(v)
+	return &d
+}
+
+func TestUpdateMetrics(t *testing.T) {
+	m := &Merchant{
+		ID:             uuid.New(),
+		ChargebackRate: decimal.NewFromFloat(0.4),
+		AvgTicketSize:  decimal.NewFromFloat(50),
+		KYCVerified:    true,
+	}
+
+	t.Run("score-relevant change queues a re-evaluation", func(t *testing.T) {
+		store := &mockMerchantRepository{merchant: m}
+		queue := &mockReevaluationQueue{}
+		service := NewService(store, queue)
+
+		verified := true
+		change, err := service.UpdateMetrics(context.Background(), m.ID, MetricsUpdate{
+			ChargebackRate: decimalPtr(1.8),
+			KYCVerified:    &verified,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(change.Changes) != 1 || change.Changes[0].Field != "chargeback_rate" {
+			t.Fatalf("expected only chargeback_rate to change, got %+v", change.Changes)
+		}
+		if len(store.updates) != 1 {
+			t.Errorf("expected one column update, got %v", store.updates)
+		}
+		if !change.ReevaluationQueued || len(queue.changes) != 1 || queue.changes[0].SnapshotID != change.SnapshotID {
+			t.Errorf("expected change queued with its snapshot, got %+v", queue.changes)
+		}
+		if !store.reevaluationDue {
+			t.Error("expected the snapshot to be marked due for the scheduler")
+		}
+	})
+
+	t.Run("reporting-only change is saved without re-evaluation", func(t *testing.T) {
+		store := &mockMerchantRepository{merchant: m}
+		queue := &mockReevaluationQueue{}
+		service := NewService(store, queue)
+
+		change, err := service.UpdateMetrics(context.Background(), m.ID, MetricsUpdate{AvgTicketSize: decimalPtr(75)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if store.updates == nil || store.reevaluationDue || change.ReevaluationQueued || len(queue.changes) != 0 {
+			t.Errorf("expected update without re-evaluation, got %+v", change)
+		}
+	})
+
+	t.Run("unchanged values are not written", func(t *testing.T) {
+		store := &mockMerchantRepository{merchant: m}
+		service := NewService(store, &mockReevaluationQueue{})
+
+		change, err := service.UpdateMetrics(context.Background(), m.ID, MetricsUpdate{ChargebackRate: decimalPtr(0.40)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(change.Changes) != 0 || store.updates != nil {
+			t.Errorf("expected no changes, got %+v", change.Changes)
+		}
+	})
+
+	t.Run("invalid values and unknown merchants are rejected", func(t *testing.T) {
+		service := NewService(&mockMerchantRepository{merchant: m}, nil)
+
+		if _, err := service.UpdateMetrics(context.Background(), m.ID, MetricsUpdate{RefundRate: decimalPtr(-1)}); !errors.Is(err, ErrInvalidMetricsUpdate) {
+			t.Errorf("expected ErrInvalidMetricsUpdate, got %v", err)
+		}
+		if _, err := service.UpdateMetrics(context.Background(), uuid.New(), MetricsUpdate{}); !errors.Is(err, ErrMerchantNotFound) {
+			t.Errorf("expected ErrMerchantNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+const (
+	EventDecisionCreated  = "risk.decision.created"
+	EventDecisionReviewed = "risk.decision.reviewed"
+	EventTierChanged      = "risk.tier.changed"
+	EventHighRiskDetected = "risk.high_risk.detected"
+)
+
+// EventTypes lists every event type the outbox records.
+var EventTypes = []string{EventDecisionCreated, EventDecisionReviewed, EventTierChanged, EventHighRiskDetected}
+
+// Event is a message written in the same transaction as the change it
+// describes and delivered afterwards by the relay. Delivery is at least once,
+// so consumers should deduplicate on ID.
+type Event struct {
+	ID         uuid.UUID       `json:"event_id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Type       string          `json:"type" gorm:"not null"`
+	MerchantID uuid.UUID       `json:"merchant_id" gorm:"type:uuid;not null"`
+	Payload    json.RawMessage `json:"payload" gorm:"type:jsonb;not null"`
+	OccurredAt time.Time       `json:"occurred_at" gorm:"not null;default:now()"`
+
+	Attempts      int        `json:"-" gorm:"not null;default:0"`
+	LastError     string     `json:"-" gorm:"not null;default:''"`
+	NextAttemptAt time.Time  `json:"-" gorm:"not null;default:now()"`
+	PublishedAt   *time.Time `json:"-"`
+}
+
+func (Event) TableName() string {
+	return "outbox_events"
+}
+
+// DecisionPayload is the payout policy a decision sets.
+type DecisionPayload struct {
+	DecisionID               uuid.UUID           `json:"decision_id"`
+	MerchantID               uuid.UUID           `json:"merchant_id"`
+	RiskScore                int                 `json:"risk_score"`
+	RiskLevel                risk.RiskLevel      `json:"risk_level"`
+	PayoutHoldPeriod         risk.HoldPeriod     `json:"payout_hold_period"`
+	RollingReservePercentage int                 `json:"rolling_reserve_percentage"`
+	Status                   risk.DecisionStatus `json:"status"`
+	ModelVersion             string              `json:"model_version"`
+	PolicyVersion            string              `json:"policy_version"`
+	BatchID                  *uuid.UUID          `json:"batch_id,omitempty"`
+	TriggerSnapshotID        *uuid.UUID          `json:"trigger_snapshot_id,omitempty"`
+	EvaluatedAt              time.Time           `json:"evaluated_at"`
+	ReviewedBy               string              `json:"reviewed_by,omitempty"`
+	ReviewedAt               *time.Time          `json:"reviewed_at,omitempty"`
+}
+
+// TierChangePayload describes a change to the payout policy in force for a
+// merchant: its risk level, hold period, reserve, or any combination.
+type TierChangePayload struct {
+	DecisionID                       uuid.UUID           `json:"decision_id"`
+	MerchantID                       uuid.UUID           `json:"merchant_id"`
+	PreviousDecisionID               uuid.UUID           `json:"previous_decision_id"`
+	PreviousRiskLevel                risk.RiskLevel      `json:"previous_risk_level"`
+	RiskLevel                        risk.RiskLevel      `json:"risk_level"`
+	PreviousPayoutHoldPeriod         risk.HoldPeriod     `json:"previous_payout_hold_period"`
+	PayoutHoldPeriod                 risk.HoldPeriod     `json:"payout_hold_period"`
+	PreviousRollingReservePercentage int                 `json:"previous_rolling_reserve_percentage"`
+	RollingReservePercentage         int                 `json:"rolling_reserve_percentage"`
+	Status                           risk.DecisionStatus `json:"status"`
+}
+
+func decisionPayload(d *risk.RiskDecision) DecisionPayload {
+	return DecisionPayload{
+		DecisionID:               d.ID,
+		MerchantID:               d.MerchantID,
+		RiskScore:                d.RiskScore,
+		RiskLevel:                d.RiskLevel,
+		PayoutHoldPeriod:         d.PayoutHoldPeriod,
+		RollingReservePercentage: d.RollingReservePercentage,
+		Status:                   d.Status,
+		ModelVersion:             d.ModelVersion,
+		PolicyVersion:            d.PolicyVersion,
+		BatchID:                  d.BatchID,
+		TriggerSnapshotID:        d.TriggerSnapshotID,
+		EvaluatedAt:              d.EvaluatedAt,
+		ReviewedBy:               d.ReviewedBy,
+		ReviewedAt:               d.ReviewedAt,
+	}
+}
+
+func newEvent(eventType string, merchantID uuid.UUID, payload interface{}, at time.Time) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s payload: %w", eventType, err)
+	}
+	return Event{Type: eventType, MerchantID: merchantID, Payload: data, OccurredAt: at, NextAttemptAt: at}, nil
+}
+
+// DecisionEvents returns the events for a newly persisted decision: always
+// risk.decision.created, risk.high_risk.detected at HIGH or above, and
+// risk.tier.changed when the decision takes effect at once with a different
+// policy from previous, the merchant's last EFFECTIVE decision. previous is
+// nil when no decision is in force yet.
+func DecisionEvents(decision, previous *risk.RiskDecision) ([]Event, error) {
+	created, err := newEvent(EventDecisionCreated, decision.MerchantID, decisionPayload(decision), decision.EvaluatedAt)
+	if err != nil {
+		return nil, err
+	}
+	events := []Event{created}
+
+	if decision.RiskLevel.AtLeast(risk.RiskLevelHigh) {
+		detected, err := newEvent(EventHighRiskDetected, decision.MerchantID, decisionPayload(decision), decision.EvaluatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, detected)
+	}
+
+	if decision.Status != risk.DecisionEffective {
+		return events, nil
+	}
+	changed, ok, err := TierChangeEvent(decision, previous, decision.EvaluatedAt)
+	if err != nil || !ok {
+		return events, err
+	}
+	return append(events, changed), nil
+}
+
+// TierChangeEvent returns the risk.tier.changed event for decision taking
+// effect at at in place of previous. It reports false when previous is nil or
+// sets the same risk level, hold period and reserve.
+func TierChangeEvent(decision, previous *risk.RiskDecision, at time.Time) (Event, bool, error) {
+	if previous == nil || (previous.RiskLevel == decision.RiskLevel &&
+		previous.PayoutHoldPeriod == decision.PayoutHoldPeriod &&
+		previous.RollingReservePercentage == decision.RollingReservePercentage) {
+		return Event{}, false, nil
+	}
+
+	changed, err := newEvent(EventTierChanged, decision.MerchantID, TierChangePayload{
+		DecisionID:                       decision.ID,
+		MerchantID:                       decision.MerchantID,
+		PreviousDecisionID:               previous.ID,
+		PreviousRiskLevel:                previous.RiskLevel,
+		RiskLevel:                        decision.RiskLevel,
+		PreviousPayoutHoldPeriod:         previous.PayoutHoldPeriod,
+		PayoutHoldPeriod:                 decision.PayoutHoldPeriod,
+		PreviousRollingReservePercentage: previous.RollingReservePercentage,
+		RollingReservePercentage:         decision.RollingReservePercentage,
+		Status:                           decision.Status,
+	}, at)
+	if err != nil {
+		return Event{}, false, err
+	}
+	return changed, true, nil
+}
+
+// ReviewEvent returns the risk.decision.reviewed event for a decision that
+// was just approved or rejected.
+func ReviewEvent(decision *risk.RiskDecision) (Event, error) {
+	at := time.Now()
+	if decision.ReviewedAt != nil {
+		at = *decision.ReviewedAt
+	}
+	return newEvent(EventDecisionReviewed, decision.MerchantID, decisionPayload(decision), at)
+}
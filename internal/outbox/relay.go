@@ -0,0 +1,158 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const maxBackoff = time.Hour
+
+type Repository interface {
+	// ClaimPending returns up to limit unpublished events due at now, oldest
+	// first, and hides them from other relays until leaseUntil. Events whose
+	// relay dies mid-delivery reappear when the lease expires.
+	ClaimPending(ctx context.Context, limit int, now, leaseUntil time.Time) ([]Event, error)
+	MarkPublished(ctx context.Context, id uuid.UUID, at time.Time) error
+	MarkFailed(ctx context.Context, id uuid.UUID, cause string, nextAttemptAt time.Time) error
+}
+
+type RelayConfig struct {
+	// Interval is how often the relay polls for pending events.
+	Interval  time.Duration
+	BatchSize int
+	// Lease is how long claimed events stay hidden from other relays. It must
+	// exceed the time needed to publish a batch.
+	Lease time.Duration
+}
+
+// Relay moves events from the outbox to a Publisher. An event is marked
+// published only after the publisher accepts it, so a crash in between
+// delivers it again.
+type Relay struct {
+	store     Repository
+	publisher Publisher
+	config    RelayConfig
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewRelay(store Repository, publisher Publisher, config RelayConfig) *Relay {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.Lease <= 0 {
+		config.Lease = time.Minute
+	}
+
+	return &Relay{store: store, publisher: publisher, config: config}
+}
+
+// Start polls the outbox every Interval until Stop is called.
+func (r *Relay) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.loop(ctx, r.done)
+	log.Printf("[INFO] Outbox relay started (interval=%s, batch=%d)", r.config.Interval, r.config.BatchSize)
+}
+
+// Stop ends polling and waits for the batch in flight to finish.
+func (r *Relay) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	log.Println("[INFO] Outbox relay stopped")
+}
+
+func (r *Relay) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while batches come back full.
+		for {
+			claimed, err := r.RelayOnce(ctx)
+			if err != nil {
+				log.Printf("[ERROR] Outbox relay failed: %v", err)
+				break
+			}
+			if claimed < r.config.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce claims one batch of due events and publishes them in order. It
+// returns how many events it claimed. A failed event is rescheduled with
+// exponential backoff and does not stop the rest of the batch.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	now := time.Now()
+	events, err := r.store.ClaimPending(ctx, r.config.BatchSize, now, now.Add(r.config.Lease))
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	for _, event := range events {
+		// Publish the claimed event even if ctx is cancelled meanwhile, so
+		// shutdown does not leave it waiting for the lease to expire.
+		publishCtx := context.WithoutCancel(ctx)
+		if err := r.publisher.Publish(publishCtx, event); err != nil {
+			next := time.Now().Add(backoff(event.Attempts + 1))
+			log.Printf("[WARN] Failed to publish %s event %s (attempt %d), retrying at %s: %v",
+				event.Type, event.ID, event.Attempts+1, next.Format(time.RFC3339), err)
+			if err := r.store.MarkFailed(publishCtx, event.ID, err.Error(), next); err != nil {
+				log.Printf("[ERROR] Failed to reschedule outbox event %s: %v", event.ID, err)
+			}
+			continue
+		}
+
+		if err := r.store.MarkPublished(publishCtx, event.ID, time.Now()); err != nil {
+			// The event will be delivered again once its lease expires.
+			log.Printf("[ERROR] Failed to mark outbox event %s published: %v", event.ID, err)
+		}
+	}
+
+	return len(events), nil
+}
+
+// backoff doubles from 5 seconds with each attempt, up to an hour.
+func backoff(attempt int) time.Duration {
+	delay := 5 * time.Second
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
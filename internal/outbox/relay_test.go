@@ -0,0 +1,187 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+type mockRepository struct {
+	pending   []Event
+	published map[uuid.UUID]bool
+	failed    map[uuid.UUID]time.Time
+}
+
+func (m *mockRepository) ClaimPending(ctx context.Context, limit int, now, leaseUntil time.Time) ([]Event, error) {
+	var claimed []Event
+	for _, e := range m.pending {
+		if !m.published[e.ID] && !e.NextAttemptAt.After(now) && len(claimed) < limit {
+			claimed = append(claimed, e)
+		}
+	}
+	return claimed, nil
+}
+
+func (m *mockRepository) MarkPublished(ctx context.Context, id uuid.UUID, at time.Time) error {
+	m.published[id] = true
+	return nil
+}
+
+func (m *mockRepository) MarkFailed(ctx context.Context, id uuid.UUID, cause string, nextAttemptAt time.Time) error {
+	m.failed[id] = nextAttemptAt
+	return nil
+}
+
+type mockPublisher struct {
+	fail      map[uuid.UUID]bool
+	delivered []uuid.UUID
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, event Event) error {
+	if m.fail[event.ID] {
+		return errors.New("sink unavailable")
+	}
+	m.delivered = append(m.delivered, event.ID)
+	return nil
+}
+
+func TestDecisionEvents(t *testing.T) {
+	merchantID := uuid.New()
+	decision := &risk.RiskDecision{ID: uuid.New(), MerchantID: merchantID, RiskLevel: risk.RiskLevelHigh,
+		PayoutHoldPeriod: risk.HoldPeriod14Days, RollingReservePercentage: 10, Status: risk.DecisionEffective, EvaluatedAt: time.Now()}
+
+	low := &risk.RiskDecision{ID: uuid.New(), MerchantID: merchantID, RiskLevel: risk.RiskLevelLow}
+	events, err := DecisionEvents(low, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != EventDecisionCreated || events[0].MerchantID != merchantID {
+		t.Fatalf("expected only decision.created for a first LOW decision, got %+v", events)
+	}
+
+	same := &risk.RiskDecision{ID: uuid.New(), MerchantID: merchantID, RiskLevel: risk.RiskLevelHigh,
+		PayoutHoldPeriod: risk.HoldPeriod14Days, RollingReservePercentage: 10}
+	if events, _ := DecisionEvents(decision, same); len(events) != 2 {
+		t.Errorf("expected no tier change for an unchanged policy, got %d events", len(events))
+	}
+
+	reserveOnly := *same
+	reserveOnly.RollingReservePercentage = 5
+	if events, _ := DecisionEvents(decision, &reserveOnly); len(events) != 3 || events[2].Type != EventTierChanged {
+		t.Errorf("expected tier.changed for a reserve change alone, got %+v", events)
+	}
+
+	previous := &risk.RiskDecision{ID: uuid.New(), MerchantID: merchantID, RiskLevel: risk.RiskLevelLow,
+		PayoutHoldPeriod: risk.HoldPeriodImmediate}
+	events, err = DecisionEvents(decision, previous)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 3 || events[1].Type != EventHighRiskDetected || events[2].Type != EventTierChanged {
+		t.Fatalf("expected decision.created, high_risk.detected and tier.changed, got %+v", events)
+	}
+	var payload TierChangePayload
+	if err := json.Unmarshal(events[2].Payload, &payload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload.PreviousRiskLevel != risk.RiskLevelLow || payload.RiskLevel != risk.RiskLevelHigh ||
+		payload.PreviousDecisionID != previous.ID || payload.PayoutHoldPeriod != risk.HoldPeriod14Days {
+		t.Errorf("unexpected tier change payload: %+v", payload)
+	}
+
+	pending := *decision
+	pending.Status = risk.DecisionPendingApproval
+	if events, _ := DecisionEvents(&pending, previous); len(events) != 2 {
+		t.Errorf("expected no tier change while the decision awaits approval, got %d events", len(events))
+	}
+
+	approvedAt := time.Now().Add(time.Hour)
+	changed, ok, err := TierChangeEvent(decision, previous, approvedAt)
+	if err != nil || !ok || changed.Type != EventTierChanged || !changed.OccurredAt.Equal(approvedAt) {
+		t.Errorf("expected tier.changed at approval time, got %+v (%v, %v)", changed, ok, err)
+	}
+	if _, ok, _ := TierChangeEvent(decision, nil, approvedAt); ok {
+		t.Error("expected no tier change without a previous effective decision")
+	}
+}
+
+func TestRelayOnce(t *testing.T) {
+	healthy, failing := uuid.New(), uuid.New()
+	past := time.Now().Add(-time.Minute)
+	repo := &mockRepository{
+		pending: []Event{
+			{ID: failing, Type: EventDecisionCreated, NextAttemptAt: past, Attempts: 2},
+			{ID: healthy, Type: EventTierChanged, NextAttemptAt: past},
+		},
+		published: make(map[uuid.UUID]bool),
+		failed:    make(map[uuid.UUID]time.Time),
+	}
+	publisher := &mockPublisher{fail: map[uuid.UUID]bool{failing: true}}
+	relay := NewRelay(repo, publisher, RelayConfig{BatchSize: 10})
+
+	claimed, err := relay.RelayOnce(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claimed != 2 || len(publisher.delivered) != 1 || !repo.published[healthy] {
+		t.Errorf("expected the healthy event published despite the failure, got %v", publisher.delivered)
+	}
+	retryAt, rescheduled := repo.failed[failing]
+	if !rescheduled || repo.published[failing] {
+		t.Fatal("expected failed event to stay unpublished and be rescheduled")
+	}
+	if delay := time.Until(retryAt); delay < 15*time.Second || delay > 20*time.Second {
+		t.Errorf("expected third attempt to back off ~20s, got %s", delay)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := map[int]time.Duration{1: 5 * time.Second, 2: 10 * time.Second, 4: 40 * time.Second, 20: time.Hour}
+	for attempt, want := range cases {
+		if got := backoff(attempt); got != want {
+			t.Errorf("attempt %d: expected %s, got %s", attempt, want, got)
+		}
+	}
+}
+
+func TestPublishers(t *testing.T) {
+	event := Event{ID: uuid.New(), Type: EventDecisionCreated, MerchantID: uuid.New(), Payload: json.RawMessage(`{"risk_level":"LOW"}`)}
+
+	var buf bytes.Buffer
+	if err := NewWriterPublisher(&buf).Publish(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var written map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &written); err != nil || written["event_id"] != event.ID.String() {
+		t.Errorf("expected a JSON line with the event ID, got %q", buf.String())
+	}
+
+	status := http.StatusAccepted
+	var key string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("Idempotency-Key")
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	publisher := NewHTTPPublisher(server.URL, time.Second)
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key != event.ID.String() {
+		t.Errorf("expected Idempotency-Key %s, got %q", event.ID, key)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := publisher.Publish(context.Background(), event); err == nil {
+		t.Error("expected error for a non-2xx response")
+	}
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 )
 
 type Environment string
@@ -17,6 +19,11 @@ type Config struct {
 	Environment Environment
 	Port        string
 	Database    DatabaseConfig
+	Risk        RiskConfig
+	Review      ReviewConfig
+	Scheduler   SchedulerConfig
+	Outbox      OutboxConfig
+	Webhook     WebhookConfig
 }
 
 type DatabaseConfig struct {
@@ -28,6 +35,62 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
+type RiskConfig struct {
+	RulesetPath           string
+	Scorer                string
+	ModelPath             string
+	HysteresisMargin      int
+	HysteresisEvaluations int
+	// ApprovalLevel is the lowest risk level whose decisions wait for
+	// approval; empty disables approval.
+	ApprovalLevel          string
+	ApprovalDefaultHold    string
+	ApprovalDefaultReserve int
+	Approvers              []string
+	// ReevaluationQueueSize and ReevaluationWorkers size the background
+	// re-evaluation triggered by metric changes.
+	ReevaluationQueueSize int
+	ReevaluationWorkers   int
+	// Challengers are scored in shadow next to the live configuration, each
+	// given as "ruleset:<path>" or "logistic:<path>".
+	Challengers []string
+}
+
+// ReviewConfig sets how many hours a review case may wait at each risk level
+// before its SLA is breached.
+type ReviewConfig struct {
+	HighSLAHours     int
+	CriticalSLAHours int
+}
+
+// SchedulerConfig controls periodic re-evaluation. Cadence overrides the
+// per-tier intervals, e.g. "CRITICAL=12h,LOW=720h".
+type SchedulerConfig struct {
+	Enabled         bool
+	IntervalMinutes int
+	BatchSize       int
+	Cadence         string
+}
+
+// OutboxConfig selects where the relay publishes outbox events: stdout, a
+// file, an HTTP endpoint, or none to leave them in the outbox.
+type OutboxConfig struct {
+	Publisher          string
+	FilePath           string
+	HTTPURL            string
+	HTTPTimeoutSeconds int
+	IntervalSeconds    int
+	BatchSize          int
+}
+
+// WebhookConfig controls webhook delivery. A delivery is dead-lettered after
+// MaxAttempts failures.
+type WebhookConfig struct {
+	MaxAttempts     int
+	TimeoutSeconds  int
+	IntervalSeconds int
+}
+
 func Load() *Config {
 	env := os.Getenv("ENVIRONMENT")
 	if env == "" {
@@ -45,6 +108,43 @@ func Load() *Config {
 			DBName:   getEnv("DB_NAME", "papaya_payout_engine"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
+		Risk: RiskConfig{
+			RulesetPath:            getEnv("RISK_RULESET_PATH", ""),
+			Scorer:                 getEnv("RISK_SCORER", "additive"),
+			ModelPath:              getEnv("RISK_MODEL_PATH", ""),
+			HysteresisMargin:       getEnvInt("RISK_HYSTERESIS_MARGIN", 0),
+			HysteresisEvaluations:  getEnvInt("RISK_HYSTERESIS_EVALUATIONS", 0),
+			ApprovalLevel:          approvalLevel(getEnv("RISK_APPROVAL_LEVEL", "CRITICAL")),
+			ApprovalDefaultHold:    getEnv("RISK_APPROVAL_DEFAULT_HOLD", "45_DAYS"),
+			ApprovalDefaultReserve: getEnvInt("RISK_APPROVAL_DEFAULT_RESERVE", 20),
+			Approvers:              getEnvList("RISK_APPROVERS"),
+			ReevaluationQueueSize:  getEnvInt("RISK_REEVALUATION_QUEUE_SIZE", 1000),
+			ReevaluationWorkers:    getEnvInt("RISK_REEVALUATION_WORKERS", 4),
+			Challengers:            getEnvList("RISK_CHALLENGERS"),
+		},
+		Review: ReviewConfig{
+			HighSLAHours:     getEnvInt("REVIEW_SLA_HIGH_HOURS", 24),
+			CriticalSLAHours: getEnvInt("REVIEW_SLA_CRITICAL_HOURS", 4),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:         getEnv("SCHEDULER_ENABLED", "true") == "true",
+			IntervalMinutes: getEnvInt("SCHEDULER_INTERVAL_MINUTES", 15),
+			BatchSize:       getEnvInt("SCHEDULER_BATCH_SIZE", 200),
+			Cadence:         getEnv("SCHEDULER_CADENCE", ""),
+		},
+		Outbox: OutboxConfig{
+			Publisher:          getEnv("OUTBOX_PUBLISHER", "stdout"),
+			FilePath:           getEnv("OUTBOX_FILE_PATH", "outbox-events.jsonl"),
+			HTTPURL:            getEnv("OUTBOX_HTTP_URL", ""),
+			HTTPTimeoutSeconds: getEnvInt("OUTBOX_HTTP_TIMEOUT_SECONDS", 10),
+			IntervalSeconds:    getEnvInt("OUTBOX_RELAY_INTERVAL_SECONDS", 5),
+			BatchSize:          getEnvInt("OUTBOX_BATCH_SIZE", 100),
+		},
+		Webhook: WebhookConfig{
+			MaxAttempts:     getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			TimeoutSeconds:  getEnvInt("WEBHOOK_TIMEOUT_SECONDS", 10),
+			IntervalSeconds: getEnvInt("WEBHOOK_DISPATCH_INTERVAL_SECONDS", 5),
+		},
 	}
 }
 
@@ -61,3 +161,28 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// approvalLevel maps NONE to the empty level that disables approval.
+func approvalLevel(level string) string {
+	if strings.EqualFold(level, "NONE") {
+		return ""
+	}
+	return strings.ToUpper(level)
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
@@ -12,6 +12,11 @@ const (
 	MaxQueryLimit     = 100
 )
 
+const (
+	DefaultRulesetVersion = "builtin-v3"
+	DefaultPolicyVersion  = "builtin-v1"
+)
+
 const (
 	DefaultChargebackExcellent   = 0.5
 	DefaultChargebackAcceptable  = 1.0
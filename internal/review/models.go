@@ -0,0 +1,109 @@
+package review
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+type Status string
+
+const (
+	StatusOpen      Status = "open"
+	StatusInReview  Status = "in_review"
+	StatusApproved  Status = "approved"
+	StatusRejected  Status = "rejected"
+	StatusEscalated Status = "escalated"
+)
+
+// UnresolvedStatuses are the statuses of cases still waiting on a reviewer.
+var UnresolvedStatuses = []Status{StatusOpen, StatusInReview, StatusEscalated}
+
+// transitions lists the statuses each status may move to. Approved and
+// rejected cases are final.
+var transitions = map[Status][]Status{
+	StatusOpen:      {StatusInReview, StatusEscalated, StatusApproved, StatusRejected},
+	StatusInReview:  {StatusOpen, StatusEscalated, StatusApproved, StatusRejected},
+	StatusEscalated: {StatusInReview, StatusApproved, StatusRejected},
+}
+
+func (s Status) Valid() bool {
+	switch s {
+	case StatusOpen, StatusInReview, StatusApproved, StatusRejected, StatusEscalated:
+		return true
+	}
+	return false
+}
+
+func (s Status) Resolved() bool {
+	return s == StatusApproved || s == StatusRejected
+}
+
+func (s Status) CanMoveTo(next Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Case tracks the manual review of a HIGH or CRITICAL decision. A merchant has
+// at most one unresolved case; later decisions refresh it instead of opening
+// another.
+type Case struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MerchantID uuid.UUID      `json:"merchant_id" gorm:"type:uuid;not null"`
+	DecisionID uuid.UUID      `json:"decision_id" gorm:"type:uuid;not null"`
+	RiskLevel  risk.RiskLevel `json:"risk_level" gorm:"not null"`
+	RiskScore  int            `json:"risk_score" gorm:"not null"`
+	Status     Status         `json:"status" gorm:"not null;default:'open'"`
+	Assignee   string         `json:"assignee" gorm:"not null;default:''"`
+	SLADueAt   time.Time      `json:"sla_due_at" gorm:"column:sla_due_at;not null"`
+	ResolvedAt *time.Time     `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"not null;default:now()"`
+	Comments   []Comment      `json:"comments,omitempty" gorm:"foreignKey:CaseID"`
+
+	// SLABreached is derived when the case is read: an unresolved case is
+	// breached once SLADueAt passes, a resolved one if it was resolved late.
+	SLABreached bool `json:"sla_breached" gorm:"-"`
+}
+
+func (Case) TableName() string {
+	return "review_cases"
+}
+
+func (c *Case) applySLA(now time.Time) {
+	end := now
+	if c.ResolvedAt != nil {
+		end = *c.ResolvedAt
+	}
+	c.SLABreached = end.After(c.SLADueAt)
+}
+
+type Comment struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CaseID    uuid.UUID `json:"case_id" gorm:"type:uuid;not null"`
+	Author    string    `json:"author" gorm:"not null"`
+	Body      string    `json:"body" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (Comment) TableName() string {
+	return "review_case_comments"
+}
+
+// Filter narrows the review queue. MinAge and MaxAge bound how long ago the
+// case was opened; zero values leave that bound open.
+type Filter struct {
+	Statuses    []Status
+	RiskLevel   risk.RiskLevel
+	Assignee    string
+	MinAge      time.Duration
+	MaxAge      time.Duration
+	SLABreached bool
+	Limit       int
+	Offset      int
+}
@@ -0,0 +1,247 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+var (
+	ErrInvalidCase       = errors.New("invalid review case request")
+	ErrInvalidTransition = errors.New("invalid review case transition")
+	ErrCaseNotFound      = errors.New("review case not found")
+	// ErrCaseExists is returned by CaseRepository.Create when the merchant
+	// already has an unresolved case.
+	ErrCaseExists = errors.New("merchant already has an unresolved review case")
+)
+
+type CaseRepository interface {
+	Create(ctx context.Context, c *Case) error
+	Get(ctx context.Context, id uuid.UUID) (*Case, error)
+	GetUnresolvedByMerchant(ctx context.Context, merchantID uuid.UUID) (*Case, error)
+	List(ctx context.Context, filter Filter, now time.Time) ([]Case, int64, error)
+	Update(ctx context.Context, c *Case) error
+	AddComment(ctx context.Context, comment *Comment) error
+}
+
+// SLA sets how long a case at each risk level may wait before it is breached.
+type SLA map[risk.RiskLevel]time.Duration
+
+func DefaultSLA() SLA {
+	return SLA{
+		risk.RiskLevelHigh:     24 * time.Hour,
+		risk.RiskLevelCritical: 4 * time.Hour,
+	}
+}
+
+func (s SLA) dueAt(level risk.RiskLevel, from time.Time) time.Time {
+	window, ok := s[level]
+	if !ok {
+		window = DefaultSLA()[level]
+	}
+	return from.Add(window)
+}
+
+type Service struct {
+	store CaseRepository
+	sla   SLA
+}
+
+func NewService(store CaseRepository, sla SLA) *Service {
+	if sla == nil {
+		sla = DefaultSLA()
+	}
+	return &Service{store: store, sla: sla}
+}
+
+// OpenCase opens a review case for a persisted decision. If the merchant
+// already has an unresolved case it is pointed at the new decision instead,
+// and its SLA tightens when the new risk level allows less time. When a
+// concurrent evaluation opens the case first, that case is refreshed.
+func (s *Service) OpenCase(ctx context.Context, decision *risk.RiskDecision) error {
+	existing, err := s.store.GetUnresolvedByMerchant(ctx, decision.MerchantID)
+	if err != nil {
+		return fmt.Errorf("failed to check open review case: %w", err)
+	}
+	if existing != nil {
+		return s.refreshCase(ctx, existing, decision)
+	}
+
+	c := &Case{
+		MerchantID: decision.MerchantID,
+		DecisionID: decision.ID,
+		RiskLevel:  decision.RiskLevel,
+		RiskScore:  decision.RiskScore,
+		Status:     StatusOpen,
+		SLADueAt:   s.sla.dueAt(decision.RiskLevel, decision.EvaluatedAt),
+	}
+	err = s.store.Create(ctx, c)
+	if errors.Is(err, ErrCaseExists) {
+		existing, err = s.store.GetUnresolvedByMerchant(ctx, decision.MerchantID)
+		if err != nil {
+			return fmt.Errorf("failed to check open review case: %w", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("failed to create review case: %w", ErrCaseExists)
+		}
+		return s.refreshCase(ctx, existing, decision)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create review case: %w", err)
+	}
+
+	log.Printf("[INFO] Review case %s opened for merchant %s (%s, due %s)",
+		c.ID, decision.MerchantID, decision.RiskLevel, c.SLADueAt.Format(time.RFC3339))
+	return nil
+}
+
+func (s *Service) refreshCase(ctx context.Context, existing *Case, decision *risk.RiskDecision) error {
+	existing.DecisionID = decision.ID
+	existing.RiskScore = decision.RiskScore
+	existing.RiskLevel = decision.RiskLevel
+	if due := s.sla.dueAt(decision.RiskLevel, decision.EvaluatedAt); due.Before(existing.SLADueAt) {
+		existing.SLADueAt = due
+	}
+	existing.UpdatedAt = time.Now()
+
+	if err := s.store.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to refresh review case: %w", err)
+	}
+	log.Printf("[INFO] Review case %s for merchant %s refreshed with decision %s (%s)",
+		existing.ID, decision.MerchantID, decision.ID, decision.RiskLevel)
+	return nil
+}
+
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*Case, error) {
+	c, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCaseNotFound, err)
+	}
+	c.applySLA(time.Now())
+	return c, nil
+}
+
+// List returns the queue matching filter, most urgent SLA first. Without a
+// status filter only unresolved cases are returned.
+func (s *Service) List(ctx context.Context, filter Filter) ([]Case, int64, error) {
+	if len(filter.Statuses) == 0 {
+		filter.Statuses = UnresolvedStatuses
+	}
+
+	now := time.Now()
+	cases, total, err := s.store.List(ctx, filter, now)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range cases {
+		cases[i].applySLA(now)
+	}
+	return cases, total, nil
+}
+
+// Assign hands the case to a reviewer. An open case moves to in_review.
+func (s *Service) Assign(ctx context.Context, id uuid.UUID, assignee, by string) (*Case, error) {
+	assignee = strings.TrimSpace(assignee)
+	if assignee == "" {
+		return nil, fmt.Errorf("%w: assignee is required", ErrInvalidCase)
+	}
+
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status.Resolved() {
+		return nil, fmt.Errorf("%w: case is already %s", ErrInvalidTransition, c.Status)
+	}
+
+	note := fmt.Sprintf("Assigned to %s", assignee)
+	c.Assignee = assignee
+	if c.Status == StatusOpen {
+		c.Status = StatusInReview
+		note += fmt.Sprintf("; status changed from %s to %s", StatusOpen, StatusInReview)
+	}
+
+	if err := s.save(ctx, c, by, note); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Transition moves the case to status, recording who moved it and, when
+// given, why.
+func (s *Service) Transition(ctx context.Context, id uuid.UUID, status Status, by, comment string) (*Case, error) {
+	if !status.Valid() {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidCase, status)
+	}
+
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Status.CanMoveTo(status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, c.Status, status)
+	}
+
+	note := fmt.Sprintf("Status changed from %s to %s", c.Status, status)
+	if comment = strings.TrimSpace(comment); comment != "" {
+		note += ": " + comment
+	}
+
+	c.Status = status
+	if status.Resolved() {
+		now := time.Now()
+		c.ResolvedAt = &now
+	}
+
+	if err := s.save(ctx, c, by, note); err != nil {
+		return nil, err
+	}
+	c.applySLA(time.Now())
+
+	log.Printf("[INFO] Review case %s for merchant %s moved to %s by %s", c.ID, c.MerchantID, status, by)
+	return c, nil
+}
+
+func (s *Service) AddComment(ctx context.Context, id uuid.UUID, author, body string) (*Comment, error) {
+	author, body = strings.TrimSpace(author), strings.TrimSpace(body)
+	if author == "" || body == "" {
+		return nil, fmt.Errorf("%w: author and body are required", ErrInvalidCase)
+	}
+
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	comment := &Comment{CaseID: id, Author: author, Body: body}
+	if err := s.store.AddComment(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to add review comment: %w", err)
+	}
+	return comment, nil
+}
+
+// save persists a change to the case and records it as a comment so the
+// comment thread doubles as the case's audit trail.
+func (s *Service) save(ctx context.Context, c *Case, by, note string) error {
+	by = strings.TrimSpace(by)
+	if by == "" {
+		return fmt.Errorf("%w: author is required", ErrInvalidCase)
+	}
+
+	c.UpdatedAt = time.Now()
+	if err := s.store.Update(ctx, c); err != nil {
+		return fmt.Errorf("failed to update review case: %w", err)
+	}
+
+	comment := Comment{CaseID: c.ID, Author: by, Body: note}
+	if err := s.store.AddComment(ctx, &comment); err != nil {
+		return fmt.Errorf("failed to add review comment: %w", err)
+	}
+	c.Comments = append(c.Comments, comment)
+	return nil
+}
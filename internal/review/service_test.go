@@ -0,0 +1,190 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+type mockCaseRepository struct {
+	cases    map[uuid.UUID]*Case
+	comments []Comment
+	// staleReads makes GetUnresolvedByMerchant miss existing cases, as when
+	// another evaluation opens one between the check and the insert.
+	staleReads int
+}
+
+func newMockCaseRepository() *mockCaseRepository {
+	return &mockCaseRepository{cases: make(map[uuid.UUID]*Case)}
+}
+
+func (m *mockCaseRepository) Create(ctx context.Context, c *Case) error {
+	for _, existing := range m.cases {
+		if existing.MerchantID == c.MerchantID && !existing.Status.Resolved() {
+			return ErrCaseExists
+		}
+	}
+	c.ID = uuid.New()
+	c.CreatedAt = time.Now()
+	stored := *c
+	m.cases[c.ID] = &stored
+	return nil
+}
+
+func (m *mockCaseRepository) Get(ctx context.Context, id uuid.UUID) (*Case, error) {
+	c, ok := m.cases[id]
+	if !ok {
+		return nil, errors.New("review case not found")
+	}
+	found := *c
+	return &found, nil
+}
+
+func (m *mockCaseRepository) GetUnresolvedByMerchant(ctx context.Context, merchantID uuid.UUID) (*Case, error) {
+	if m.staleReads > 0 {
+		m.staleReads--
+		return nil, nil
+	}
+	for _, c := range m.cases {
+		if c.MerchantID == merchantID && !c.Status.Resolved() {
+			found := *c
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockCaseRepository) List(ctx context.Context, filter Filter, now time.Time) ([]Case, int64, error) {
+	var cases []Case
+	for _, c := range m.cases {
+		cases = append(cases, *c)
+	}
+	return cases, int64(len(cases)), nil
+}
+
+func (m *mockCaseRepository) Update(ctx context.Context, c *Case) error {
+	stored := *c
+	m.cases[c.ID] = &stored
+	return nil
+}
+
+func (m *mockCaseRepository) AddComment(ctx context.Context, comment *Comment) error {
+	m.comments = append(m.comments, *comment)
+	return nil
+}
+
+func decisionAt(merchantID uuid.UUID, level risk.RiskLevel, at time.Time) *risk.RiskDecision {
+	return &risk.RiskDecision{
+		ID:          uuid.New(),
+		MerchantID:  merchantID,
+		RiskLevel:   level,
+		RiskScore:   70,
+		EvaluatedAt: at,
+	}
+}
+
+func TestOpenCase(t *testing.T) {
+	store := newMockCaseRepository()
+	service := NewService(store, nil)
+	merchantID := uuid.New()
+	now := time.Now()
+
+	if err := service.OpenCase(context.Background(), decisionAt(merchantID, risk.RiskLevelHigh, now)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.cases) != 1 {
+		t.Fatalf("expected 1 case, got %d", len(store.cases))
+	}
+
+	critical := decisionAt(merchantID, risk.RiskLevelCritical, now.Add(time.Hour))
+	if err := service.OpenCase(context.Background(), critical); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.cases) != 1 {
+		t.Fatalf("expected the open case to be refreshed, got %d cases", len(store.cases))
+	}
+
+	for _, c := range store.cases {
+		if c.DecisionID != critical.ID || c.RiskLevel != risk.RiskLevelCritical {
+			t.Errorf("expected case to point at the CRITICAL decision, got %+v", c)
+		}
+		if want := critical.EvaluatedAt.Add(4 * time.Hour); !c.SLADueAt.Equal(want) {
+			t.Errorf("expected SLA to tighten to %s, got %s", want, c.SLADueAt)
+		}
+	}
+
+	t.Run("concurrent open", func(t *testing.T) {
+		store.staleReads = 1
+		latest := decisionAt(merchantID, risk.RiskLevelHigh, now.Add(2*time.Hour))
+		if err := service.OpenCase(context.Background(), latest); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(store.cases) != 1 {
+			t.Fatalf("expected the case opened concurrently to be refreshed, got %d cases", len(store.cases))
+		}
+		for _, c := range store.cases {
+			if c.DecisionID != latest.ID {
+				t.Errorf("expected case to point at the latest decision, got %s", c.DecisionID)
+			}
+		}
+	})
+}
+
+func TestCaseWorkflow(t *testing.T) {
+	store := newMockCaseRepository()
+	service := NewService(store, nil)
+	merchantID := uuid.New()
+
+	if err := service.OpenCase(context.Background(), decisionAt(merchantID, risk.RiskLevelHigh, time.Now().Add(-48*time.Hour))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var id uuid.UUID
+	for caseID := range store.cases {
+		id = caseID
+	}
+
+	rc, err := service.Assign(context.Background(), id, "analyst-1", "lead-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rc.Status != StatusInReview || rc.Assignee != "analyst-1" {
+		t.Errorf("expected assigned case in review, got %s/%s", rc.Status, rc.Assignee)
+	}
+	if !rc.SLABreached {
+		t.Error("expected case past its due time to be breached")
+	}
+
+	if _, err := service.Transition(context.Background(), id, "closed", "analyst-1", ""); !errors.Is(err, ErrInvalidCase) {
+		t.Errorf("expected ErrInvalidCase for unknown status, got %v", err)
+	}
+	if _, err := service.Transition(context.Background(), id, StatusApproved, "", ""); !errors.Is(err, ErrInvalidCase) {
+		t.Errorf("expected ErrInvalidCase without an author, got %v", err)
+	}
+
+	rc, err = service.Transition(context.Background(), id, StatusApproved, "analyst-1", "Chargebacks explained by one disputed batch")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rc.ResolvedAt == nil {
+		t.Error("expected approved case to be resolved")
+	}
+
+	if _, err := service.Transition(context.Background(), id, StatusInReview, "analyst-1", ""); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition from approved, got %v", err)
+	}
+	if _, err := service.Assign(context.Background(), id, "analyst-2", "lead-1"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition assigning a resolved case, got %v", err)
+	}
+
+	if len(store.comments) != 2 {
+		t.Errorf("expected assignment and approval in the comment trail, got %d comments", len(store.comments))
+	}
+
+	if _, err := service.Get(context.Background(), uuid.New()); !errors.Is(err, ErrCaseNotFound) {
+		t.Errorf("expected ErrCaseNotFound, got %v", err)
+	}
+}
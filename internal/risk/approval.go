@@ -0,0 +1,180 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DecisionStatus string
+
+const (
+	DecisionEffective       DecisionStatus = "EFFECTIVE"
+	DecisionPendingApproval DecisionStatus = "PENDING_APPROVAL"
+	DecisionRejected        DecisionStatus = "REJECTED"
+	// DecisionSuperseded marks a pending decision replaced by a newer decision
+	// for the same merchant before it was reviewed. It never takes effect.
+	DecisionSuperseded DecisionStatus = "SUPERSEDED"
+)
+
+var (
+	ErrApprovalDisabled   = errors.New("decision approval is not enabled")
+	ErrNotApprover        = errors.New("user is not authorized to review decisions")
+	ErrDecisionNotPending = errors.New("decision is not pending approval")
+	ErrDecisionNotFound   = errors.New("decision not found")
+)
+
+// ApprovalPolicy holds decisions at or above MinRiskLevel as PENDING_APPROVAL
+// until an approver reviews them. Meanwhile the merchant's last effective
+// policy stays in force, or the default hold and reserve when it has none.
+// Approval is off when MinRiskLevel is empty. With no Approvers listed any
+// identified user may review.
+type ApprovalPolicy struct {
+	MinRiskLevel             RiskLevel
+	DefaultHoldPeriod        HoldPeriod
+	DefaultReservePercentage int
+	Approvers                []string
+}
+
+// PendingApproval records the policy that stays in force while a decision
+// waits for approval.
+type PendingApproval struct {
+	EffectiveDecisionID        *uuid.UUID `json:"effective_decision_id,omitempty"`
+	EffectiveHoldPeriod        HoldPeriod `json:"effective_hold_period"`
+	EffectiveReservePercentage int        `json:"effective_reserve_percentage"`
+	SafeDefault                bool       `json:"safe_default,omitempty"`
+}
+
+func (p ApprovalPolicy) Enabled() bool {
+	return p.MinRiskLevel != ""
+}
+
+func (p ApprovalPolicy) Requires(level RiskLevel) bool {
+	return p.Enabled() && level.AtLeast(p.MinRiskLevel)
+}
+
+func (p ApprovalPolicy) CanReview(user string) bool {
+	if user == "" {
+		return false
+	}
+	if len(p.Approvers) == 0 {
+		return true
+	}
+	for _, approver := range p.Approvers {
+		if approver == user {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the policy's levels and defaults are known values.
+func (p ApprovalPolicy) Validate() error {
+	if !p.Enabled() {
+		return nil
+	}
+	if _, ok := riskLevelRank[p.MinRiskLevel]; !ok {
+		return fmt.Errorf("unknown approval risk level %q", p.MinRiskLevel)
+	}
+	if holdPeriodRank(p.DefaultHoldPeriod) < 0 {
+		return fmt.Errorf("unknown approval default hold period %q", p.DefaultHoldPeriod)
+	}
+	if p.DefaultReservePercentage < 0 || p.DefaultReservePercentage > 100 {
+		return fmt.Errorf("approval default reserve must be between 0 and 100")
+	}
+	return nil
+}
+
+type ApprovalRepository interface {
+	// Get returns ErrDecisionNotFound when no decision has the ID.
+	Get(ctx context.Context, id uuid.UUID) (*RiskDecision, error)
+	// GetLatestEffectiveByMerchant returns the merchant's newest persisted
+	// decision in force, or nil when there is none.
+	GetLatestEffectiveByMerchant(ctx context.Context, merchantID uuid.UUID) (*RiskDecision, error)
+	// Review moves a pending decision to status and fails if it is no longer
+	// pending.
+	Review(ctx context.Context, id uuid.UUID, status DecisionStatus, reviewedBy, note string, at time.Time) error
+}
+
+// WithApproval holds decisions at or above the policy's risk level for
+// approval before their hold and reserve take effect.
+func WithApproval(policy ApprovalPolicy, decisions ApprovalRepository) Option {
+	return func(s *Service) {
+		s.approval = policy
+		s.approvals = decisions
+	}
+}
+
+// interimPolicy returns the policy that stays in force while a decision for
+// the merchant is pending approval.
+func (s *Service) interimPolicy(ctx context.Context, merchantID uuid.UUID) (*PendingApproval, error) {
+	effective, err := s.approvals.GetLatestEffectiveByMerchant(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if effective == nil {
+		return &PendingApproval{
+			EffectiveHoldPeriod:        s.approval.DefaultHoldPeriod,
+			EffectiveReservePercentage: s.approval.DefaultReservePercentage,
+			SafeDefault:                true,
+		}, nil
+	}
+
+	return &PendingApproval{
+		EffectiveDecisionID:        &effective.ID,
+		EffectiveHoldPeriod:        effective.PayoutHoldPeriod,
+		EffectiveReservePercentage: effective.RollingReservePercentage,
+	}, nil
+}
+
+// ApproveDecision puts a pending decision's policy into effect.
+func (s *Service) ApproveDecision(ctx context.Context, id uuid.UUID, reviewedBy, note string) (*RiskDecision, error) {
+	return s.reviewDecision(ctx, id, DecisionEffective, reviewedBy, note)
+}
+
+// RejectDecision discards a pending decision; the policy in force while it
+// was pending stays in force.
+func (s *Service) RejectDecision(ctx context.Context, id uuid.UUID, reviewedBy, note string) (*RiskDecision, error) {
+	return s.reviewDecision(ctx, id, DecisionRejected, reviewedBy, note)
+}
+
+func (s *Service) reviewDecision(ctx context.Context, id uuid.UUID, status DecisionStatus, reviewedBy, note string) (*RiskDecision, error) {
+	if s.approvals == nil || !s.approval.Enabled() {
+		return nil, ErrApprovalDisabled
+	}
+
+	reviewedBy = strings.TrimSpace(reviewedBy)
+	if !s.approval.CanReview(reviewedBy) {
+		return nil, fmt.Errorf("%w: %q", ErrNotApprover, reviewedBy)
+	}
+
+	decision, err := s.approvals.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if decision.Status != DecisionPendingApproval {
+		return nil, fmt.Errorf("%w: %s is %s", ErrDecisionNotPending, id, decision.Status)
+	}
+
+	now := time.Now()
+	note = strings.TrimSpace(note)
+	if err := s.approvals.Review(ctx, id, status, reviewedBy, note, now); err != nil {
+		return nil, fmt.Errorf("failed to review decision: %w", err)
+	}
+
+	decision.Status = status
+	decision.ReviewedBy = reviewedBy
+	decision.ReviewedAt = &now
+	decision.ReviewNote = note
+
+	log.Printf("[INFO] Decision %s for merchant %s %s by %s (%s, hold=%s, reserve=%d%%)",
+		id, decision.MerchantID, strings.ToLower(string(status)), reviewedBy,
+		decision.RiskLevel, decision.PayoutHoldPeriod, decision.RollingReservePercentage)
+	return decision, nil
+}
@@ -0,0 +1,193 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+type mockApprovalRepository struct {
+	decisions map[uuid.UUID]*RiskDecision
+	effective *RiskDecision
+}
+
+func (m *mockApprovalRepository) Get(ctx context.Context, id uuid.UUID) (*RiskDecision, error) {
+	d, ok := m.decisions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDecisionNotFound, id)
+	}
+	found := *d
+	return &found, nil
+}
+
+func (m *mockApprovalRepository) GetLatestEffectiveByMerchant(ctx context.Context, merchantID uuid.UUID) (*RiskDecision, error) {
+	return m.effective, nil
+}
+
+func (m *mockApprovalRepository) Review(ctx context.Context, id uuid.UUID, status DecisionStatus, reviewedBy, note string, at time.Time) error {
+	m.decisions[id].Status = status
+	return nil
+}
+
+func criticalMerchant() *merchant.Merchant {
+	return &merchant.Merchant{
+		ID:                   uuid.New(),
+		Industry:             "TRAVEL",
+		AccountAgeDays:       10,
+		TransactionVolume30d: decimal.NewFromFloat(20000),
+		ChargebackRate:       decimal.NewFromFloat(3.5),
+		VelocityMultiplier:   decimal.NewFromFloat(4.0),
+		RefundRate:           decimal.NewFromFloat(7.0),
+	}
+}
+
+var testApprovalPolicy = ApprovalPolicy{
+	MinRiskLevel:             RiskLevelCritical,
+	DefaultHoldPeriod:        HoldPeriod45Days,
+	DefaultReservePercentage: 25,
+	Approvers:                []string{"risk-lead"},
+}
+
+func TestEvaluateMerchantPendingApproval(t *testing.T) {
+	m := criticalMerchant()
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return m, nil
+		},
+	}
+
+	t.Run("previous effective policy stays in force", func(t *testing.T) {
+		previous := &RiskDecision{ID: uuid.New(), PayoutHoldPeriod: HoldPeriod7Days}
+		approvals := &mockApprovalRepository{effective: previous}
+		service := NewService(merchantStore, &mockDecisionRepository{}, WithApproval(testApprovalPolicy, approvals))
+
+		decision, err := service.EvaluateMerchant(context.Background(), m.ID, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if decision.Status != DecisionPendingApproval || decision.RiskLevel != RiskLevelCritical {
+			t.Fatalf("expected pending CRITICAL decision, got %s %s", decision.Status, decision.RiskLevel)
+		}
+		interim := decision.Reasoning.PendingApproval
+		if interim == nil || interim.SafeDefault || *interim.EffectiveDecisionID != previous.ID ||
+			interim.EffectiveHoldPeriod != HoldPeriod7Days {
+			t.Errorf("expected previous policy in force, got %+v", interim)
+		}
+	})
+
+	t.Run("safe default without a previous policy", func(t *testing.T) {
+		approvals := &mockApprovalRepository{}
+		service := NewService(merchantStore, &mockDecisionRepository{}, WithApproval(testApprovalPolicy, approvals))
+
+		decision, err := service.EvaluateMerchant(context.Background(), m.ID, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		interim := decision.Reasoning.PendingApproval
+		if interim == nil || !interim.SafeDefault || interim.EffectiveReservePercentage != 25 {
+			t.Errorf("expected safe default in force, got %+v", interim)
+		}
+	})
+
+	t.Run("levels below the threshold take effect", func(t *testing.T) {
+		low := &merchant.Merchant{ID: uuid.New(), Industry: "RETAIL", AccountAgeDays: 800, KYCVerified: true, KYCLevel: "ENHANCED",
+			ChargebackRate: decimal.NewFromFloat(0.3), VelocityMultiplier: decimal.NewFromFloat(1.0), RefundRate: decimal.NewFromFloat(1.0)}
+		service := NewService(&mockMerchantRepository{
+			getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) { return low, nil },
+		}, &mockDecisionRepository{}, WithApproval(testApprovalPolicy, &mockApprovalRepository{}))
+
+		decision, err := service.EvaluateMerchant(context.Background(), low.ID, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if decision.Status != DecisionEffective || decision.Reasoning.PendingApproval != nil {
+			t.Errorf("expected effective decision, got %s", decision.Status)
+		}
+	})
+}
+
+func TestGetMerchantProfilePendingApproval(t *testing.T) {
+	m := criticalMerchant()
+	pending := &RiskDecision{ID: uuid.New(), MerchantID: m.ID, RiskScore: 90, RiskLevel: RiskLevelCritical,
+		PayoutHoldPeriod: HoldPeriod45Days, RollingReservePercentage: 20, Status: DecisionPendingApproval}
+	effective := &RiskDecision{ID: uuid.New(), MerchantID: m.ID, RiskScore: 15, RiskLevel: RiskLevelLow,
+		PayoutHoldPeriod: HoldPeriodImmediate, Status: DecisionEffective}
+
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return m, nil
+		},
+	}
+	decisionStore := &mockDecisionRepository{
+		getLatestByMerchant: func(ctx context.Context, merchantID uuid.UUID) (*RiskDecision, error) {
+			return pending, nil
+		},
+	}
+	approvals := &mockApprovalRepository{effective: effective}
+	service := NewService(merchantStore, decisionStore, WithApproval(testApprovalPolicy, approvals))
+
+	profile, err := service.GetMerchantProfile(context.Background(), m.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.CurrentPolicy == nil || profile.CurrentPolicy.PayoutHoldPeriod != "IMMEDIATE" || profile.CurrentPolicy.Status != "EFFECTIVE" {
+		t.Errorf("expected effective IMMEDIATE policy, got %+v", profile.CurrentPolicy)
+	}
+	if profile.PendingPolicy == nil || profile.PendingPolicy.PayoutHoldPeriod != "45_DAYS" || profile.PendingPolicy.Status != "PENDING_APPROVAL" {
+		t.Errorf("expected pending 45_DAYS policy, got %+v", profile.PendingPolicy)
+	}
+
+	approvals.effective = nil
+	profile, err = service.GetMerchantProfile(context.Background(), m.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.CurrentPolicy == nil || profile.CurrentPolicy.Status != "SAFE_DEFAULT" || profile.CurrentPolicy.RollingReservePercentage != 25 {
+		t.Errorf("expected safe default policy, got %+v", profile.CurrentPolicy)
+	}
+}
+
+func TestReviewDecision(t *testing.T) {
+	id := uuid.New()
+	approvals := &mockApprovalRepository{decisions: map[uuid.UUID]*RiskDecision{
+		id: {ID: id, RiskLevel: RiskLevelCritical, Status: DecisionPendingApproval},
+	}}
+	service := NewService(&mockMerchantRepository{}, &mockDecisionRepository{}, WithApproval(testApprovalPolicy, approvals))
+
+	if _, err := service.ApproveDecision(context.Background(), id, "analyst-1", ""); !errors.Is(err, ErrNotApprover) {
+		t.Errorf("expected ErrNotApprover, got %v", err)
+	}
+
+	decision, err := service.ApproveDecision(context.Background(), id, "risk-lead", "Confirmed with merchant")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Status != DecisionEffective || decision.ReviewedBy != "risk-lead" || decision.ReviewedAt == nil {
+		t.Errorf("expected approved decision, got %+v", decision)
+	}
+
+	if _, err := service.RejectDecision(context.Background(), id, "risk-lead", ""); !errors.Is(err, ErrDecisionNotPending) {
+		t.Errorf("expected ErrDecisionNotPending, got %v", err)
+	}
+
+	superseded := uuid.New()
+	approvals.decisions[superseded] = &RiskDecision{ID: superseded, RiskLevel: RiskLevelCritical, Status: DecisionSuperseded}
+	if _, err := service.ApproveDecision(context.Background(), superseded, "risk-lead", ""); !errors.Is(err, ErrDecisionNotPending) {
+		t.Errorf("expected ErrDecisionNotPending for a superseded decision, got %v", err)
+	}
+
+	if _, err := service.ApproveDecision(context.Background(), uuid.New(), "risk-lead", ""); !errors.Is(err, ErrDecisionNotFound) {
+		t.Errorf("expected ErrDecisionNotFound, got %v", err)
+	}
+
+	disabled := NewService(&mockMerchantRepository{}, &mockDecisionRepository{})
+	if _, err := disabled.ApproveDecision(context.Background(), id, "risk-lead", ""); !errors.Is(err, ErrApprovalDisabled) {
+		t.Errorf("expected ErrApprovalDisabled, got %v", err)
+	}
+}
@@ -0,0 +1,353 @@
+package risk
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+var (
+	ErrInvalidBacktest  = errors.New("invalid backtest candidate")
+	ErrNoDecisionInputs = errors.New("decision has no recorded inputs")
+)
+
+// DecisionInputs are the merchant attributes and metrics a decision was
+// scored on, recorded with the decision so it can be replayed later.
+type DecisionInputs struct {
+	Industry             string          `json:"industry"`
+	Country              string          `json:"country"`
+	AccountAgeDays       int             `json:"account_age_days"`
+	TransactionVolume30d decimal.Decimal `json:"transaction_volume_30d"`
+	TransactionCount30d  int             `json:"transaction_count_30d"`
+	AvgTicketSize        decimal.Decimal `json:"avg_ticket_size"`
+	ChargebackCount30d   int             `json:"chargeback_count_30d"`
+	ChargebackRate       decimal.Decimal `json:"chargeback_rate"`
+	RefundRate           decimal.Decimal `json:"refund_rate"`
+	VelocityMultiplier   decimal.Decimal `json:"velocity_multiplier"`
+	KYCVerified          bool            `json:"kyc_verified"`
+	KYCLevel             string          `json:"kyc_level"`
+}
+
+func NewDecisionInputs(m *merchant.Merchant) *DecisionInputs {
+	return &DecisionInputs{
+		Industry:             m.Industry,
+		Country:              m.Country,
+		AccountAgeDays:       m.AccountAgeDays,
+		TransactionVolume30d: m.TransactionVolume30d,
+		TransactionCount30d:  m.TransactionCount30d,
+		AvgTicketSize:        m.AvgTicketSize,
+		ChargebackCount30d:   m.ChargebackCount30d,
+		ChargebackRate:       m.ChargebackRate,
+		RefundRate:           m.RefundRate,
+		VelocityMultiplier:   m.VelocityMultiplier,
+		KYCVerified:          m.KYCVerified,
+		KYCLevel:             m.KYCLevel,
+	}
+}
+
+// Merchant rebuilds the merchant as it was scored.
+func (in DecisionInputs) Merchant(id uuid.UUID) *merchant.Merchant {
+	return &merchant.Merchant{
+		ID:                   id,
+		Industry:             in.Industry,
+		Country:              in.Country,
+		AccountAgeDays:       in.AccountAgeDays,
+		TransactionVolume30d: in.TransactionVolume30d,
+		TransactionCount30d:  in.TransactionCount30d,
+		AvgTicketSize:        in.AvgTicketSize,
+		ChargebackCount30d:   in.ChargebackCount30d,
+		ChargebackRate:       in.ChargebackRate,
+		RefundRate:           in.RefundRate,
+		VelocityMultiplier:   in.VelocityMultiplier,
+		KYCVerified:          in.KYCVerified,
+		KYCLevel:             in.KYCLevel,
+	}
+}
+
+func (in *DecisionInputs) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+	return json.Unmarshal(bytes, in)
+}
+
+func (in DecisionInputs) Value() (driver.Value, error) {
+	return json.Marshal(in)
+}
+
+// BacktestCandidate is the configuration a backtest replays historical
+// decisions under. Ruleset replaces the live ruleset and ScoringThresholds
+// adjust it, as in simulations. Tiers replace the tier table that was in
+// force when each decision was made.
+type BacktestCandidate struct {
+	Ruleset           *Ruleset           `json:"ruleset,omitempty"`
+	ScoringThresholds *ScoringThresholds `json:"scoring_thresholds,omitempty"`
+	Tiers             []PolicyTier       `json:"tiers,omitempty"`
+}
+
+func (c BacktestCandidate) Validate() error {
+	if c.Ruleset != nil {
+		if err := c.Ruleset.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidBacktest, err)
+		}
+	}
+	if c.Tiers != nil {
+		if err := ValidateTiers(c.Tiers); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidBacktest, err)
+		}
+	}
+	return nil
+}
+
+func (c *BacktestCandidate) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+func (c BacktestCandidate) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Replay scores recorded decision inputs under a backtest candidate and,
+// for comparison, under the live ruleset.
+type Replay struct {
+	service   *Service
+	baseline  *Evaluator
+	evaluator *Evaluator
+	tiers     *PolicyMapper
+	overrides map[uuid.UUID]*PolicyOverride
+}
+
+// NewReplay prepares candidate for replaying.
+func (s *Service) NewReplay(ctx context.Context, candidate BacktestCandidate) (*Replay, error) {
+	if err := candidate.Validate(); err != nil {
+		return nil, err
+	}
+
+	evaluator := s.evaluator
+	if candidate.Ruleset != nil {
+		evaluator = NewEvaluatorFromRuleset(candidate.Ruleset)
+	}
+	if !candidate.ScoringThresholds.Empty() {
+		evaluator = evaluator.WithThresholds(*candidate.ScoringThresholds)
+		if err := evaluator.Ruleset().Validate(); err != nil {
+			return nil, fmt.Errorf("%w: scoring_thresholds: %v", ErrInvalidBacktest, err)
+		}
+	}
+
+	replay := &Replay{
+		service:   s,
+		baseline:  s.evaluator,
+		evaluator: evaluator,
+		overrides: make(map[uuid.UUID]*PolicyOverride),
+	}
+	if candidate.Tiers != nil {
+		replay.tiers = NewPolicyMapperFromTiers("backtest", candidate.Tiers)
+	}
+	return replay, nil
+}
+
+// Version identifies the candidate's scoring model.
+func (r *Replay) Version() string {
+	return r.evaluator.Version()
+}
+
+// BacktestRow compares the baseline and candidate replays of one historical
+// decision.
+type BacktestRow struct {
+	DecisionID                 uuid.UUID       `json:"decision_id"`
+	MerchantID                 uuid.UUID       `json:"merchant_id"`
+	EvaluatedAt                time.Time       `json:"evaluated_at"`
+	TransactionVolume30d       decimal.Decimal `json:"transaction_volume_30d" gorm:"column:transaction_volume_30d"`
+	BaselineScore              int             `json:"baseline_score"`
+	CandidateScore             int             `json:"candidate_score"`
+	BaselineRiskLevel          RiskLevel       `json:"baseline_risk_level"`
+	CandidateRiskLevel         RiskLevel       `json:"candidate_risk_level"`
+	BaselineHoldPeriod         HoldPeriod      `json:"baseline_hold_period"`
+	CandidateHoldPeriod        HoldPeriod      `json:"candidate_hold_period"`
+	BaselineReservePercentage  int             `json:"baseline_reserve_percentage"`
+	CandidateReservePercentage int             `json:"candidate_reserve_percentage"`
+}
+
+// Replay scores d's recorded inputs with the live ruleset (the baseline) and
+// with the candidate. Both sides use the tier table and market override in
+// force when d was made, and neither replays trend points, hysteresis or
+// analyst overrides, so the rows differ only where the candidate does. It
+// returns ErrNoDecisionInputs for decisions made before inputs were
+// recorded.
+func (r *Replay) Replay(ctx context.Context, d *RiskDecision) (*BacktestRow, error) {
+	if d.Inputs == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoDecisionInputs, d.ID)
+	}
+	m := d.Inputs.Merchant(d.MerchantID)
+
+	policy, err := r.service.policyAt(ctx, d.EvaluatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve policy tiers: %w", err)
+	}
+	candidatePolicy := policy
+	if r.tiers != nil {
+		candidatePolicy = r.tiers
+	}
+	override, err := r.policyOverride(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	if override != nil {
+		policy = override.Apply(policy)
+		candidatePolicy = override.Apply(candidatePolicy)
+	}
+
+	baselineScore, baseline := replayTier(r.baseline, policy, m)
+	score, tier := replayTier(r.evaluator, candidatePolicy, m)
+
+	return &BacktestRow{
+		DecisionID:                 d.ID,
+		MerchantID:                 d.MerchantID,
+		EvaluatedAt:                d.EvaluatedAt,
+		TransactionVolume30d:       d.Inputs.TransactionVolume30d,
+		BaselineScore:              baselineScore,
+		CandidateScore:             score,
+		BaselineRiskLevel:          baseline.RiskLevel,
+		CandidateRiskLevel:         tier.RiskLevel,
+		BaselineHoldPeriod:         baseline.HoldPeriod,
+		CandidateHoldPeriod:        tier.HoldPeriod,
+		BaselineReservePercentage:  baseline.ReservePercentage,
+		CandidateReservePercentage: tier.ReservePercentage,
+	}, nil
+}
+
+func replayTier(evaluator *Evaluator, policy *PolicyMapper, m *merchant.Merchant) (int, PolicyTier) {
+	score, _ := evaluator.Score(m)
+	tier, _ := ApplyHardStops(evaluator.Ruleset().HardStops, m, policy, policy.DeterminePolicyTier(score))
+	return score, tier
+}
+
+// policyOverride returns the market override recorded on d. Adjustments are
+// rebuilt from the record; replacement tiers are loaded once per override,
+// whether or not it is still active.
+func (r *Replay) policyOverride(ctx context.Context, d *RiskDecision) (*PolicyOverride, error) {
+	applied := d.Reasoning.PolicyOverride
+	if applied == nil {
+		return nil, nil
+	}
+	if applied.Mode != PolicyOverrideModeReplace {
+		return &PolicyOverride{
+			ID:                  applied.ID,
+			Name:                applied.Name,
+			Mode:                applied.Mode,
+			HoldAdjustmentSteps: applied.HoldAdjustmentSteps,
+			ReserveAdjustment:   applied.ReserveAdjustment,
+		}, nil
+	}
+
+	if override, ok := r.overrides[applied.ID]; ok {
+		return override, nil
+	}
+	if r.service.overrides == nil {
+		return nil, fmt.Errorf("policy override %s is not available to replay", applied.ID)
+	}
+	override, err := r.service.overrides.Get(ctx, applied.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy override %s: %w", applied.ID, err)
+	}
+	r.overrides[applied.ID] = override
+	return override, nil
+}
+
+// BacktestSummary aggregates replayed decisions. Volumes are the merchants'
+// 30-day transaction volume at decision time; reserves are volume times
+// reserve percentage.
+type BacktestSummary struct {
+	Decisions            int64               `json:"decisions"`
+	SkippedWithoutInputs int64               `json:"skipped_without_inputs"`
+	TotalVolume          decimal.Decimal     `json:"total_volume"`
+	TierChanges          BacktestChanges     `json:"tier_changes"`
+	HoldChanges          BacktestChanges     `json:"hold_changes"`
+	ReserveChanges       BacktestChanges     `json:"reserve_changes"`
+	BaselineReserve      decimal.Decimal     `json:"baseline_reserve"`
+	CandidateReserve     decimal.Decimal     `json:"candidate_reserve"`
+	ReserveDelta         decimal.Decimal     `json:"reserve_delta"`
+	TierMigration        TierMigrationMatrix `json:"tier_migration"`
+}
+
+// BacktestChanges counts decisions whose tier, hold period or reserve the
+// candidate would raise or lower, and the volume behind them. VolumeShare is
+// the changed volume as a share of total volume.
+type BacktestChanges struct {
+	Count           int64           `json:"count"`
+	Increased       int64           `json:"increased"`
+	Decreased       int64           `json:"decreased"`
+	Volume          decimal.Decimal `json:"volume"`
+	IncreasedVolume decimal.Decimal `json:"increased_volume"`
+	DecreasedVolume decimal.Decimal `json:"decreased_volume"`
+	VolumeShare     float64         `json:"volume_share"`
+}
+
+func NewBacktestSummary() *BacktestSummary {
+	return &BacktestSummary{TierMigration: NewTierMigrationMatrix()}
+}
+
+func (s *BacktestSummary) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+func (s BacktestSummary) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Add folds a replayed decision into the summary.
+func (s *BacktestSummary) Add(row *BacktestRow) {
+	volume := row.TransactionVolume30d
+	s.Decisions++
+	s.TotalVolume = s.TotalVolume.Add(volume)
+	s.TierMigration.Add(row.BaselineRiskLevel, row.CandidateRiskLevel, 1)
+
+	s.TierChanges.add(riskLevelRank[row.CandidateRiskLevel]-riskLevelRank[row.BaselineRiskLevel], volume)
+	s.HoldChanges.add(holdPeriodRank(row.CandidateHoldPeriod)-holdPeriodRank(row.BaselineHoldPeriod), volume)
+	s.ReserveChanges.add(row.CandidateReservePercentage-row.BaselineReservePercentage, volume)
+
+	s.BaselineReserve = s.BaselineReserve.Add(reserveAmount(volume, row.BaselineReservePercentage))
+	s.CandidateReserve = s.CandidateReserve.Add(reserveAmount(volume, row.CandidateReservePercentage))
+	s.ReserveDelta = s.CandidateReserve.Sub(s.BaselineReserve)
+
+	for _, c := range []*BacktestChanges{&s.TierChanges, &s.HoldChanges, &s.ReserveChanges} {
+		if s.TotalVolume.IsPositive() {
+			c.VolumeShare = c.Volume.Div(s.TotalVolume).InexactFloat64()
+		}
+	}
+}
+
+func (c *BacktestChanges) add(direction int, volume decimal.Decimal) {
+	if direction == 0 {
+		return
+	}
+	c.Count++
+	c.Volume = c.Volume.Add(volume)
+	if direction > 0 {
+		c.Increased++
+		c.IncreasedVolume = c.IncreasedVolume.Add(volume)
+	} else {
+		c.Decreased++
+		c.DecreasedVolume = c.DecreasedVolume.Add(volume)
+	}
+}
+
+func reserveAmount(volume decimal.Decimal, percentage int) decimal.Decimal {
+	return volume.Mul(decimal.NewFromInt(int64(percentage))).Div(decimal.NewFromInt(100))
+}
@@ -0,0 +1,349 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+const (
+	// maxTierPathChanges bounds how many metrics a single option may change.
+	maxTierPathChanges = 3
+	// maxTierPathOptions bounds how many ranked options are returned.
+	maxTierPathOptions = 10
+	// maxAccountAgeWaitDays is the longest wait for account age we still
+	// consider a realistic recommendation.
+	maxAccountAgeWaitDays = 180
+)
+
+// TierPath lists the smallest changes found that would move a merchant to a
+// better tier than the one it is in today.
+type TierPath struct {
+	MerchantID               uuid.UUID        `json:"merchant_id"`
+	CurrentScore             int              `json:"current_score"`
+	CurrentRiskLevel         RiskLevel        `json:"current_risk_level"`
+	CurrentHoldPeriod        HoldPeriod       `json:"current_hold_period"`
+	CurrentReservePercentage int              `json:"current_reserve_percentage"`
+	BestTier                 bool             `json:"best_tier"`
+	Options                  []TierPathOption `json:"options"`
+}
+
+// TierPathOption is one set of metric changes and the policy it would earn.
+// Effort is a heuristic used for ranking: relative reduction for rates, a
+// fixed cost per KYC upgrade step and elapsed time for account age.
+type TierPathOption struct {
+	Rank                       int            `json:"rank"`
+	Changes                    []MetricChange `json:"changes"`
+	ProjectedScore             int            `json:"projected_score"`
+	ProjectedRiskLevel         RiskLevel      `json:"projected_risk_level"`
+	ProjectedHoldPeriod        HoldPeriod     `json:"projected_hold_period"`
+	ProjectedReservePercentage int            `json:"projected_reserve_percentage"`
+	Effort                     float64        `json:"effort"`
+}
+
+type MetricChange struct {
+	Field       string      `json:"field"`
+	Current     interface{} `json:"current"`
+	Target      interface{} `json:"target"`
+	Description string      `json:"description"`
+}
+
+// leverOption is one candidate value for a single merchant metric.
+type leverOption struct {
+	change MetricChange
+	effort float64
+	apply  func(m *merchant.Merchant)
+}
+
+// PathToNextTier searches single metric changes and small combinations of them
+// for the options that would place the merchant in a better tier. Options are
+// minimal: a combination is dropped when a subset of its changes already
+// succeeds on its own.
+func (s *Service) PathToNextTier(ctx context.Context, merchantID uuid.UUID) (*TierPath, error) {
+	m, err := s.merchantStore.Get(ctx, merchantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merchant %s: %w", merchantID, err)
+	}
+
+	evaluatedAt := time.Now()
+	policy, _, err := s.resolvePolicy(ctx, m, evaluatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve policy tiers: %w", err)
+	}
+
+	history, err := s.trendHistory(ctx, s.scorer, merchantID, evaluatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metric history: %w", err)
+	}
+
+	rules := s.evaluator.Ruleset()
+	messages := catalogFor(LanguageFromContext(ctx))
+	project := func(candidate *merchant.Merchant) (int, PolicyTier) {
+		score, _, _ := scoreWithHistory(s.scorer, candidate, history, evaluatedAt)
+		tier, _ := ApplyHardStops(rules.HardStops, candidate, policy, policy.DeterminePolicyTier(score))
+		return score, tier
+	}
+
+	currentScore, currentTier := project(m)
+	path := &TierPath{
+		MerchantID:               merchantID,
+		CurrentScore:             currentScore,
+		CurrentRiskLevel:         currentTier.RiskLevel,
+		CurrentHoldPeriod:        currentTier.HoldPeriod,
+		CurrentReservePercentage: currentTier.ReservePercentage,
+		Options:                  []TierPathOption{},
+	}
+
+	if riskLevelRank[currentTier.RiskLevel] <= riskLevelRank[policy.Tiers()[0].RiskLevel] {
+		path.BestTier = true
+		return path, nil
+	}
+
+	levers := counterfactualLevers(m, rules, messages)
+	var successes [][]string
+	var options []TierPathOption
+
+	// Search by number of changes so smaller combinations are recorded before
+	// the larger ones that contain them.
+	for size := 1; size <= maxTierPathChanges; size++ {
+		for _, combo := range leverCombinations(levers, size) {
+			if containsSuccessfulSubset(successes, combo.keys) {
+				continue
+			}
+
+			candidate := *m
+			var effort float64
+			changes := make([]MetricChange, len(combo.options))
+			for i, opt := range combo.options {
+				opt.apply(&candidate)
+				effort += opt.effort
+				changes[i] = opt.change
+			}
+
+			score, tier := project(&candidate)
+			if riskLevelRank[tier.RiskLevel] >= riskLevelRank[currentTier.RiskLevel] {
+				continue
+			}
+
+			options = append(options, TierPathOption{
+				Changes:                    changes,
+				ProjectedScore:             score,
+				ProjectedRiskLevel:         tier.RiskLevel,
+				ProjectedHoldPeriod:        tier.HoldPeriod,
+				ProjectedReservePercentage: tier.ReservePercentage,
+				Effort:                     math.Round(effort*100) / 100,
+			})
+			successes = append(successes, combo.keys)
+		}
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		a, b := options[i], options[j]
+		if len(a.Changes) != len(b.Changes) {
+			return len(a.Changes) < len(b.Changes)
+		}
+		if a.Effort != b.Effort {
+			return a.Effort < b.Effort
+		}
+		return a.ProjectedScore < b.ProjectedScore
+	})
+
+	if len(options) > maxTierPathOptions {
+		options = options[:maxTierPathOptions]
+	}
+	for i := range options {
+		options[i].Rank = i + 1
+	}
+	path.Options = options
+
+	log.Printf("[INFO] Found %d next-tier options for merchant %s (current=%s)",
+		len(options), merchantID, currentTier.RiskLevel)
+	return path, nil
+}
+
+// leverCombination picks one option from each of several levers. Keys
+// identify the chosen options as "lever.option" for the minimality check.
+type leverCombination struct {
+	keys    []string
+	options []leverOption
+}
+
+// leverCombinations enumerates every combination of exactly size levers,
+// taking one option from each.
+func leverCombinations(levers [][]leverOption, size int) []leverCombination {
+	var combos []leverCombination
+
+	var walk func(start int, keys []string, options []leverOption)
+	walk = func(start int, keys []string, options []leverOption) {
+		if len(keys) == size {
+			combos = append(combos, leverCombination{
+				keys:    append([]string(nil), keys...),
+				options: append([]leverOption(nil), options...),
+			})
+			return
+		}
+		for i := start; i < len(levers); i++ {
+			for j, opt := range levers[i] {
+				walk(i+1, append(keys, fmt.Sprintf("%d.%d", i, j)), append(options, opt))
+			}
+		}
+	}
+	walk(0, nil, nil)
+
+	return combos
+}
+
+// containsSuccessfulSubset reports whether a strict subset of the chosen
+// options already succeeded, making this combination non-minimal.
+func containsSuccessfulSubset(successes [][]string, keys []string) bool {
+	for _, success := range successes {
+		if len(success) >= len(keys) {
+			continue
+		}
+		subset := true
+		for _, f := range success {
+			found := false
+			for _, g := range keys {
+				if f == g {
+					found = true
+					break
+				}
+			}
+			if !found {
+				subset = false
+				break
+			}
+		}
+		if subset {
+			return true
+		}
+	}
+	return false
+}
+
+// counterfactualLevers returns, per actionable metric, the candidate values
+// that would move it into each lower-scoring band. Business category is not
+// actionable and is never suggested.
+func counterfactualLevers(m *merchant.Merchant, rules *Ruleset, messages catalog) [][]leverOption {
+	var levers [][]leverOption
+
+	rateLever := func(field, key string, f BandedFactor, current, step float64, set func(*merchant.Merchant, float64)) {
+		var opts []leverOption
+		currentPoints := f.Score(current)
+		for _, band := range f.Bands {
+			if band.Below == nil || band.Points >= currentPoints || *band.Below > current {
+				continue
+			}
+			target := math.Max(0, *band.Below-step)
+			target = math.Round(target/step) * step
+			effort := 1.0
+			if current > 0 {
+				effort = (current - target) / current
+			}
+			opts = append(opts, leverOption{
+				change: MetricChange{
+					Field:       field,
+					Current:     current,
+					Target:      target,
+					Description: messages.format(key, current, target),
+				},
+				effort: effort,
+				apply:  func(c *merchant.Merchant) { set(c, target) },
+			})
+		}
+		if len(opts) > 0 {
+			levers = append(levers, opts)
+		}
+	}
+
+	rateLever("chargeback_rate", "counterfactual.chargeback_rate", rules.Chargeback,
+		m.ChargebackRate.InexactFloat64(), 0.01,
+		func(c *merchant.Merchant, v float64) { c.ChargebackRate = decimal.NewFromFloat(v) })
+	rateLever("velocity_multiplier", "counterfactual.velocity_multiplier", rules.Velocity,
+		m.VelocityMultiplier.InexactFloat64(), 0.1,
+		func(c *merchant.Merchant, v float64) { c.VelocityMultiplier = decimal.NewFromFloat(v) })
+	rateLever("refund_rate", "counterfactual.refund_rate", rules.Refund,
+		m.RefundRate.InexactFloat64(), 0.1,
+		func(c *merchant.Merchant, v float64) { c.RefundRate = decimal.NewFromFloat(v) })
+
+	if opts := kycLever(m, rules.KYC, messages); len(opts) > 0 {
+		levers = append(levers, opts)
+	}
+	if opts := accountAgeLever(m, rules.AccountAge, messages); len(opts) > 0 {
+		levers = append(levers, opts)
+	}
+
+	return levers
+}
+
+func kycLever(m *merchant.Merchant, f CategoricalFactor, messages catalog) []leverOption {
+	currentPoints := f.Default
+	if m.KYCVerified {
+		currentPoints = f.Score(m.KYCLevel)
+	}
+
+	levels := make([]string, 0, len(f.Values))
+	for level, points := range f.Values {
+		if points < currentPoints {
+			levels = append(levels, level)
+		}
+	}
+	// Prefer the upgrade that saves the fewest points first: it is the
+	// smallest step up from where the merchant is.
+	sort.Slice(levels, func(i, j int) bool {
+		return f.Values[levels[i]] > f.Values[levels[j]]
+	})
+
+	opts := make([]leverOption, 0, len(levels))
+	for i, level := range levels {
+		target := level
+		opts = append(opts, leverOption{
+			change: MetricChange{
+				Field:       "kyc_level",
+				Current:     m.KYCLevel,
+				Target:      target,
+				Description: messages.format("counterfactual.kyc_level", target),
+			},
+			effort: 0.5 + 0.25*float64(i),
+			apply: func(c *merchant.Merchant) {
+				c.KYCVerified = true
+				c.KYCLevel = target
+			},
+		})
+	}
+	return opts
+}
+
+func accountAgeLever(m *merchant.Merchant, f BandedFactor, messages catalog) []leverOption {
+	var opts []leverOption
+	currentPoints := f.Score(float64(m.AccountAgeDays))
+
+	for i, band := range f.Bands {
+		if i == 0 || band.Points >= currentPoints {
+			continue
+		}
+		target := int(math.Ceil(*f.Bands[i-1].Below))
+		wait := target - m.AccountAgeDays
+		if wait <= 0 || wait > maxAccountAgeWaitDays {
+			continue
+		}
+		opts = append(opts, leverOption{
+			change: MetricChange{
+				Field:       "account_age_days",
+				Current:     m.AccountAgeDays,
+				Target:      target,
+				Description: messages.format("counterfactual.account_age_days", target, wait),
+			},
+			effort: float64(wait) / maxAccountAgeWaitDays,
+			apply:  func(c *merchant.Merchant) { c.AccountAgeDays = target },
+		})
+	}
+	return opts
+}
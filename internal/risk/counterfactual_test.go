@@ -0,0 +1,121 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+func TestPathToNextTier(t *testing.T) {
+	merchantID := uuid.New()
+	serviceFor := func(m *merchant.Merchant) *Service {
+		return NewService(&mockMerchantRepository{
+			getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+				return m, nil
+			},
+		}, &mockDecisionRepository{})
+	}
+
+	t.Run("ranked minimal options", func(t *testing.T) {
+		// 20 chargeback + 5 age + 5 category + 7 KYC = 37, MEDIUM_LOW.
+		m := &merchant.Merchant{
+			ID:                 merchantID,
+			Industry:           "RETAIL",
+			AccountAgeDays:     400,
+			ChargebackRate:     decimal.NewFromFloat(1.2),
+			VelocityMultiplier: decimal.NewFromFloat(1.0),
+			RefundRate:         decimal.NewFromFloat(1.0),
+			KYCVerified:        true,
+			KYCLevel:           "PARTIAL",
+		}
+
+		path, err := serviceFor(m).PathToNextTier(context.Background(), merchantID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if path.CurrentScore != 37 || path.CurrentRiskLevel != RiskLevelMediumLow {
+			t.Fatalf("expected current score 37 MEDIUM_LOW, got %d %s", path.CurrentScore, path.CurrentRiskLevel)
+		}
+		if len(path.Options) == 0 {
+			t.Fatal("expected at least one option")
+		}
+
+		first := path.Options[0]
+		if first.Rank != 1 || len(first.Changes) != 1 || first.Changes[0].Field != "chargeback_rate" {
+			t.Fatalf("expected single chargeback change ranked first, got %+v", first)
+		}
+		if first.Changes[0].Target != 0.49 {
+			t.Errorf("expected chargeback target 0.49, got %v", first.Changes[0].Target)
+		}
+		if first.ProjectedScore != 17 || first.ProjectedRiskLevel != RiskLevelLow || first.ProjectedHoldPeriod != HoldPeriodImmediate {
+			t.Errorf("unexpected projection %+v", first)
+		}
+
+		var foundPair bool
+		for _, opt := range path.Options {
+			if len(opt.Changes) == 2 && opt.Changes[0].Target == 0.99 && opt.Changes[1].Target == "ENHANCED" {
+				foundPair = true
+			}
+			for _, change := range opt.Changes {
+				if change.Field == "chargeback_rate" && change.Target == 0.49 && len(opt.Changes) > 1 {
+					t.Errorf("non-minimal option returned: %+v", opt)
+				}
+			}
+		}
+		if !foundPair {
+			t.Errorf("expected smaller chargeback cut combined with ENHANCED KYC, got %+v", path.Options)
+		}
+	})
+
+	t.Run("hard stop must be cleared", func(t *testing.T) {
+		// Low additive score but unverified with high volume is forced to HIGH.
+		m := lowRiskUnverifiedMerchant(75000)
+
+		path, err := serviceFor(m).PathToNextTier(context.Background(), m.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if path.CurrentRiskLevel != RiskLevelHigh {
+			t.Fatalf("expected HIGH from hard stop, got %s", path.CurrentRiskLevel)
+		}
+		if len(path.Options) == 0 || path.Options[0].Changes[0].Field != "kyc_level" {
+			t.Fatalf("expected KYC upgrade ranked first, got %+v", path.Options)
+		}
+	})
+
+	t.Run("already in best tier", func(t *testing.T) {
+		m := &merchant.Merchant{
+			ID:             merchantID,
+			Industry:       "UTILITIES",
+			AccountAgeDays: 900,
+			KYCVerified:    true,
+			KYCLevel:       "ENHANCED",
+		}
+
+		path, err := serviceFor(m).PathToNextTier(context.Background(), merchantID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !path.BestTier || len(path.Options) != 0 {
+			t.Errorf("expected best tier with no options, got %+v", path)
+		}
+	})
+
+	t.Run("merchant not found", func(t *testing.T) {
+		service := NewService(&mockMerchantRepository{
+			getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+				return nil, errors.New("merchant not found")
+			},
+		}, &mockDecisionRepository{})
+
+		if _, err := service.PathToNextTier(context.Background(), merchantID); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
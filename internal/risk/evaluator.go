@@ -1,95 +1,197 @@
 package risk
 
 import (
+	"math"
+
 	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
-	"github.com/yuno-payments/papaya-payout-engine/internal/platform/constants"
 )
 
 type Evaluator struct {
-	chargebackExcellent  float64
-	chargebackAcceptable float64
-	chargebackCritical   float64
-	velocityNormal       float64
-	velocityElevated     float64
-	velocityConcerning   float64
-	velocityHighRisk     float64
-	refundNormal         float64
-	refundElevated       float64
+	rules *Ruleset
 }
 
 func NewEvaluator() *Evaluator {
-	return &Evaluator{
-		chargebackExcellent:  constants.DefaultChargebackExcellent,
-		chargebackAcceptable: constants.DefaultChargebackAcceptable,
-		chargebackCritical:   constants.DefaultChargebackCritical,
-		velocityNormal:       constants.DefaultVelocityNormal,
-		velocityElevated:     constants.DefaultVelocityElevated,
-		velocityConcerning:   constants.DefaultVelocityConcerning,
-		velocityHighRisk:     constants.DefaultVelocityHighRisk,
-		refundNormal:         constants.DefaultRefundNormal,
-		refundElevated:       constants.DefaultRefundElevated,
-	}
+	return NewEvaluatorFromRuleset(DefaultRuleset())
+}
+
+// NewEvaluatorFromRuleset builds an evaluator that scores every factor using
+// the bands declared in rs. The ruleset is expected to be validated already.
+func NewEvaluatorFromRuleset(rs *Ruleset) *Evaluator {
+	return &Evaluator{rules: rs}
 }
 
-func NewEvaluatorWithThresholds(thresholds map[string]interface{}) *Evaluator {
-	e := NewEvaluator()
+func NewEvaluatorWithThresholds(thresholds ScoringThresholds) *Evaluator {
+	return NewEvaluator().WithThresholds(thresholds)
+}
 
-	if val, ok := thresholds["chargeback_excellent"].(float64); ok {
-		e.chargebackExcellent = val
+// ScoringThresholds replace band upper bounds for simulations and backtests.
+// Nil fields keep the ruleset's bound. Rates are percentages, account ages
+// days and velocity a multiple of the merchant's baseline.
+type ScoringThresholds struct {
+	ChargebackExcellent   *float64 `json:"chargeback_excellent,omitempty"`
+	ChargebackAcceptable  *float64 `json:"chargeback_acceptable,omitempty"`
+	ChargebackCritical    *float64 `json:"chargeback_critical,omitempty"`
+	AccountAgeVeryNew     *float64 `json:"account_age_very_new,omitempty"`
+	AccountAgeNew         *float64 `json:"account_age_new,omitempty"`
+	AccountAgeEarly       *float64 `json:"account_age_early,omitempty"`
+	AccountAgeEstablished *float64 `json:"account_age_established,omitempty"`
+	AccountAgeMature      *float64 `json:"account_age_mature,omitempty"`
+	VelocityNormal        *float64 `json:"velocity_normal,omitempty"`
+	VelocityElevated      *float64 `json:"velocity_elevated,omitempty"`
+	VelocityConcerning    *float64 `json:"velocity_concerning,omitempty"`
+	VelocityHighRisk      *float64 `json:"velocity_high_risk,omitempty"`
+	RefundNormal          *float64 `json:"refund_normal,omitempty"`
+	RefundElevated        *float64 `json:"refund_elevated,omitempty"`
+}
+
+// thresholdBands maps each threshold key to the band whose upper bound it
+// replaces.
+var thresholdBands = []struct{ key, factor, label string }{
+	{"chargeback_excellent", "chargeback", "excellent"},
+	{"chargeback_acceptable", "chargeback", "acceptable"},
+	{"chargeback_critical", "chargeback", "concerning"},
+	{"account_age_very_new", "account_age", "very_new"},
+	{"account_age_new", "account_age", "new"},
+	{"account_age_early", "account_age", "early"},
+	{"account_age_established", "account_age", "established"},
+	{"account_age_mature", "account_age", "mature"},
+	{"velocity_normal", "velocity", "normal"},
+	{"velocity_elevated", "velocity", "elevated"},
+	{"velocity_concerning", "velocity", "concerning"},
+	{"velocity_high_risk", "velocity", "high_risk"},
+	{"refund_normal", "refund", "normal"},
+	{"refund_elevated", "refund", "elevated"},
+}
+
+// field returns the field for a threshold key, or nil for an unknown key.
+func (t *ScoringThresholds) field(key string) **float64 {
+	switch key {
+	case "chargeback_excellent":
+		return &t.ChargebackExcellent
+	case "chargeback_acceptable":
+		return &t.ChargebackAcceptable
+	case "chargeback_critical":
+		return &t.ChargebackCritical
+	case "account_age_very_new":
+		return &t.AccountAgeVeryNew
+	case "account_age_new":
+		return &t.AccountAgeNew
+	case "account_age_early":
+		return &t.AccountAgeEarly
+	case "account_age_established":
+		return &t.AccountAgeEstablished
+	case "account_age_mature":
+		return &t.AccountAgeMature
+	case "velocity_normal":
+		return &t.VelocityNormal
+	case "velocity_elevated":
+		return &t.VelocityElevated
+	case "velocity_concerning":
+		return &t.VelocityConcerning
+	case "velocity_high_risk":
+		return &t.VelocityHighRisk
+	case "refund_normal":
+		return &t.RefundNormal
+	case "refund_elevated":
+		return &t.RefundElevated
+	default:
+		return nil
 	}
-	if val, ok := thresholds["chargeback_acceptable"].(float64); ok {
-		e.chargebackAcceptable = val
+}
+
+// Empty reports whether no threshold is set. A nil receiver is empty.
+func (t *ScoringThresholds) Empty() bool {
+	if t == nil {
+		return true
+	}
+	for _, band := range thresholdBands {
+		if *t.field(band.key) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// WithThresholds returns a copy of the evaluator whose band bounds are replaced
+// by the given thresholds. The receiver is left untouched.
+func (e *Evaluator) WithThresholds(thresholds ScoringThresholds) *Evaluator {
+	evaluator, _ := e.applyThresholds(thresholds)
+	return evaluator
+}
+
+// applyThresholds is WithThresholds that also returns the thresholds the
+// ruleset had a band for.
+func (e *Evaluator) applyThresholds(thresholds ScoringThresholds) (*Evaluator, ScoringThresholds) {
+	rs := e.rules.Clone()
+
+	var applied ScoringThresholds
+	for _, band := range thresholdBands {
+		val := *thresholds.field(band.key)
+		if val != nil && rs.bandedFactor(band.factor).setThreshold(band.label, *val) {
+			*applied.field(band.key) = val
+		}
 	}
-	if val, ok := thresholds["chargeback_critical"].(float64); ok {
-		e.chargebackCritical = val
+
+	if !applied.Empty() {
+		rs.Version += "+custom-thresholds"
 	}
-	if val, ok := thresholds["velocity_normal"].(float64); ok {
-		e.velocityNormal = val
+
+	return NewEvaluatorFromRuleset(rs), applied
+}
+
+// WithMode returns a copy of the evaluator that scores in the given mode. The
+// version records the mode when it differs from the ruleset's own.
+func (e *Evaluator) WithMode(mode string) (*Evaluator, error) {
+	if err := validateScoringMode(mode); err != nil {
+		return nil, err
 	}
-	if val, ok := thresholds["refund_normal"].(float64); ok {
-		e.refundNormal = val
+
+	current := e.rules.Mode
+	if current == "" {
+		current = ScoringModeBanded
 	}
-	if val, ok := thresholds["refund_elevated"].(float64); ok {
-		e.refundElevated = val
+	if mode == "" || mode == current {
+		return e, nil
 	}
 
-	return e
+	rs := e.rules.Clone()
+	rs.Mode = mode
+	rs.Version += "+" + mode
+	return NewEvaluatorFromRuleset(rs), nil
+}
+
+func (e *Evaluator) Ruleset() *Ruleset {
+	return e.rules
+}
+
+// Version identifies the scoring model recorded on every decision.
+func (e *Evaluator) Version() string {
+	return e.rules.Version
 }
 
 // CalculateChargebackScore evaluates the merchant's chargeback rate and assigns
 // a risk score from 0-30 points. Higher chargeback rates indicate potential fraud,
 // operational issues, or customer dissatisfaction.
 //
-// Scoring thresholds (configurable):
+// Default scoring thresholds (configurable through the ruleset):
 //   - < 0.5%: 0 points (excellent, industry best practice)
 //   - 0.5-1.0%: 10 points (acceptable range)
 //   - 1.0-1.5%: 20 points (concerning, approaching processor limits)
 //   - > 1.5%: 30 points (critical, exceeds most processor thresholds)
 //
 // Note: Most payment processors enforce 1.5% maximum chargeback rate.
+//
+// When the ruleset enables smoothing, the rate is first shrunk toward the
+// industry prior according to TransactionCount30d.
 func (e *Evaluator) CalculateChargebackScore(m *merchant.Merchant) int {
-	rate := m.ChargebackRate.InexactFloat64()
-
-	var baseScore int
-	switch {
-	case rate < e.chargebackExcellent:
-		baseScore = 0
-	case rate >= e.chargebackExcellent && rate < e.chargebackAcceptable:
-		baseScore = 10
-	case rate >= e.chargebackAcceptable && rate < e.chargebackCritical:
-		baseScore = 20
-	default:
-		baseScore = 30
-	}
-
-	return baseScore
+	return e.scoreBanded(e.rules.Chargeback, e.rules.ChargebackEstimate(m).AdjustedRate)
 }
 
 // CalculateAccountAgeScore evaluates merchant account maturity and assigns
 // a risk score from 0-25 points. Newer merchants have less established track records
 // and higher risk profiles.
 //
-// Scoring thresholds:
+// Default scoring thresholds (configurable through the ruleset):
 //   - < 30 days: 25 points (very new, high-risk window)
 //   - 30-90 days: 20 points (new, still establishing patterns)
 //   - 91-180 days: 15 points (early stage)
@@ -97,80 +199,30 @@ func (e *Evaluator) CalculateChargebackScore(m *merchant.Merchant) int {
 //   - 366-730 days: 5 points (mature)
 //   - > 730 days: 0 points (veteran, proven track record)
 func (e *Evaluator) CalculateAccountAgeScore(m *merchant.Merchant) int {
-	days := m.AccountAgeDays
-
-	switch {
-	case days < 30:
-		return 25
-	case days >= 30 && days < 91:
-		return 20
-	case days >= 91 && days < 181:
-		return 15
-	case days >= 181 && days < 366:
-		return 10
-	case days >= 366 && days < 731:
-		return 5
-	default:
-		return 0
-	}
+	return e.scoreBanded(e.rules.AccountAge, float64(m.AccountAgeDays))
 }
 
 func (e *Evaluator) CalculateVelocityScore(m *merchant.Merchant) int {
-	multiplier := m.VelocityMultiplier.InexactFloat64()
-
-	switch {
-	case multiplier < e.velocityNormal:
-		return 0
-	case multiplier >= e.velocityNormal && multiplier < e.velocityElevated:
-		return 5
-	case multiplier >= e.velocityElevated && multiplier < e.velocityConcerning:
-		return 10
-	case multiplier >= e.velocityConcerning && multiplier < e.velocityHighRisk:
-		return 15
-	default:
-		return 20
-	}
+	return e.scoreBanded(e.rules.Velocity, m.VelocityMultiplier.InexactFloat64())
 }
 
 func (e *Evaluator) CalculateCategoryScore(m *merchant.Merchant) int {
-	switch m.Industry {
-	case "DIGITAL_GOODS", "TRAVEL", "ELECTRONICS":
-		return 15
-	case "FASHION", "SERVICES":
-		return 10
-	case "FOOD_DELIVERY", "RETAIL":
-		return 5
-	case "UTILITIES", "HEALTHCARE":
-		return 0
-	default:
-		return 10
-	}
+	return e.rules.Category.Score(m.Industry)
 }
 
 func (e *Evaluator) CalculateKYCScore(m *merchant.Merchant) int {
 	if !m.KYCVerified {
-		return 10
+		return e.rules.KYC.Default
 	}
 
-	switch m.KYCLevel {
-	case "NONE":
-		return 10
-	case "PARTIAL":
-		return 7
-	case "FULL":
-		return 3
-	case "ENHANCED":
-		return 0
-	default:
-		return 10
-	}
+	return e.rules.KYC.Score(m.KYCLevel)
 }
 
 // CalculateRefundScore evaluates the merchant's refund rate and assigns
 // a risk score from 0-5 points. Elevated refund rates may indicate fraud patterns,
 // poor product quality, or misleading product descriptions.
 //
-// Scoring thresholds (configurable):
+// Default scoring thresholds (configurable through the ruleset):
 //   - < 3.0%: 0 points (normal operations)
 //   - 3.0-6.0%: 3 points (elevated but acceptable)
 //   - > 6.0%: 5 points (high risk, potential fraud signal)
@@ -178,16 +230,16 @@ func (e *Evaluator) CalculateKYCScore(m *merchant.Merchant) int {
 // High refund rates combined with low chargebacks may indicate friendly fraud
 // or merchant refunding to avoid chargebacks.
 func (e *Evaluator) CalculateRefundScore(m *merchant.Merchant) int {
-	rate := m.RefundRate.InexactFloat64()
+	return e.scoreBanded(e.rules.Refund, e.rules.RefundEstimate(m).AdjustedRate)
+}
 
-	switch {
-	case rate < e.refundNormal:
-		return 0
-	case rate >= e.refundNormal && rate < e.refundElevated:
-		return 3
-	default:
-		return 5
+// scoreBanded applies the ruleset's mode to a numeric factor. Continuous
+// scores are rounded to whole points so factor maxima are unchanged.
+func (e *Evaluator) scoreBanded(f BandedFactor, value float64) int {
+	if e.rules.Continuous() {
+		return int(math.Round(f.Interpolate(value)))
 	}
+	return f.Score(value)
 }
 
 func (e *Evaluator) CalculateTotalScore(m *merchant.Merchant) (int, FactorScore) {
@@ -1,213 +1,362 @@
 package risk
 
 import (
-	"fmt"
-
 	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
 )
 
-type Explainer struct{}
+// Explainer renders human-readable reasoning for a decision. It reads the same
+// ruleset bands the evaluator scored with, so custom thresholds in a
+// simulation are reflected in the text, and renders messages from the catalog
+// for its language. Every message is also recorded on the reasoning so it can
+// be rendered again in another language (see Reasoning.Localize).
+type Explainer struct {
+	rules    *Ruleset
+	messages catalog
+}
 
 func NewExplainer() *Explainer {
-	return &Explainer{}
+	return NewExplainerFromRuleset(DefaultRuleset(), DefaultLanguage)
+}
+
+func NewExplainerFromRuleset(rs *Ruleset, lang string) *Explainer {
+	return &Explainer{rules: rs, messages: catalogFor(lang)}
+}
+
+// explain appends msg to a factor's contribution.
+func (e *Explainer) explain(factor *FactorExplanation, msg Message) {
+	factor.Messages = append(factor.Messages, msg)
+	factor.Contribution += msg.render(e.messages)
+}
+
+// explainPolicy appends msg to the reasoning's policy explanation.
+func (e *Explainer) explainPolicy(reasoning *Reasoning, msg Message) {
+	reasoning.PolicyMessages = append(reasoning.PolicyMessages, msg)
+	reasoning.PolicyExplanation += msg.render(e.messages)
+}
+
+// bandImpacts records the impact of each band in the built-in ruleset. Bands
+// with other labels fall back to an impact derived from their points.
+var bandImpacts = map[string]map[string]string{
+	"chargeback": {
+		"excellent":  "POSITIVE",
+		"acceptable": "NEUTRAL",
+		"concerning": "NEGATIVE",
+		"critical":   "CRITICAL",
+	},
+	"account_age": {
+		"very_new":    "CRITICAL",
+		"new":         "NEGATIVE",
+		"early":       "NEUTRAL",
+		"established": "NEUTRAL",
+		"mature":      "POSITIVE",
+		"veteran":     "POSITIVE",
+	},
+	"velocity": {
+		"normal":     "POSITIVE",
+		"elevated":   "NEUTRAL",
+		"concerning": "NEGATIVE",
+		"high_risk":  "NEGATIVE",
+		"critical":   "CRITICAL",
+	},
+	"refund": {
+		"normal":   "POSITIVE",
+		"elevated": "NEUTRAL",
+		"high":     "NEGATIVE",
+	},
 }
 
-func (e *Explainer) GenerateReasoning(m *merchant.Merchant, factors FactorScore, tier PolicyTier) Reasoning {
+func (e *Explainer) GenerateReasoning(m *merchant.Merchant, totalScore int, factors FactorScore, tier PolicyTier) Reasoning {
+	chargeback := e.rules.ChargebackEstimate(m)
+	refund := e.rules.RefundEstimate(m)
+
 	primaryFactors := []FactorExplanation{
-		e.ExplainChargebackScore(factors.Chargeback, m.ChargebackRate.InexactFloat64()),
+		e.explainSmoothedRate(e.ExplainChargebackScore(factors.Chargeback, chargeback.AdjustedRate), "smoothing.chargeback", chargeback),
 		e.ExplainAccountAgeScore(factors.AccountAge, m.AccountAgeDays),
 		e.ExplainVelocityScore(factors.Velocity, m.VelocityMultiplier.InexactFloat64()),
 		e.ExplainCategoryScore(factors.Category, m.Industry),
 		e.ExplainKYCScore(factors.KYC, m.KYCVerified, m.KYCLevel),
-		e.ExplainRefundScore(factors.Refund, m.RefundRate.InexactFloat64()),
+		e.explainSmoothedRate(e.ExplainRefundScore(factors.Refund, refund.AdjustedRate), "smoothing.refund", refund),
 	}
 
-	totalScore := factors.Chargeback + factors.AccountAge + factors.Velocity +
-		factors.Category + factors.KYC + factors.Refund
+	reasoning := Reasoning{PrimaryFactors: primaryFactors}
+	e.explainPolicy(&reasoning, newMessage("policy.explanation",
+		totalScore, tier.RiskLevel, tier.HoldPeriod, tier.ReservePercentage))
+	return reasoning
+}
 
-	policyExplanation := fmt.Sprintf(
-		"Score of %d places merchant in %s tier requiring %s hold and %d%% reserve",
-		totalScore, tier.RiskLevel, tier.HoldPeriod, tier.ReservePercentage,
-	)
+// ApplyPolicyOverride records a market override on the reasoning and extends
+// the policy explanation so readers know the standard tiers were adjusted.
+func (e *Explainer) ApplyPolicyOverride(reasoning *Reasoning, override *AppliedPolicyOverride) {
+	if override == nil {
+		return
+	}
 
-	return Reasoning{
-		PrimaryFactors:    primaryFactors,
-		PolicyExplanation: policyExplanation,
+	scope := override.Country
+	switch {
+	case override.Country != "" && override.Industry != "":
+		scope = override.Country + "/" + override.Industry
+	case override.Industry != "":
+		scope = override.Industry
 	}
+
+	reasoning.PolicyOverride = override
+	e.explainPolicy(reasoning, newMessage("policy.override", scope, override.Name, override.Mode))
 }
 
-func (e *Explainer) ExplainChargebackScore(score int, rate float64) FactorExplanation {
-	var contribution string
-	var impact string
+// ApplyHardStops adds a HARD_STOP factor for every triggered rule and notes in
+// the policy explanation when the rules moved the merchant off its scored tier.
+func (e *Explainer) ApplyHardStops(reasoning *Reasoning, triggered []HardStopRule, scored, final PolicyTier) {
+	for _, rule := range triggered {
+		// Validation requires a rule to set a minimum level, a hold or both.
+		var effect Message
+		switch {
+		case rule.MinRiskLevel != "" && rule.HoldPeriod != "":
+			effect = newMessage("hard_stop.and",
+				newMessage("hard_stop.min_level", rule.MinRiskLevel), newMessage("hard_stop.hold", rule.HoldPeriod))
+		case rule.MinRiskLevel != "":
+			effect = newMessage("hard_stop.min_level", rule.MinRiskLevel)
+		default:
+			effect = newMessage("hard_stop.hold", rule.HoldPeriod)
+		}
 
-	switch {
-	case rate < 0.5:
-		contribution = fmt.Sprintf("%.2f%% rate - Excellent", rate)
-		impact = "POSITIVE"
-	case rate >= 0.5 && rate < 1.0:
-		contribution = fmt.Sprintf("%.2f%% rate - Acceptable range", rate)
-		impact = "NEUTRAL"
-	case rate >= 1.0 && rate < 1.5:
-		contribution = fmt.Sprintf("%.2f%% rate - Concerning", rate)
-		impact = "NEGATIVE"
-	default:
-		contribution = fmt.Sprintf("%.2f%% rate - Critical", rate)
-		impact = "CRITICAL"
+		factor := FactorExplanation{
+			Factor:     "Hard Stop: " + rule.Code,
+			Score:      0,
+			Impact:     "HARD_STOP",
+			ReasonCode: reasonCodeHardStopPrefix + rule.Code,
+		}
+		e.explain(&factor, newMessage("hard_stop.contribution", rule.Description, effect))
+		reasoning.PrimaryFactors = append(reasoning.PrimaryFactors, factor)
 	}
 
-	return FactorExplanation{
-		Factor:       "Chargeback Rate",
-		Score:        score,
-		Contribution: contribution,
-		Impact:       impact,
+	if final != scored {
+		e.explainPolicy(reasoning, newMessage("policy.hard_stop",
+			final.RiskLevel, final.HoldPeriod, final.ReservePercentage))
 	}
 }
 
-func (e *Explainer) ExplainAccountAgeScore(score int, days int) FactorExplanation {
-	var contribution string
-	var impact string
+// ApplyHysteresis records a hysteresis hold on the reasoning and explains
+// what the upgrade still requires.
+func (e *Explainer) ApplyHysteresis(reasoning *Reasoning, hold *HysteresisHold) {
+	if hold == nil {
+		return
+	}
 
-	switch {
-	case days < 30:
-		contribution = fmt.Sprintf("Account %d days old - Very new", days)
-		impact = "CRITICAL"
-	case days >= 30 && days < 91:
-		contribution = fmt.Sprintf("Account %d days old - New", days)
-		impact = "NEGATIVE"
-	case days >= 91 && days < 181:
-		contribution = fmt.Sprintf("Account %d days old - Early stage", days)
-		impact = "NEUTRAL"
-	case days >= 181 && days < 366:
-		contribution = fmt.Sprintf("Account %d days old - Established", days)
-		impact = "NEUTRAL"
-	case days >= 366 && days < 731:
-		contribution = fmt.Sprintf("Account %d days old - Mature", days)
-		impact = "POSITIVE"
-	default:
-		contribution = fmt.Sprintf("Account %d days old - Veteran", days)
-		impact = "POSITIVE"
+	reasoning.Hysteresis = hold
+	e.explainPolicy(reasoning, newMessage("hysteresis.held", hold.HeldRiskLevel, hold.ScoredRiskLevel))
+	if hold.RequiredScore != nil {
+		e.explainPolicy(reasoning, newMessage("hysteresis.margin", *hold.RequiredScore))
+	}
+	if hold.RequiredEvaluations > 0 {
+		e.explainPolicy(reasoning, newMessage("hysteresis.streak",
+			hold.RequiredEvaluations, hold.QualifyingEvaluations))
+	}
+}
+
+// ApplyManualOverride records an analyst override on the reasoning and notes
+// the policy it replaced.
+func (e *Explainer) ApplyManualOverride(reasoning *Reasoning, override *AppliedManualOverride) {
+	if override == nil {
+		return
 	}
 
-	return FactorExplanation{
-		Factor:       "Account Age",
-		Score:        score,
-		Contribution: contribution,
-		Impact:       impact,
+	reasoning.ManualOverride = override
+	e.explainPolicy(reasoning, newMessage("policy.manual_override",
+		override.CreatedBy, override.HoldPeriod, override.ReservePercentage,
+		override.ExpiresAt.Format("2006-01-02 15:04 MST"),
+		override.ComputedHoldPeriod, override.ComputedReservePercentage))
+}
+
+// ApplyPendingApproval records that the decision awaits approval and which
+// policy stays in force meanwhile.
+func (e *Explainer) ApplyPendingApproval(reasoning *Reasoning, level RiskLevel, interim *PendingApproval) {
+	if interim == nil {
+		return
 	}
+
+	reasoning.PendingApproval = interim
+	key := "policy.pending_approval"
+	if interim.SafeDefault {
+		key = "policy.pending_approval_default"
+	}
+	e.explainPolicy(reasoning, newMessage(key,
+		level, interim.EffectiveHoldPeriod, interim.EffectiveReservePercentage))
 }
 
-func (e *Explainer) ExplainVelocityScore(score int, multiplier float64) FactorExplanation {
-	var contribution string
-	var impact string
+// ApplyTrends adds a factor for every trend signal that matched the
+// merchant's metric history.
+func (e *Explainer) ApplyTrends(reasoning *Reasoning, trends []TrendMatch) {
+	for _, trend := range trends {
+		var msg Message
+		switch trend.Kind {
+		case TrendDrop:
+			msg = newMessage("trend.drop",
+				trend.Metric, (trend.From-trend.To)/trend.From*100, trend.Periods, trend.From, trend.To)
+		default:
+			msg = newMessage("trend.rising",
+				trend.Metric, trend.Periods, trend.From, trend.To)
+		}
 
+		factor := FactorExplanation{
+			Factor:     "Trend: " + trend.Code,
+			Score:      trend.Points,
+			Impact:     "NEGATIVE",
+			ReasonCode: reasonCodeTrendPrefix + trend.Code,
+		}
+		e.explain(&factor, msg)
+		reasoning.PrimaryFactors = append(reasoning.PrimaryFactors, factor)
+	}
+}
+
+// explainSmoothedRate notes the raw rate, transaction count and confidence
+// behind a smoothed rate. Rates the ruleset does not smooth are left as is.
+func (e *Explainer) explainSmoothedRate(explanation FactorExplanation, key string, estimate RateEstimate) FactorExplanation {
+	if e.rules.Smoothing == nil {
+		return explanation
+	}
+
+	e.explain(&explanation, newMessage(key,
+		estimate.RawRate, estimate.TransactionCount, estimate.Confidence*100))
+	explanation.Adjustment = &estimate
+	return explanation
+}
+
+// explainBand finds the band value falls in and returns the catalog message
+// for it, along with the band's impact and reason code.
+func (e *Explainer) explainBand(factor string, f BandedFactor, value interface{}, numeric float64) (Message, string, string) {
+	band, ok := f.Band(numeric)
+	if !ok {
+		return newMessage(factor+".band", value, "-"), "NEUTRAL", ""
+	}
+
+	impact, known := bandImpacts[factor][band.Label]
+	if !known {
+		impact = impactFromPoints(band.Points, f.MaxPoints)
+	}
+	code := bandReasonCode(factor, band.Label)
+
+	key := factor + "." + band.Label
+	if !e.messages.has(key) {
+		return newMessage(factor+".band", value, band.Label), impact, code
+	}
+	return newMessage(key, value), impact, code
+}
+
+// impactFromPoints classifies a custom band by how much of the factor's
+// maximum it awards.
+func impactFromPoints(points, maxPoints int) string {
 	switch {
-	case multiplier < 1.5:
-		contribution = fmt.Sprintf("%.1fx velocity - Normal", multiplier)
-		impact = "POSITIVE"
-	case multiplier >= 1.5 && multiplier < 2.5:
-		contribution = fmt.Sprintf("%.1fx velocity - Elevated", multiplier)
-		impact = "NEUTRAL"
-	case multiplier >= 2.5 && multiplier < 4.0:
-		contribution = fmt.Sprintf("%.1fx velocity - Concerning", multiplier)
-		impact = "NEGATIVE"
-	case multiplier >= 4.0 && multiplier < 6.0:
-		contribution = fmt.Sprintf("%.1fx velocity - High risk", multiplier)
-		impact = "NEGATIVE"
+	case points == 0:
+		return "POSITIVE"
+	case points >= maxPoints:
+		return "CRITICAL"
+	case points*2 > maxPoints:
+		return "NEGATIVE"
 	default:
-		contribution = fmt.Sprintf("%.1fx velocity - Critical", multiplier)
-		impact = "CRITICAL"
+		return "NEUTRAL"
 	}
+}
+
+func (e *Explainer) ExplainChargebackScore(score int, rate float64) FactorExplanation {
+	msg, impact, code := e.explainBand("chargeback", e.rules.Chargeback, rate, rate)
 
-	return FactorExplanation{
-		Factor:       "Transaction Velocity",
-		Score:        score,
-		Contribution: contribution,
-		Impact:       impact,
+	factor := FactorExplanation{
+		Factor:     "Chargeback Rate",
+		Score:      score,
+		Impact:     impact,
+		ReasonCode: code,
 	}
+	e.explain(&factor, msg)
+	return factor
 }
 
+func (e *Explainer) ExplainAccountAgeScore(score int, days int) FactorExplanation {
+	msg, impact, code := e.explainBand("account_age", e.rules.AccountAge, days, float64(days))
+
+	factor := FactorExplanation{
+		Factor:     "Account Age",
+		Score:      score,
+		Impact:     impact,
+		ReasonCode: code,
+	}
+	e.explain(&factor, msg)
+	return factor
+}
+
+func (e *Explainer) ExplainVelocityScore(score int, multiplier float64) FactorExplanation {
+	msg, impact, code := e.explainBand("velocity", e.rules.Velocity, multiplier, multiplier)
+
+	factor := FactorExplanation{
+		Factor:     "Transaction Velocity",
+		Score:      score,
+		Impact:     impact,
+		ReasonCode: code,
+	}
+	e.explain(&factor, msg)
+	return factor
+}
+
+// ExplainCategoryScore grades the industry by the points the ruleset assigns
+// it relative to the category maximum.
 func (e *Explainer) ExplainCategoryScore(score int, industry string) FactorExplanation {
-	var contribution string
-	var impact string
-
-	switch score {
-	case 15:
-		contribution = fmt.Sprintf("%s - High risk category", industry)
-		impact = "NEGATIVE"
-	case 10:
-		contribution = fmt.Sprintf("%s - Medium risk category", industry)
-		impact = "NEUTRAL"
-	case 5:
-		contribution = fmt.Sprintf("%s - Low risk category", industry)
-		impact = "POSITIVE"
-	case 0:
-		contribution = fmt.Sprintf("%s - Minimal risk category", industry)
-		impact = "POSITIVE"
+	points := e.rules.Category.Score(industry)
+	maxPoints := e.rules.Category.MaxPoints
+
+	var key, impact string
+	switch {
+	case points >= maxPoints:
+		key, impact = "category.high", "NEGATIVE"
+	case points*3 >= maxPoints*2:
+		key, impact = "category.medium", "NEUTRAL"
+	case points > 0:
+		key, impact = "category.low", "POSITIVE"
 	default:
-		contribution = fmt.Sprintf("%s - Unknown category", industry)
-		impact = "NEUTRAL"
+		key, impact = "category.minimal", "POSITIVE"
 	}
 
-	return FactorExplanation{
-		Factor:       "Business Category",
-		Score:        score,
-		Contribution: contribution,
-		Impact:       impact,
+	factor := FactorExplanation{
+		Factor:     "Business Category",
+		Score:      score,
+		Impact:     impact,
+		ReasonCode: categoryReasonCodes[key],
 	}
+	e.explain(&factor, newMessage(key, industry))
+	return factor
 }
 
 func (e *Explainer) ExplainKYCScore(score int, verified bool, level string) FactorExplanation {
-	var contribution string
-	var impact string
+	key, impact := "kyc.none", "CRITICAL"
 
-	if !verified {
-		contribution = "No KYC verification"
-		impact = "CRITICAL"
-	} else {
+	if verified {
 		switch level {
 		case "ENHANCED":
-			contribution = "Enhanced KYC - Full business documentation"
-			impact = "POSITIVE"
+			key, impact = "kyc.enhanced", "POSITIVE"
 		case "FULL":
-			contribution = "Full KYC - ID and address verified"
-			impact = "NEUTRAL"
+			key, impact = "kyc.full", "NEUTRAL"
 		case "PARTIAL":
-			contribution = "Partial KYC - ID only"
-			impact = "NEGATIVE"
-		default:
-			contribution = "No KYC verification"
-			impact = "CRITICAL"
+			key, impact = "kyc.partial", "NEGATIVE"
 		}
 	}
 
-	return FactorExplanation{
-		Factor:       "KYC Verification",
-		Score:        score,
-		Contribution: contribution,
-		Impact:       impact,
+	factor := FactorExplanation{
+		Factor:     "KYC Verification",
+		Score:      score,
+		Impact:     impact,
+		ReasonCode: kycReasonCodes[key],
 	}
+	e.explain(&factor, newMessage(key))
+	return factor
 }
 
 func (e *Explainer) ExplainRefundScore(score int, rate float64) FactorExplanation {
-	var contribution string
-	var impact string
-
-	switch {
-	case rate < 3.0:
-		contribution = fmt.Sprintf("%.1f%% refund rate - Normal", rate)
-		impact = "POSITIVE"
-	case rate >= 3.0 && rate < 6.0:
-		contribution = fmt.Sprintf("%.1f%% refund rate - Elevated", rate)
-		impact = "NEUTRAL"
-	default:
-		contribution = fmt.Sprintf("%.1f%% refund rate - High (fraud signal)", rate)
-		impact = "NEGATIVE"
-	}
+	msg, impact, code := e.explainBand("refund", e.rules.Refund, rate, rate)
 
-	return FactorExplanation{
-		Factor:       "Refund Rate",
-		Score:        score,
-		Contribution: contribution,
-		Impact:       impact,
+	factor := FactorExplanation{
+		Factor:     "Refund Rate",
+		Score:      score,
+		Impact:     impact,
+		ReasonCode: code,
 	}
+	e.explain(&factor, msg)
+	return factor
 }
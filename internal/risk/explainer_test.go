@@ -0,0 +1,228 @@
+package risk
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+func TestExplainerUsesRulesetBands(t *testing.T) {
+	t.Run("default bands", func(t *testing.T) {
+		e := NewExplainer()
+
+		got := e.ExplainChargebackScore(10, 0.7)
+		if got.Contribution != "0.70% rate - Acceptable range" || got.Impact != "NEUTRAL" {
+			t.Errorf("unexpected explanation %+v", got)
+		}
+	})
+
+	t.Run("custom thresholds move the band", func(t *testing.T) {
+		rules := NewEvaluator().WithThresholds(ScoringThresholds{
+			ChargebackExcellent: ptr(0.3),
+			VelocityNormal:      ptr(1.2),
+		}).Ruleset()
+		e := NewExplainerFromRuleset(rules, DefaultLanguage)
+
+		chargeback := e.ExplainChargebackScore(10, 0.4)
+		if chargeback.Contribution != "0.40% rate - Acceptable range" {
+			t.Errorf("expected acceptable band under custom thresholds, got %q", chargeback.Contribution)
+		}
+		velocity := e.ExplainVelocityScore(5, 1.3)
+		if velocity.Contribution != "1.3x velocity - Elevated" || velocity.Impact != "NEUTRAL" {
+			t.Errorf("expected elevated band under custom thresholds, got %+v", velocity)
+		}
+	})
+
+	t.Run("unknown band labels fall back to generic text", func(t *testing.T) {
+		rules := DefaultRuleset()
+		rules.Refund = BandedFactor{
+			MaxPoints: 5,
+			Bands: []Band{
+				{Label: "low", Below: below(2), Points: 0},
+				{Label: "watch", Points: 4},
+			},
+		}
+		e := NewExplainerFromRuleset(rules, DefaultLanguage)
+
+		got := e.ExplainRefundScore(4, 2.5)
+		if got.Contribution != "2.5% refund rate - watch" || got.Impact != "NEGATIVE" {
+			t.Errorf("unexpected explanation %+v", got)
+		}
+	})
+}
+
+func TestExplainerLocalization(t *testing.T) {
+	tier := PolicyTier{RiskLevel: RiskLevelMedium, HoldPeriod: HoldPeriod14Days, ReservePercentage: 10}
+	m := &merchant.Merchant{
+		ChargebackRate:     decimal.NewFromFloat(1.2),
+		AccountAgeDays:     40,
+		VelocityMultiplier: decimal.NewFromFloat(1.0),
+		RefundRate:         decimal.NewFromFloat(1.0),
+		Industry:           "RETAIL",
+	}
+
+	tests := []struct {
+		lang       string
+		chargeback string
+		policy     string
+	}{
+		{"en", "1.20% rate - Concerning", "Score of 45 places merchant in MEDIUM tier"},
+		{"pt-BR", "Taxa de 1.20% - Preocupante", "Pontuação de 45 coloca o lojista no nível MEDIUM"},
+		{"es", "Tasa de 1.20% - Preocupante", "Una puntuación de 45 ubica al comercio en el nivel MEDIUM"},
+		{"de", "1.20% rate - Concerning", "Score of 45 places merchant in MEDIUM tier"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			e := NewExplainerFromRuleset(DefaultRuleset(), tt.lang)
+			reasoning := e.GenerateReasoning(m, 45, FactorScore{Chargeback: 20}, tier)
+
+			if got := reasoning.PrimaryFactors[0].Contribution; got != tt.chargeback {
+				t.Errorf("chargeback contribution = %q, want %q", got, tt.chargeback)
+			}
+			if !strings.HasPrefix(reasoning.PolicyExplanation, tt.policy) {
+				t.Errorf("policy explanation = %q, want prefix %q", reasoning.PolicyExplanation, tt.policy)
+			}
+		})
+	}
+}
+
+func TestCatalogsDefineSameKeys(t *testing.T) {
+	keys := func(c catalog) []string {
+		var out []string
+		for k := range c {
+			out = append(out, k)
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	want := keys(catalogs[DefaultLanguage])
+	for _, lang := range SupportedLanguages {
+		if got := keys(catalogs[lang]); !reflect.DeepEqual(got, want) {
+			t.Errorf("catalog %s keys differ from %s", lang, DefaultLanguage)
+		}
+	}
+}
+
+func TestNegotiateLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"pt-BR", "pt-BR"},
+		{"pt-PT", "pt-BR"},
+		{"es-MX,es;q=0.9", "es"},
+		{"fr-FR, es;q=0.4, pt-br;q=0.8", "pt-BR"},
+		{"de, *;q=0.1", "en"},
+		{"es;q=0, en-US", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := NegotiateLanguage(tt.header); got != tt.want {
+				t.Errorf("NegotiateLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulateMerchantExplanationMatchesThresholds(t *testing.T) {
+	merchantID := uuid.New()
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return &merchant.Merchant{
+				ID:             merchantID,
+				Industry:       "RETAIL",
+				AccountAgeDays: 800,
+				ChargebackRate: decimal.NewFromFloat(0.4),
+				KYCVerified:    true,
+				KYCLevel:       "FULL",
+			}, nil
+		},
+	}
+	service := NewService(merchantStore, &mockDecisionRepository{})
+
+	ctx := WithLanguage(context.Background(), "es")
+	decision, err := service.SimulateMerchant(ctx, merchantID, SimulationOverrides{
+		ScoringThresholds: &ScoringThresholds{ChargebackExcellent: ptr(0.3)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	chargeback := decision.Reasoning.PrimaryFactors[0]
+	if chargeback.Score != 10 {
+		t.Errorf("expected chargeback score 10 under custom thresholds, got %d", chargeback.Score)
+	}
+	if chargeback.Contribution != "0.40% rate - Acceptable range" {
+		t.Errorf("expected explanation to match custom thresholds in English, got %q", chargeback.Contribution)
+	}
+
+	decision.Reasoning.Localize("es")
+	if got := decision.Reasoning.PrimaryFactors[0].Contribution; got != "Tasa de 0.40% - Rango aceptable" {
+		t.Errorf("expected explanation to match custom thresholds in Spanish, got %q", got)
+	}
+}
+
+func TestReasoningLocalize(t *testing.T) {
+	rules := DefaultRuleset()
+	tier := PolicyTier{RiskLevel: RiskLevelHigh, HoldPeriod: HoldPeriod45Days, ReservePercentage: 20}
+	m := &merchant.Merchant{
+		Industry:             "TRAVEL",
+		AccountAgeDays:       10,
+		TransactionVolume30d: decimal.NewFromFloat(80000),
+		ChargebackRate:       decimal.NewFromFloat(3.5),
+		VelocityMultiplier:   decimal.NewFromFloat(4.0),
+		RefundRate:           decimal.NewFromFloat(7.0),
+	}
+	explain := func(lang string) Reasoning {
+		e := NewExplainerFromRuleset(rules, lang)
+		reasoning := e.GenerateReasoning(m, 100, FactorScore{Chargeback: 40}, tier)
+		e.ApplyHardStops(&reasoning, rules.HardStops, PolicyTier{}, tier)
+		return reasoning
+	}
+
+	stored, err := explain(DefaultLanguage).Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, lang := range []string{"es", "pt-BR"} {
+		t.Run(lang, func(t *testing.T) {
+			var reasoning Reasoning
+			if err := reasoning.Scan(stored); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			reasoning.Localize(lang)
+
+			want := explain(lang)
+			for i, factor := range reasoning.PrimaryFactors {
+				if factor.Contribution != want.PrimaryFactors[i].Contribution {
+					t.Errorf("%s contribution = %q, want %q", factor.ReasonCode, factor.Contribution, want.PrimaryFactors[i].Contribution)
+				}
+			}
+			if reasoning.PolicyExplanation != want.PolicyExplanation {
+				t.Errorf("policy explanation = %q, want %q", reasoning.PolicyExplanation, want.PolicyExplanation)
+			}
+		})
+	}
+
+	t.Run("without messages", func(t *testing.T) {
+		reasoning := Reasoning{
+			PrimaryFactors:    []FactorExplanation{{Factor: "chargeback_rate", Contribution: "stored text"}},
+			PolicyExplanation: "stored policy",
+		}
+		reasoning.Localize("es")
+		if reasoning.PrimaryFactors[0].Contribution != "stored text" || reasoning.PolicyExplanation != "stored policy" {
+			t.Errorf("expected reasoning without messages to keep its text, got %+v", reasoning)
+		}
+	})
+}
@@ -0,0 +1,250 @@
+package risk
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+// HardStopRule forces a minimum tier or hold period when all of its
+// conditions hold, regardless of the additive score. Hard stops only ever
+// tighten policy; they never move a merchant to a better tier.
+type HardStopRule struct {
+	Code         string          `json:"code"`
+	Description  string          `json:"description"`
+	Conditions   []RuleCondition `json:"conditions"`
+	MinRiskLevel RiskLevel       `json:"min_risk_level,omitempty"`
+	HoldPeriod   HoldPeriod      `json:"hold_period,omitempty"`
+}
+
+// RuleCondition compares one merchant field against a constant. Numeric
+// fields support eq, ne, gt, gte, lt and lte; boolean and string fields
+// support eq and ne.
+type RuleCondition struct {
+	Field string      `json:"field"`
+	Op    string      `json:"op"`
+	Value interface{} `json:"value"`
+}
+
+type conditionFieldKind int
+
+const (
+	numericField conditionFieldKind = iota
+	boolField
+	stringField
+)
+
+var conditionFields = map[string]conditionFieldKind{
+	"chargeback_rate":        numericField,
+	"chargeback_count_30d":   numericField,
+	"refund_rate":            numericField,
+	"velocity_multiplier":    numericField,
+	"account_age_days":       numericField,
+	"transaction_volume_30d": numericField,
+	"transaction_count_30d":  numericField,
+	"kyc_verified":           boolField,
+	"kyc_level":              stringField,
+	"industry":               stringField,
+	"country":                stringField,
+}
+
+var numericOps = map[string]func(a, b float64) bool{
+	"eq":  func(a, b float64) bool { return a == b },
+	"ne":  func(a, b float64) bool { return a != b },
+	"gt":  func(a, b float64) bool { return a > b },
+	"gte": func(a, b float64) bool { return a >= b },
+	"lt":  func(a, b float64) bool { return a < b },
+	"lte": func(a, b float64) bool { return a <= b },
+}
+
+var riskLevelRank = map[RiskLevel]int{
+	RiskLevelLow:       0,
+	RiskLevelMediumLow: 1,
+	RiskLevelMedium:    2,
+	RiskLevelHigh:      3,
+	RiskLevelCritical:  4,
+}
+
+// AtLeast reports whether l is as severe as other or more.
+func (l RiskLevel) AtLeast(other RiskLevel) bool {
+	return riskLevelRank[l] >= riskLevelRank[other]
+}
+
+// Valid reports whether l is one of the known risk levels.
+func (l RiskLevel) Valid() bool {
+	return validRiskLevels[l]
+}
+
+func (c RuleCondition) validate() error {
+	kind, ok := conditionFields[c.Field]
+	if !ok {
+		return fmt.Errorf("unknown field %q", c.Field)
+	}
+
+	switch kind {
+	case numericField:
+		if _, ok := numericOps[c.Op]; !ok {
+			return fmt.Errorf("field %q: unknown op %q", c.Field, c.Op)
+		}
+		if _, ok := c.Value.(float64); !ok {
+			return fmt.Errorf("field %q: value must be a number", c.Field)
+		}
+	case boolField:
+		if c.Op != "eq" && c.Op != "ne" {
+			return fmt.Errorf("field %q: op must be eq or ne", c.Field)
+		}
+		if _, ok := c.Value.(bool); !ok {
+			return fmt.Errorf("field %q: value must be a boolean", c.Field)
+		}
+	case stringField:
+		if c.Op != "eq" && c.Op != "ne" {
+			return fmt.Errorf("field %q: op must be eq or ne", c.Field)
+		}
+		if _, ok := c.Value.(string); !ok {
+			return fmt.Errorf("field %q: value must be a string", c.Field)
+		}
+	}
+
+	return nil
+}
+
+func (c RuleCondition) matches(m *merchant.Merchant) bool {
+	switch c.Field {
+	case "kyc_verified":
+		return (m.KYCVerified == c.Value.(bool)) == (c.Op == "eq")
+	case "kyc_level":
+		return strings.EqualFold(m.KYCLevel, c.Value.(string)) == (c.Op == "eq")
+	case "industry":
+		return strings.EqualFold(m.Industry, c.Value.(string)) == (c.Op == "eq")
+	case "country":
+		return strings.EqualFold(m.Country, c.Value.(string)) == (c.Op == "eq")
+	}
+
+	actual, _ := numericFeature(m, c.Field)
+	return numericOps[c.Op](actual, c.Value.(float64))
+}
+
+// numericFeature reads a numeric merchant metric by its JSON field name.
+func numericFeature(m *merchant.Merchant, field string) (float64, bool) {
+	switch field {
+	case "chargeback_rate":
+		return m.ChargebackRate.InexactFloat64(), true
+	case "chargeback_count_30d":
+		return float64(m.ChargebackCount30d), true
+	case "refund_rate":
+		return m.RefundRate.InexactFloat64(), true
+	case "velocity_multiplier":
+		return m.VelocityMultiplier.InexactFloat64(), true
+	case "account_age_days":
+		return float64(m.AccountAgeDays), true
+	case "transaction_volume_30d":
+		return m.TransactionVolume30d.InexactFloat64(), true
+	case "transaction_count_30d":
+		return float64(m.TransactionCount30d), true
+	default:
+		return 0, false
+	}
+}
+
+func (r HardStopRule) Triggered(m *merchant.Merchant) bool {
+	for _, condition := range r.Conditions {
+		if !condition.matches(m) {
+			return false
+		}
+	}
+	return len(r.Conditions) > 0
+}
+
+func validateHardStops(rules []HardStopRule) []error {
+	var errs []error
+	codes := make(map[string]bool, len(rules))
+
+	for i, rule := range rules {
+		name := fmt.Sprintf("hard_stops[%d]", i)
+		if rule.Code == "" {
+			errs = append(errs, fmt.Errorf("%s: code is required", name))
+		} else {
+			name = fmt.Sprintf("hard_stops %s", rule.Code)
+			if codes[rule.Code] {
+				errs = append(errs, fmt.Errorf("%s: duplicate code", name))
+			}
+			codes[rule.Code] = true
+		}
+
+		if len(rule.Conditions) == 0 {
+			errs = append(errs, fmt.Errorf("%s: at least one condition is required", name))
+		}
+		for _, condition := range rule.Conditions {
+			if err := condition.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+
+		if rule.MinRiskLevel == "" && rule.HoldPeriod == "" {
+			errs = append(errs, fmt.Errorf("%s: min_risk_level or hold_period is required", name))
+		}
+		if rule.MinRiskLevel != "" && !validRiskLevels[rule.MinRiskLevel] {
+			errs = append(errs, fmt.Errorf("%s: unknown min_risk_level %q", name, rule.MinRiskLevel))
+		}
+		if rule.HoldPeriod != "" && !validHoldPeriods[rule.HoldPeriod] {
+			errs = append(errs, fmt.Errorf("%s: unknown hold_period %q", name, rule.HoldPeriod))
+		}
+	}
+
+	return errs
+}
+
+// ApplyHardStops runs every rule against the merchant and tightens tier as
+// required. It returns the resulting tier and the rules that triggered, in
+// ruleset order. The strictest triggered min_risk_level is applied first and
+// the longest hold second, so the result does not depend on rule order, and
+// the hold and reserve never end up below the scored tier's. A min_risk_level
+// the tier table has no tier for is logged and leaves the level unchanged.
+func ApplyHardStops(rules []HardStopRule, m *merchant.Merchant, policy *PolicyMapper, tier PolicyTier) (PolicyTier, []HardStopRule) {
+	var triggered []HardStopRule
+	var minLevel RiskLevel
+	hold := tier.HoldPeriod
+
+	for _, rule := range rules {
+		if !rule.Triggered(m) {
+			continue
+		}
+		triggered = append(triggered, rule)
+
+		if rule.MinRiskLevel != "" && (minLevel == "" || riskLevelRank[minLevel] < riskLevelRank[rule.MinRiskLevel]) {
+			minLevel = rule.MinRiskLevel
+		}
+		if rule.HoldPeriod != "" && holdPeriodRank(hold) < holdPeriodRank(rule.HoldPeriod) {
+			hold = rule.HoldPeriod
+		}
+	}
+
+	scored := tier
+	if minLevel != "" && riskLevelRank[tier.RiskLevel] < riskLevelRank[minLevel] {
+		if forced, ok := policy.TierAtLeast(minLevel); ok {
+			tier = forced
+		} else {
+			log.Printf("[WARN] Hard stop min_risk_level %s has no tier in policy %s; merchant %s keeps %s",
+				minLevel, policy.Version(), m.ID, tier.RiskLevel)
+		}
+	}
+	if holdPeriodRank(tier.HoldPeriod) < holdPeriodRank(hold) {
+		tier.HoldPeriod = hold
+	}
+	if tier.ReservePercentage < scored.ReservePercentage {
+		tier.ReservePercentage = scored.ReservePercentage
+	}
+
+	return tier, triggered
+}
+
+func holdPeriodRank(hold HoldPeriod) int {
+	for i, h := range holdPeriodLadder {
+		if h == hold {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,219 @@
+package risk
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+func lowRiskUnverifiedMerchant(volume float64) *merchant.Merchant {
+	return &merchant.Merchant{
+		ID:                   uuid.New(),
+		Industry:             "RETAIL",
+		Country:              "BR",
+		AccountAgeDays:       800,
+		TransactionVolume30d: decimal.NewFromFloat(volume),
+		ChargebackRate:       decimal.NewFromFloat(0.3),
+		RefundRate:           decimal.NewFromFloat(2.0),
+		VelocityMultiplier:   decimal.NewFromFloat(1.2),
+		KYCVerified:          false,
+		KYCLevel:             "NONE",
+	}
+}
+
+func TestHardStopRuleTriggered(t *testing.T) {
+	m := lowRiskUnverifiedMerchant(75000)
+
+	tests := []struct {
+		name       string
+		conditions []RuleCondition
+		want       bool
+	}{
+		{"all conditions hold", []RuleCondition{
+			{Field: "kyc_verified", Op: "eq", Value: false},
+			{Field: "transaction_volume_30d", Op: "gt", Value: float64(50000)},
+		}, true},
+		{"one condition fails", []RuleCondition{
+			{Field: "kyc_verified", Op: "eq", Value: false},
+			{Field: "transaction_volume_30d", Op: "gt", Value: float64(100000)},
+		}, false},
+		{"string ne", []RuleCondition{{Field: "country", Op: "ne", Value: "MX"}}, true},
+		{"string eq is case insensitive", []RuleCondition{{Field: "industry", Op: "eq", Value: "retail"}}, true},
+		{"lte on boundary", []RuleCondition{{Field: "chargeback_rate", Op: "lte", Value: 0.3}}, true},
+		{"no conditions never trigger", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := HardStopRule{Code: "TEST", Conditions: tt.conditions, MinRiskLevel: RiskLevelHigh}
+			if got := rule.Triggered(m); got != tt.want {
+				t.Errorf("Triggered() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyHardStops(t *testing.T) {
+	policy := NewPolicyMapper()
+	lowTier := policy.DeterminePolicyTier(10)
+	m := lowRiskUnverifiedMerchant(75000)
+
+	t.Run("forces minimum risk level", func(t *testing.T) {
+		rules := DefaultRuleset().HardStops
+
+		tier, triggered := ApplyHardStops(rules, m, policy, lowTier)
+
+		if tier.RiskLevel != RiskLevelHigh {
+			t.Errorf("expected HIGH tier, got %s", tier.RiskLevel)
+		}
+		if len(triggered) != 1 || triggered[0].Code != "UNVERIFIED_HIGH_VOLUME" {
+			t.Errorf("expected UNVERIFIED_HIGH_VOLUME to trigger, got %+v", triggered)
+		}
+	})
+
+	t.Run("forces hold period only", func(t *testing.T) {
+		rules := []HardStopRule{{
+			Code:       "NEW_UNVERIFIED",
+			Conditions: []RuleCondition{{Field: "kyc_verified", Op: "eq", Value: false}},
+			HoldPeriod: HoldPeriod14Days,
+		}}
+
+		tier, _ := ApplyHardStops(rules, m, policy, lowTier)
+
+		if tier.RiskLevel != lowTier.RiskLevel {
+			t.Errorf("expected risk level unchanged at %s, got %s", lowTier.RiskLevel, tier.RiskLevel)
+		}
+		if tier.HoldPeriod != HoldPeriod14Days {
+			t.Errorf("expected 14_DAYS hold, got %s", tier.HoldPeriod)
+		}
+	})
+
+	t.Run("never loosens a stricter tier", func(t *testing.T) {
+		criticalTier := policy.DeterminePolicyTier(95)
+		rules := []HardStopRule{{
+			Code:         "UNVERIFIED",
+			Conditions:   []RuleCondition{{Field: "kyc_verified", Op: "eq", Value: false}},
+			MinRiskLevel: RiskLevelMedium,
+			HoldPeriod:   HoldPeriod7Days,
+		}}
+
+		tier, triggered := ApplyHardStops(rules, m, policy, criticalTier)
+
+		if tier != criticalTier {
+			t.Errorf("expected tier unchanged, got %+v", tier)
+		}
+		if len(triggered) != 1 {
+			t.Errorf("expected rule still reported as triggered, got %d", len(triggered))
+		}
+	})
+
+	t.Run("hold rule before a min level rule", func(t *testing.T) {
+		tiers := DefaultPolicyTiers()
+		tiers[3].HoldPeriod = HoldPeriod14Days
+		replaced := NewPolicyMapperFromTiers("short-high-hold", tiers)
+		unverified := []RuleCondition{{Field: "kyc_verified", Op: "eq", Value: false}}
+		rules := []HardStopRule{
+			{Code: "HOLD", Conditions: unverified, HoldPeriod: HoldPeriod45Days},
+			{Code: "MIN_LEVEL", Conditions: unverified, MinRiskLevel: RiskLevelHigh},
+		}
+
+		for _, order := range [][]HardStopRule{rules, {rules[1], rules[0]}} {
+			tier, triggered := ApplyHardStops(order, m, replaced, replaced.DeterminePolicyTier(10))
+
+			if tier.RiskLevel != RiskLevelHigh || tier.HoldPeriod != HoldPeriod45Days {
+				t.Errorf("%s first: expected HIGH with 45_DAYS hold, got %s with %s",
+					order[0].Code, tier.RiskLevel, tier.HoldPeriod)
+			}
+			if len(triggered) != 2 {
+				t.Errorf("%s first: expected both rules triggered, got %d", order[0].Code, len(triggered))
+			}
+		}
+	})
+
+	t.Run("min level without a tier", func(t *testing.T) {
+		capped := NewPolicyMapperFromTiers("no-critical", DefaultPolicyTiers()[:4])
+		rules := []HardStopRule{{
+			Code:         "UNVERIFIED",
+			Conditions:   []RuleCondition{{Field: "kyc_verified", Op: "eq", Value: false}},
+			MinRiskLevel: RiskLevelCritical,
+			HoldPeriod:   HoldPeriod14Days,
+		}}
+
+		tier, _ := ApplyHardStops(rules, m, capped, lowTier)
+
+		if tier.RiskLevel != lowTier.RiskLevel || tier.HoldPeriod != HoldPeriod14Days {
+			t.Errorf("expected LOW with the rule's 14_DAYS hold, got %s with %s", tier.RiskLevel, tier.HoldPeriod)
+		}
+	})
+}
+
+func TestValidateHardStops(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    HardStopRule
+		wantErr string
+	}{
+		{"unknown field", HardStopRule{Code: "A", MinRiskLevel: RiskLevelHigh,
+			Conditions: []RuleCondition{{Field: "mcc", Op: "eq", Value: "5411"}}}, "unknown field"},
+		{"bad op for bool", HardStopRule{Code: "A", MinRiskLevel: RiskLevelHigh,
+			Conditions: []RuleCondition{{Field: "kyc_verified", Op: "gt", Value: false}}}, "op must be eq or ne"},
+		{"wrong value type", HardStopRule{Code: "A", MinRiskLevel: RiskLevelHigh,
+			Conditions: []RuleCondition{{Field: "chargeback_rate", Op: "gt", Value: "3"}}}, "value must be a number"},
+		{"no effect", HardStopRule{Code: "A",
+			Conditions: []RuleCondition{{Field: "chargeback_rate", Op: "gt", Value: float64(3)}}}, "min_risk_level or hold_period"},
+		{"unknown risk level", HardStopRule{Code: "A", MinRiskLevel: "SEVERE",
+			Conditions: []RuleCondition{{Field: "chargeback_rate", Op: "gt", Value: float64(3)}}}, "unknown min_risk_level"},
+		{"no conditions", HardStopRule{Code: "A", MinRiskLevel: RiskLevelHigh}, "at least one condition"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := DefaultRuleset()
+			rs.HardStops = []HardStopRule{tt.rule}
+
+			err := rs.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEvaluateMerchantHardStop(t *testing.T) {
+	m := lowRiskUnverifiedMerchant(75000)
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return m, nil
+		},
+	}
+
+	service := NewService(merchantStore, &mockDecisionRepository{})
+	decision, err := service.EvaluateMerchant(context.Background(), m.ID, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.RiskScore > 20 {
+		t.Fatalf("expected a low additive score, got %d", decision.RiskScore)
+	}
+	if decision.RiskLevel != RiskLevelHigh {
+		t.Errorf("expected hard stop to force HIGH, got %s", decision.RiskLevel)
+	}
+
+	var found bool
+	for _, factor := range decision.Reasoning.PrimaryFactors {
+		if factor.Impact == "HARD_STOP" && strings.Contains(factor.Factor, "UNVERIFIED_HIGH_VOLUME") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected HARD_STOP factor in reasoning, got %+v", decision.Reasoning.PrimaryFactors)
+	}
+	if !strings.Contains(decision.Reasoning.PolicyExplanation, "hard stop") {
+		t.Errorf("expected policy explanation to mention hard stop, got %q", decision.Reasoning.PolicyExplanation)
+	}
+}
@@ -0,0 +1,93 @@
+package risk
+
+// Hysteresis damps upgrades to a better tier so merchants scoring near a
+// boundary do not flap between policies. Downgrades always apply at once. An
+// upgrade applies when the score clears the current tier's lower boundary by
+// more than Margin points, or when ConsecutiveEvaluations evaluations in a
+// row, including this one, have qualified for it. A zero value disables that
+// route; hysteresis is off when both are zero.
+type Hysteresis struct {
+	Margin                 int
+	ConsecutiveEvaluations int
+}
+
+// HysteresisHold records that hysteresis kept a merchant in its previous tier
+// and what the upgrade still requires.
+type HysteresisHold struct {
+	ScoredRiskLevel       RiskLevel `json:"scored_risk_level"`
+	HeldRiskLevel         RiskLevel `json:"held_risk_level"`
+	RequiredScore         *int      `json:"required_score,omitempty"`
+	QualifyingEvaluations int       `json:"qualifying_evaluations,omitempty"`
+	RequiredEvaluations   int       `json:"required_evaluations,omitempty"`
+}
+
+func (h Hysteresis) Enabled() bool {
+	return h.Margin > 0 || h.ConsecutiveEvaluations > 0
+}
+
+// Apply decides the tier for an evaluation given the merchant's recent
+// persisted decisions, newest first. tier is returned unchanged unless it is
+// better than the latest decision's tier and neither upgrade route is met, in
+// which case the latest tier is kept and the hold is described. Pending and
+// rejected decisions never took effect, so they are skipped.
+func (h Hysteresis) Apply(policy *PolicyMapper, score int, tier PolicyTier, recent []RiskDecision) (PolicyTier, *HysteresisHold) {
+	recent = tookEffect(recent)
+	if !h.Enabled() || len(recent) == 0 {
+		return tier, nil
+	}
+
+	previous := recent[0]
+	if riskLevelRank[tier.RiskLevel] >= riskLevelRank[previous.RiskLevel] {
+		return tier, nil
+	}
+
+	held, ok := policy.TierAtLeast(previous.RiskLevel)
+	if !ok {
+		return tier, nil
+	}
+
+	hold := &HysteresisHold{
+		ScoredRiskLevel: tier.RiskLevel,
+		HeldRiskLevel:   held.RiskLevel,
+	}
+
+	if h.Margin > 0 {
+		required := held.MinScore - 1 - h.Margin
+		if score <= required {
+			return tier, nil
+		}
+		hold.RequiredScore = &required
+	}
+
+	if h.ConsecutiveEvaluations > 0 {
+		qualifying := 1
+		for _, d := range recent {
+			if d.Reasoning.Hysteresis == nil || d.Reasoning.Hysteresis.HeldRiskLevel != held.RiskLevel {
+				break
+			}
+			qualifying++
+		}
+		if qualifying >= h.ConsecutiveEvaluations {
+			return tier, nil
+		}
+		hold.QualifyingEvaluations = qualifying
+		hold.RequiredEvaluations = h.ConsecutiveEvaluations
+	}
+
+	// A hard stop may have lengthened the hold on the better tier; keep it.
+	if holdPeriodRank(tier.HoldPeriod) > holdPeriodRank(held.HoldPeriod) {
+		held.HoldPeriod = tier.HoldPeriod
+	}
+
+	return held, hold
+}
+
+func tookEffect(decisions []RiskDecision) []RiskDecision {
+	effective := make([]RiskDecision, 0, len(decisions))
+	for _, d := range decisions {
+		if d.Status != DecisionPendingApproval && d.Status != DecisionRejected && d.Status != DecisionSuperseded {
+			effective = append(effective, d)
+		}
+	}
+	return effective
+}
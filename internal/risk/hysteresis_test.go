@@ -0,0 +1,116 @@
+package risk
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+func heldDecision(level RiskLevel) RiskDecision {
+	return RiskDecision{
+		RiskLevel: level,
+		Reasoning: Reasoning{Hysteresis: &HysteresisHold{HeldRiskLevel: level}},
+	}
+}
+
+func TestHysteresisApply(t *testing.T) {
+	policy := NewPolicyMapper()
+	previousMedium := []RiskDecision{{RiskLevel: RiskLevelMedium}}
+
+	tests := []struct {
+		name       string
+		hysteresis Hysteresis
+		score      int
+		recent     []RiskDecision
+		want       RiskLevel
+		held       bool
+	}{
+		{"disabled", Hysteresis{}, 40, previousMedium, RiskLevelMediumLow, false},
+		{"no history", Hysteresis{Margin: 3}, 40, nil, RiskLevelMediumLow, false},
+		{"downgrade applies immediately", Hysteresis{Margin: 3}, 65, previousMedium, RiskLevelHigh, false},
+		{"upgrade within margin is held", Hysteresis{Margin: 3}, 40, previousMedium, RiskLevelMedium, true},
+		{"upgrade clearing margin applies", Hysteresis{Margin: 3}, 37, previousMedium, RiskLevelMediumLow, false},
+		{"streak not yet reached", Hysteresis{ConsecutiveEvaluations: 3}, 40,
+			[]RiskDecision{heldDecision(RiskLevelMedium), {RiskLevel: RiskLevelMedium}}, RiskLevelMedium, true},
+		{"streak reached", Hysteresis{ConsecutiveEvaluations: 3}, 40,
+			[]RiskDecision{heldDecision(RiskLevelMedium), heldDecision(RiskLevelMedium)}, RiskLevelMediumLow, false},
+		{"either route upgrades", Hysteresis{Margin: 10, ConsecutiveEvaluations: 2}, 40,
+			[]RiskDecision{heldDecision(RiskLevelMedium)}, RiskLevelMediumLow, false},
+		{"rejected tier does not hold", Hysteresis{ConsecutiveEvaluations: 3}, 5,
+			[]RiskDecision{{RiskLevel: RiskLevelCritical, Status: DecisionRejected}, {RiskLevel: RiskLevelLow, Status: DecisionEffective}},
+			RiskLevelLow, false},
+		{"pending tier does not hold", Hysteresis{ConsecutiveEvaluations: 2}, 40,
+			[]RiskDecision{{RiskLevel: RiskLevelCritical, Status: DecisionPendingApproval}}, RiskLevelMediumLow, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tier := policy.DeterminePolicyTier(tt.score)
+
+			got, hold := tt.hysteresis.Apply(policy, tt.score, tier, tt.recent)
+			if got.RiskLevel != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got.RiskLevel)
+			}
+			if (hold != nil) != tt.held {
+				t.Errorf("expected held=%v, got %+v", tt.held, hold)
+			}
+		})
+	}
+
+	t.Run("hold describes what is still required", func(t *testing.T) {
+		h := Hysteresis{Margin: 3, ConsecutiveEvaluations: 3}
+		_, hold := h.Apply(policy, 40, policy.DeterminePolicyTier(40), []RiskDecision{heldDecision(RiskLevelMedium)})
+		if hold == nil {
+			t.Fatal("expected hold")
+		}
+		if *hold.RequiredScore != 37 || hold.QualifyingEvaluations != 2 || hold.RequiredEvaluations != 3 {
+			t.Errorf("unexpected hold %+v", hold)
+		}
+	})
+}
+
+func TestEvaluateMerchantHysteresis(t *testing.T) {
+	// 20 chargeback + 5 age + 5 category + 7 KYC = 37, MEDIUM_LOW.
+	m := &merchant.Merchant{
+		ID:                 uuid.New(),
+		Industry:           "RETAIL",
+		AccountAgeDays:     400,
+		ChargebackRate:     decimal.NewFromFloat(1.2),
+		VelocityMultiplier: decimal.NewFromFloat(1.0),
+		RefundRate:         decimal.NewFromFloat(1.0),
+		KYCVerified:        true,
+		KYCLevel:           "PARTIAL",
+	}
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return m, nil
+		},
+	}
+	decisionStore := &mockDecisionRepository{
+		listRecentByMerchant: func(ctx context.Context, merchantID uuid.UUID, limit int) ([]RiskDecision, error) {
+			return []RiskDecision{{RiskScore: 42, RiskLevel: RiskLevelMedium}}, nil
+		},
+	}
+
+	service := NewService(merchantStore, decisionStore, WithHysteresis(Hysteresis{Margin: 5}))
+	decision, err := service.EvaluateMerchant(context.Background(), m.ID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decision.RiskScore != 37 || decision.RiskLevel != RiskLevelMedium || decision.PayoutHoldPeriod != HoldPeriod14Days {
+		t.Errorf("expected score 37 held in MEDIUM/14_DAYS, got %d %s/%s",
+			decision.RiskScore, decision.RiskLevel, decision.PayoutHoldPeriod)
+	}
+	if decision.Reasoning.Hysteresis == nil {
+		t.Fatal("expected hysteresis on reasoning")
+	}
+	want := "Held in MEDIUM tier by hysteresis although the score qualifies for MEDIUM_LOW. Upgrade requires a score of 35 or lower."
+	if !strings.Contains(decision.Reasoning.PolicyExplanation, want) {
+		t.Errorf("expected policy explanation to contain %q, got %q", want, decision.Reasoning.PolicyExplanation)
+	}
+}
@@ -0,0 +1,255 @@
+package risk
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const DefaultLanguage = "en"
+
+// SupportedLanguages lists the languages with a message catalog in locales/.
+var SupportedLanguages = []string{"en", "pt-BR", "es"}
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var catalogs = loadCatalogs()
+
+type catalog map[string]string
+
+func loadCatalogs() map[string]catalog {
+	loaded := make(map[string]catalog, len(SupportedLanguages))
+	for _, lang := range SupportedLanguages {
+		data, err := localeFiles.ReadFile("locales/" + lang + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("missing message catalog for %s: %v", lang, err))
+		}
+		var c catalog
+		if err := json.Unmarshal(data, &c); err != nil {
+			panic(fmt.Sprintf("invalid message catalog for %s: %v", lang, err))
+		}
+		loaded[lang] = c
+	}
+	return loaded
+}
+
+// catalogFor returns the catalog for lang, falling back to English.
+func catalogFor(lang string) catalog {
+	if c, ok := catalogs[lang]; ok {
+		return c
+	}
+	return catalogs[DefaultLanguage]
+}
+
+// has reports whether the catalog or the English fallback defines key.
+func (c catalog) has(key string) bool {
+	if _, ok := c[key]; ok {
+		return true
+	}
+	_, ok := catalogs[DefaultLanguage][key]
+	return ok
+}
+
+// format renders key with args, falling back to the English message and then
+// to the key itself when a translation is missing.
+func (c catalog) format(key string, args ...interface{}) string {
+	msg, ok := c[key]
+	if !ok {
+		msg, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+type languageKey struct{}
+
+// WithLanguage returns a context that asks the explainer to render reasoning
+// in lang.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+func LanguageFromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// NegotiateLanguage picks the best supported language for an Accept-Language
+// header. Exact tags win over a shared primary subtag, so "pt" and "pt-PT"
+// both resolve to pt-BR. Anything unsupported resolves to English.
+func NegotiateLanguage(header string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	for _, c := range candidates {
+		if c.tag == "*" {
+			return DefaultLanguage
+		}
+		for _, lang := range SupportedLanguages {
+			if strings.EqualFold(c.tag, lang) {
+				return lang
+			}
+		}
+		primary, _, _ := strings.Cut(c.tag, "-")
+		for _, lang := range SupportedLanguages {
+			langPrimary, _, _ := strings.Cut(lang, "-")
+			if strings.EqualFold(primary, langPrimary) {
+				return lang
+			}
+		}
+	}
+
+	return DefaultLanguage
+}
+
+// Message is a catalog key and the arguments it was rendered with. Reasoning
+// keeps the messages behind its text so a stored decision can be shown in any
+// supported language.
+type Message struct {
+	Key  string      `json:"key"`
+	Args MessageArgs `json:"args,omitempty"`
+}
+
+func newMessage(key string, args ...interface{}) Message {
+	normalized := make(MessageArgs, len(args))
+	for i, arg := range args {
+		normalized[i] = normalizeArg(arg)
+	}
+	return Message{Key: key, Args: normalized}
+}
+
+// normalizeArg reduces arg to the types MessageArgs can store: string, int64,
+// float64 or a nested Message.
+func normalizeArg(arg interface{}) interface{} {
+	if m, ok := arg.(Message); ok {
+		return m
+	}
+	v := reflect.ValueOf(arg)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	default:
+		return fmt.Sprint(arg)
+	}
+}
+
+func (m Message) render(c catalog) string {
+	args := make([]interface{}, len(m.Args))
+	for i, arg := range m.Args {
+		if nested, ok := arg.(Message); ok {
+			arg = nested.render(c)
+		}
+		args[i] = arg
+	}
+	return c.format(m.Key, args...)
+}
+
+func renderMessages(c catalog, messages []Message) string {
+	var b strings.Builder
+	for _, m := range messages {
+		b.WriteString(m.render(c))
+	}
+	return b.String()
+}
+
+// MessageArgs are stored with their type, so a message read back from the
+// database formats exactly as it did when it was recorded.
+type MessageArgs []interface{}
+
+type messageArg struct {
+	String  *string  `json:"s,omitempty"`
+	Int     *int64   `json:"i,omitempty"`
+	Float   *float64 `json:"f,omitempty"`
+	Message *Message `json:"m,omitempty"`
+}
+
+func (a MessageArgs) MarshalJSON() ([]byte, error) {
+	encoded := make([]messageArg, len(a))
+	for i, arg := range a {
+		switch v := normalizeArg(arg).(type) {
+		case string:
+			encoded[i].String = &v
+		case int64:
+			encoded[i].Int = &v
+		case float64:
+			encoded[i].Float = &v
+		case Message:
+			encoded[i].Message = &v
+		}
+	}
+	return json.Marshal(encoded)
+}
+
+func (a *MessageArgs) UnmarshalJSON(data []byte) error {
+	var encoded []messageArg
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	args := make(MessageArgs, len(encoded))
+	for i, arg := range encoded {
+		switch {
+		case arg.String != nil:
+			args[i] = *arg.String
+		case arg.Int != nil:
+			args[i] = *arg.Int
+		case arg.Float != nil:
+			args[i] = *arg.Float
+		case arg.Message != nil:
+			args[i] = *arg.Message
+		default:
+			return fmt.Errorf("message argument %d has no value", i)
+		}
+	}
+	*a = args
+	return nil
+}
+
+// Localize renders the reasoning's text in lang from its recorded messages.
+// Reasoning stored before messages were recorded keeps its text as stored.
+func (r *Reasoning) Localize(lang string) {
+	c := catalogFor(lang)
+	for i := range r.PrimaryFactors {
+		if factor := &r.PrimaryFactors[i]; len(factor.Messages) > 0 {
+			factor.Contribution = renderMessages(c, factor.Messages)
+		}
+	}
+	if len(r.PolicyMessages) > 0 {
+		r.PolicyExplanation = renderMessages(c, r.PolicyMessages)
+	}
+}
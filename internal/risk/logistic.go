@@ -0,0 +1,304 @@
+package risk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+// LogisticModel is a logistic-regression model exported by the data science
+// team. Features are standardized with Mean and Std before the coefficient is
+// applied, and the resulting probability is mapped to 0-100 by Calibration.
+type LogisticModel struct {
+	Version     string             `json:"version"`
+	Intercept   float64            `json:"intercept"`
+	Features    []LogisticFeature  `json:"features"`
+	Calibration []CalibrationPoint `json:"calibration,omitempty"`
+}
+
+// LogisticFeature names a numeric merchant field (chargeback_rate,
+// account_age_days, ...), the boolean kyc_verified, or an indicator of the form
+// industry=TRAVEL or kyc_level=NONE.
+type LogisticFeature struct {
+	Name        string  `json:"name"`
+	Coefficient float64 `json:"coefficient"`
+	Mean        float64 `json:"mean,omitempty"`
+	Std         float64 `json:"std,omitempty"`
+}
+
+// CalibrationPoint maps a model probability to a risk score. Scores between
+// points are linearly interpolated. Without calibration the score is the
+// probability times 100.
+type CalibrationPoint struct {
+	Probability float64 `json:"probability"`
+	Score       float64 `json:"score"`
+}
+
+// LogisticScorer scores merchants with a LogisticModel. The factor breakdown
+// splits the final score across factors in proportion to each factor's
+// positive contribution to the logit, so it is an attribution rather than an
+// exact decomposition. Features without a factor of their own, such as
+// country indicators, are spread across the others, and no factor is
+// attributed more than the ruleset's max_points for it.
+type LogisticScorer struct {
+	model  *LogisticModel
+	maxima map[string]int
+}
+
+func NewLogisticScorer(model *LogisticModel) *LogisticScorer {
+	return NewLogisticScorerFromRuleset(model, DefaultRuleset())
+}
+
+// NewLogisticScorerFromRuleset caps each factor's attributed points at its
+// max_points in rs, the ruleset the reasoning is explained against.
+func NewLogisticScorerFromRuleset(model *LogisticModel, rs *Ruleset) *LogisticScorer {
+	return &LogisticScorer{
+		model: model,
+		maxima: map[string]int{
+			"chargeback":  rs.Chargeback.MaxPoints,
+			"account_age": rs.AccountAge.MaxPoints,
+			"velocity":    rs.Velocity.MaxPoints,
+			"category":    rs.Category.MaxPoints,
+			"kyc":         rs.KYC.MaxPoints,
+			"refund":      rs.Refund.MaxPoints,
+		},
+	}
+}
+
+func (s *LogisticScorer) Version() string {
+	return s.model.Version
+}
+
+func (s *LogisticScorer) Score(m *merchant.Merchant) (int, FactorScore) {
+	logit := s.model.Intercept
+	contributions := make(map[string]float64)
+
+	for _, feature := range s.model.Features {
+		x, _ := logisticFeatureValue(m, feature.Name)
+		std := feature.Std
+		if std == 0 {
+			std = 1
+		}
+
+		c := feature.Coefficient * (x - feature.Mean) / std
+		logit += c
+		contributions[featureFactor(feature.Name)] += c
+	}
+
+	probability := 1 / (1 + math.Exp(-logit))
+	score := int(math.Round(s.model.calibrate(probability)))
+	if score < 0 {
+		score = 0
+	}
+	if score > 100 {
+		score = 100
+	}
+
+	return score, attributeScore(score, contributions, s.maxima)
+}
+
+func (m *LogisticModel) calibrate(p float64) float64 {
+	points := m.Calibration
+	if len(points) == 0 {
+		return p * 100
+	}
+
+	if p <= points[0].Probability {
+		return points[0].Score
+	}
+	for i := 1; i < len(points); i++ {
+		if p <= points[i].Probability {
+			lo, hi := points[i-1], points[i]
+			t := (p - lo.Probability) / (hi.Probability - lo.Probability)
+			return lo.Score + t*(hi.Score-lo.Score)
+		}
+	}
+	return points[len(points)-1].Score
+}
+
+// attributeScore splits score across the factors in maxima in proportion to
+// their positive contributions. A factor whose share would exceed its maximum
+// is capped there and the excess is split among the rest; points no factor
+// can take are left unattributed.
+func attributeScore(score int, contributions map[string]float64, maxima map[string]int) FactorScore {
+	points := make(map[string]float64)
+	open := make(map[string]bool)
+	for factor, c := range contributions {
+		if c > 0 && maxima[factor] > 0 {
+			open[factor] = true
+		}
+	}
+
+	remaining := float64(score)
+	for remaining > 0 && len(open) > 0 {
+		var positive float64
+		for factor := range open {
+			positive += contributions[factor]
+		}
+
+		var capped []string
+		for factor := range open {
+			if remaining*contributions[factor]/positive >= float64(maxima[factor]) {
+				capped = append(capped, factor)
+			}
+		}
+		if len(capped) == 0 {
+			for factor := range open {
+				points[factor] = remaining * contributions[factor] / positive
+			}
+			break
+		}
+		for _, factor := range capped {
+			points[factor] = float64(maxima[factor])
+			remaining -= points[factor]
+			delete(open, factor)
+		}
+	}
+
+	share := func(factor string) int {
+		return int(math.Round(points[factor]))
+	}
+
+	return FactorScore{
+		Chargeback: share("chargeback"),
+		AccountAge: share("account_age"),
+		Velocity:   share("velocity"),
+		Category:   share("category"),
+		KYC:        share("kyc"),
+		Refund:     share("refund"),
+	}
+}
+
+// featureFactor assigns a model feature to the factor it is reported under.
+// Features with no matching factor, such as country=MX, return "".
+func featureFactor(name string) string {
+	switch {
+	case strings.HasPrefix(name, "chargeback_"):
+		return "chargeback"
+	case name == "account_age_days":
+		return "account_age"
+	case name == "velocity_multiplier", name == "transaction_volume_30d", name == "transaction_count_30d":
+		return "velocity"
+	case strings.HasPrefix(name, "industry="):
+		return "category"
+	case strings.HasPrefix(name, "kyc_"):
+		return "kyc"
+	case name == "refund_rate":
+		return "refund"
+	default:
+		return ""
+	}
+}
+
+func logisticFeatureValue(m *merchant.Merchant, name string) (float64, bool) {
+	if field, value, ok := strings.Cut(name, "="); ok {
+		var actual string
+		switch field {
+		case "industry":
+			actual = m.Industry
+		case "kyc_level":
+			actual = m.KYCLevel
+		case "country":
+			actual = m.Country
+		default:
+			return 0, false
+		}
+		if strings.EqualFold(actual, value) {
+			return 1, true
+		}
+		return 0, true
+	}
+
+	if name == "kyc_verified" {
+		if m.KYCVerified {
+			return 1, true
+		}
+		return 0, true
+	}
+
+	return numericFeature(m, name)
+}
+
+func (m *LogisticModel) Validate() error {
+	var errs []error
+
+	if m.Version == "" {
+		errs = append(errs, errors.New("version is required"))
+	}
+	if len(m.Features) == 0 {
+		errs = append(errs, errors.New("at least one feature is required"))
+	}
+
+	probe := &merchant.Merchant{}
+	seen := make(map[string]bool, len(m.Features))
+	for _, feature := range m.Features {
+		if _, ok := logisticFeatureValue(probe, feature.Name); !ok {
+			errs = append(errs, fmt.Errorf("unknown feature %q", feature.Name))
+		}
+		if seen[feature.Name] {
+			errs = append(errs, fmt.Errorf("duplicate feature %q", feature.Name))
+		}
+		seen[feature.Name] = true
+		if feature.Std < 0 {
+			errs = append(errs, fmt.Errorf("feature %q: std must not be negative", feature.Name))
+		}
+	}
+
+	for i, point := range m.Calibration {
+		if point.Probability < 0 || point.Probability > 1 {
+			errs = append(errs, fmt.Errorf("calibration[%d]: probability outside [0, 1]", i))
+		}
+		if point.Score < 0 || point.Score > 100 {
+			errs = append(errs, fmt.Errorf("calibration[%d]: score outside [0, 100]", i))
+		}
+		if i == 0 {
+			continue
+		}
+		prev := m.Calibration[i-1]
+		if point.Probability <= prev.Probability {
+			errs = append(errs, fmt.Errorf("calibration[%d]: probabilities must ascend", i))
+		}
+		if point.Score < prev.Score {
+			errs = append(errs, fmt.Errorf("calibration[%d]: scores must not decrease", i))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ParseLogisticModel decodes a JSON model, rejecting unknown fields, and
+// validates it.
+func ParseLogisticModel(data []byte) (*LogisticModel, error) {
+	var model LogisticModel
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&model); err != nil {
+		return nil, fmt.Errorf("failed to decode logistic model: %w", err)
+	}
+
+	if err := model.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid logistic model: %w", err)
+	}
+
+	return &model, nil
+}
+
+func LoadLogisticModel(path string) (*LogisticModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logistic model %s: %w", path, err)
+	}
+
+	model, err := ParseLogisticModel(data)
+	if err != nil {
+		return nil, fmt.Errorf("logistic model %s: %w", path, err)
+	}
+
+	return model, nil
+}
@@ -0,0 +1,202 @@
+package risk
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+func TestLoadLogisticModel(t *testing.T) {
+	t.Run("shipped example loads", func(t *testing.T) {
+		model, err := LoadLogisticModel("../../models/logistic_example.json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if model.Version != "logistic-example-v1" {
+			t.Errorf("expected version logistic-example-v1, got %s", model.Version)
+		}
+	})
+
+	t.Run("invalid model is rejected", func(t *testing.T) {
+		_, err := LoadLogisticModel("testdata/invalid_logistic_model.json")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		for _, want := range []string{
+			`unknown feature "mcc_code"`,
+			`feature "chargeback_rate": std must not be negative`,
+			`calibration[1]: probabilities must ascend`,
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to contain %q, got %v", want, err)
+			}
+		}
+	})
+}
+
+func TestLogisticScorer(t *testing.T) {
+	t.Run("uncalibrated score is probability times 100", func(t *testing.T) {
+		scorer := NewLogisticScorer(&LogisticModel{
+			Version:  "test",
+			Features: []LogisticFeature{{Name: "chargeback_rate", Coefficient: 1}},
+		})
+
+		score, factors := scorer.Score(&merchant.Merchant{ChargebackRate: decimal.NewFromFloat(2)})
+
+		want := int(math.Round(100 / (1 + math.Exp(-2))))
+		if score != want {
+			t.Errorf("Score() = %d, want %d", score, want)
+		}
+		if factors != (FactorScore{Chargeback: 30}) {
+			t.Errorf("expected chargeback attribution capped at its 30 max points, got %+v", factors)
+		}
+	})
+
+	t.Run("country points spread across factors", func(t *testing.T) {
+		scorer := NewLogisticScorer(&LogisticModel{
+			Version:   "test",
+			Intercept: -3,
+			Features: []LogisticFeature{
+				{Name: "chargeback_rate", Coefficient: 0.5},
+				{Name: "refund_rate", Coefficient: 0.1},
+				{Name: "country=MX", Coefficient: 1.5},
+			},
+		})
+
+		score, factors := scorer.Score(&merchant.Merchant{
+			ChargebackRate: decimal.NewFromFloat(1),
+			RefundRate:     decimal.NewFromFloat(1),
+			Country:        "MX",
+		})
+
+		if score != 29 {
+			t.Fatalf("Score() = %d, want 29", score)
+		}
+		if factors != (FactorScore{Chargeback: 24, Refund: 5}) {
+			t.Errorf("expected the score split between chargeback and refund, got %+v", factors)
+		}
+	})
+
+	t.Run("attribution within factor maxima", func(t *testing.T) {
+		maxima := NewLogisticScorer(&LogisticModel{}).maxima
+
+		tests := []struct {
+			name          string
+			score         int
+			contributions map[string]float64
+			want          FactorScore
+		}{
+			{"unassigned contributions", 40, map[string]float64{"chargeback": 1, "category": 1, "": 2},
+				FactorScore{Chargeback: 25, Category: 15}},
+			{"excess moves to open factors", 40, map[string]float64{"refund": 3, "chargeback": 1, "kyc": 1},
+				FactorScore{Chargeback: 25, KYC: 10, Refund: 5}},
+			{"excess beyond every maximum", 60, map[string]float64{"refund": 3, "chargeback": 1, "kyc": 1},
+				FactorScore{Chargeback: 30, KYC: 10, Refund: 5}},
+			{"negative contributions", 10, map[string]float64{"velocity": 1, "account_age": -3},
+				FactorScore{Velocity: 10}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if got := attributeScore(tt.score, tt.contributions, maxima); got != tt.want {
+					t.Errorf("attributeScore() = %+v, want %+v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("calibration interpolates between points", func(t *testing.T) {
+		model := &LogisticModel{
+			Calibration: []CalibrationPoint{
+				{Probability: 0, Score: 0},
+				{Probability: 0.5, Score: 80},
+				{Probability: 1, Score: 100},
+			},
+		}
+
+		if got := model.calibrate(0.25); got != 40 {
+			t.Errorf("calibrate(0.25) = %v, want 40", got)
+		}
+		if got := model.calibrate(0.75); got != 90 {
+			t.Errorf("calibrate(0.75) = %v, want 90", got)
+		}
+	})
+
+	t.Run("riskier merchant scores higher", func(t *testing.T) {
+		model, err := LoadLogisticModel("../../models/logistic_example.json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		scorer := NewLogisticScorer(model)
+
+		safe, _ := scorer.Score(&merchant.Merchant{
+			ChargebackRate:     decimal.NewFromFloat(0.2),
+			AccountAgeDays:     900,
+			VelocityMultiplier: decimal.NewFromFloat(1.0),
+			RefundRate:         decimal.NewFromFloat(1.0),
+			Industry:           "UTILITIES",
+			KYCVerified:        true,
+		})
+		risky, factors := scorer.Score(&merchant.Merchant{
+			ChargebackRate:     decimal.NewFromFloat(3.0),
+			AccountAgeDays:     20,
+			VelocityMultiplier: decimal.NewFromFloat(4.0),
+			RefundRate:         decimal.NewFromFloat(8.0),
+			Industry:           "DIGITAL_GOODS",
+			KYCVerified:        false,
+		})
+
+		if safe >= risky {
+			t.Errorf("expected safe score %d below risky score %d", safe, risky)
+		}
+		if risky < 0 || risky > 100 {
+			t.Errorf("score %d outside [0, 100]", risky)
+		}
+		if factors.Chargeback == 0 || factors.Category == 0 {
+			t.Errorf("expected chargeback and category attribution, got %+v", factors)
+		}
+	})
+}
+
+func TestEvaluateMerchantWithScorer(t *testing.T) {
+	merchantID := uuid.New()
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return &merchant.Merchant{
+				ID:             merchantID,
+				Industry:       "RETAIL",
+				AccountAgeDays: 800,
+				ChargebackRate: decimal.NewFromFloat(0.3),
+				KYCVerified:    true,
+				KYCLevel:       "FULL",
+			}, nil
+		},
+	}
+	scorer := NewLogisticScorer(&LogisticModel{
+		Version:   "logistic-test",
+		Intercept: 0,
+		Features:  []LogisticFeature{{Name: "kyc_verified", Coefficient: 2}},
+	})
+
+	service := NewService(merchantStore, &mockDecisionRepository{}, WithScorer(scorer))
+	decision, err := service.EvaluateMerchant(context.Background(), merchantID, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.ModelVersion != "logistic-test" {
+		t.Errorf("expected model version logistic-test, got %s", decision.ModelVersion)
+	}
+	if decision.RiskScore != 88 {
+		t.Errorf("expected score 88 from logistic scorer, got %d", decision.RiskScore)
+	}
+	if service.ModelVersion() != "logistic-test" {
+		t.Errorf("expected service model version logistic-test, got %s", service.ModelVersion())
+	}
+}
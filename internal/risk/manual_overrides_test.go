@@ -0,0 +1,280 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+type mockManualOverrideStore struct {
+	overrides []ManualOverride
+	// staleReads makes GetActive miss active overrides, as when another
+	// request creates one between the check and the insert.
+	staleReads bool
+}
+
+func (m *mockManualOverrideStore) GetActive(ctx context.Context, merchantID uuid.UUID, at time.Time) (*ManualOverride, error) {
+	if m.staleReads {
+		return nil, nil
+	}
+	for i := len(m.overrides) - 1; i >= 0; i-- {
+		o := m.overrides[i]
+		if o.MerchantID == merchantID && o.ActiveAt(at) {
+			return &o, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockManualOverrideStore) Create(ctx context.Context, override *ManualOverride) error {
+	for _, o := range m.overrides {
+		if o.MerchantID == override.MerchantID && o.ActiveAt(time.Now()) {
+			return ErrActiveManualOverride
+		}
+	}
+	override.ID = uuid.New()
+	m.overrides = append(m.overrides, *override)
+	return nil
+}
+
+func (m *mockManualOverrideStore) Get(ctx context.Context, id uuid.UUID) (*ManualOverride, error) {
+	for _, o := range m.overrides {
+		if o.ID == id {
+			return &o, nil
+		}
+	}
+	return nil, errors.New("manual override not found")
+}
+
+func (m *mockManualOverrideStore) ListByMerchant(ctx context.Context, merchantID uuid.UUID) ([]ManualOverride, error) {
+	return m.overrides, nil
+}
+
+func (m *mockManualOverrideStore) Revoke(ctx context.Context, id uuid.UUID, revokedBy, reason string, at time.Time) error {
+	for i := range m.overrides {
+		if m.overrides[i].ID == id {
+			m.overrides[i].RevokedAt = &at
+			m.overrides[i].RevokedBy = revokedBy
+			m.overrides[i].RevokeReason = reason
+		}
+	}
+	return nil
+}
+
+func TestManualOverrideService(t *testing.T) {
+	merchantID := uuid.New()
+	newOverride := func() *ManualOverride {
+		return &ManualOverride{
+			MerchantID:        merchantID,
+			HoldPeriod:        HoldPeriod45Days,
+			ReservePercentage: 20,
+			Justification:     "Open fraud investigation",
+			CreatedBy:         "analyst-1",
+			ExpiresAt:         time.Now().Add(7 * 24 * time.Hour),
+		}
+	}
+
+	t.Run("rejects invalid overrides", func(t *testing.T) {
+		service := NewManualOverrideService(&mockManualOverrideStore{})
+
+		invalid := newOverride()
+		invalid.Justification = "  "
+		invalid.HoldPeriod = "90_DAYS"
+		invalid.ExpiresAt = time.Now().Add(-time.Hour)
+
+		_, err := service.Create(context.Background(), invalid)
+		if !errors.Is(err, ErrInvalidManualOverride) {
+			t.Fatalf("expected ErrInvalidManualOverride, got %v", err)
+		}
+		for _, want := range []string{"justification", "hold_period", "expires_at"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to mention %s, got %v", want, err)
+			}
+		}
+	})
+
+	t.Run("allows one active override per merchant", func(t *testing.T) {
+		service := NewManualOverrideService(&mockManualOverrideStore{})
+
+		first, err := service.Create(context.Background(), newOverride())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := service.Create(context.Background(), newOverride()); !errors.Is(err, ErrActiveManualOverride) {
+			t.Fatalf("expected ErrActiveManualOverride, got %v", err)
+		}
+
+		revoked, err := service.Revoke(context.Background(), merchantID, first.ID, "analyst-2", "Investigation closed")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if revoked.RevokedAt == nil || revoked.RevokedBy != "analyst-2" {
+			t.Errorf("expected revocation to be recorded, got %+v", revoked)
+		}
+		if _, err := service.Revoke(context.Background(), merchantID, first.ID, "analyst-2", ""); !errors.Is(err, ErrManualOverrideRevoked) {
+			t.Errorf("expected ErrManualOverrideRevoked, got %v", err)
+		}
+
+		if _, err := service.Create(context.Background(), newOverride()); err != nil {
+			t.Errorf("expected new override after revocation, got %v", err)
+		}
+	})
+
+	t.Run("concurrent create", func(t *testing.T) {
+		store := &mockManualOverrideStore{}
+		service := NewManualOverrideService(store)
+
+		if _, err := service.Create(context.Background(), newOverride()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		store.staleReads = true
+		if _, err := service.Create(context.Background(), newOverride()); !errors.Is(err, ErrActiveManualOverride) {
+			t.Errorf("expected the store to reject the second override, got %v", err)
+		}
+		if len(store.overrides) != 1 {
+			t.Errorf("expected 1 override, got %d", len(store.overrides))
+		}
+	})
+
+	t.Run("revoke checks the merchant", func(t *testing.T) {
+		service := NewManualOverrideService(&mockManualOverrideStore{})
+
+		created, err := service.Create(context.Background(), newOverride())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := service.Revoke(context.Background(), uuid.New(), created.ID, "analyst-2", ""); !errors.Is(err, ErrManualOverrideMissing) {
+			t.Errorf("expected ErrManualOverrideMissing, got %v", err)
+		}
+	})
+}
+
+func TestEvaluateMerchantManualOverride(t *testing.T) {
+	// Scores 37, MEDIUM_LOW with a 7 day hold and no reserve.
+	m := &merchant.Merchant{
+		ID:                 uuid.New(),
+		Industry:           "RETAIL",
+		AccountAgeDays:     400,
+		ChargebackRate:     decimal.NewFromFloat(1.2),
+		VelocityMultiplier: decimal.NewFromFloat(1.0),
+		RefundRate:         decimal.NewFromFloat(1.0),
+		KYCVerified:        true,
+		KYCLevel:           "PARTIAL",
+	}
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return m, nil
+		},
+	}
+	decisionStore := &mockDecisionRepository{
+		getLatestByMerchant: func(ctx context.Context, merchantID uuid.UUID) (*RiskDecision, error) {
+			return &RiskDecision{RiskScore: 37, PayoutHoldPeriod: HoldPeriod7Days}, nil
+		},
+	}
+	overrides := &mockManualOverrideStore{overrides: []ManualOverride{
+		{
+			ID:                uuid.New(),
+			MerchantID:        m.ID,
+			HoldPeriod:        HoldPeriod45Days,
+			ReservePercentage: 20,
+			Justification:     "Open fraud investigation",
+			CreatedBy:         "analyst-1",
+			ExpiresAt:         time.Now().Add(24 * time.Hour),
+		},
+	}}
+
+	service := NewService(merchantStore, decisionStore, WithManualOverrides(overrides))
+
+	decision, err := service.EvaluateMerchant(context.Background(), m.ID, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.RiskScore != 37 || decision.RiskLevel != RiskLevelMediumLow {
+		t.Errorf("expected computed score 37 MEDIUM_LOW, got %d %s", decision.RiskScore, decision.RiskLevel)
+	}
+	if decision.PayoutHoldPeriod != HoldPeriod45Days || decision.RollingReservePercentage != 20 {
+		t.Errorf("expected override policy 45_DAYS/20%%, got %s/%d",
+			decision.PayoutHoldPeriod, decision.RollingReservePercentage)
+	}
+	applied := decision.Reasoning.ManualOverride
+	if applied == nil {
+		t.Fatal("expected manual override on reasoning")
+	}
+	if applied.ComputedHoldPeriod != HoldPeriod7Days || applied.ComputedReservePercentage != 0 {
+		t.Errorf("expected computed policy 7_DAYS/0%%, got %s/%d",
+			applied.ComputedHoldPeriod, applied.ComputedReservePercentage)
+	}
+
+	profile, err := service.GetMerchantProfile(context.Background(), m.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.ManualOverride == nil || profile.CurrentPolicy.PayoutHoldPeriod != "45_DAYS" ||
+		profile.CurrentPolicy.RollingReservePercentage != 20 || profile.CurrentPolicy.RiskScore != 37 {
+		t.Errorf("expected profile to show the override policy, got %+v", profile.CurrentPolicy)
+	}
+
+	unevaluated := NewService(merchantStore, &mockDecisionRepository{
+		getLatestByMerchant: func(ctx context.Context, merchantID uuid.UUID) (*RiskDecision, error) {
+			return nil, nil
+		},
+	}, WithManualOverrides(overrides))
+	profile, err = unevaluated.GetMerchantProfile(context.Background(), m.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.CurrentPolicy == nil || profile.CurrentPolicy.PayoutHoldPeriod != "45_DAYS" ||
+		profile.CurrentPolicy.RollingReservePercentage != 20 || profile.CurrentPolicy.Status != "MANUAL_OVERRIDE" {
+		t.Errorf("expected the override policy for a merchant never evaluated, got %+v", profile.CurrentPolicy)
+	}
+
+	overrides.overrides[0].ExpiresAt = time.Now().Add(-time.Minute)
+	decision, err = service.EvaluateMerchant(context.Background(), m.ID, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.PayoutHoldPeriod != HoldPeriod7Days || decision.Reasoning.ManualOverride != nil {
+		t.Errorf("expected expired override to be ignored, got %s", decision.PayoutHoldPeriod)
+	}
+}
+
+func TestEvaluateMerchantManualOverrideReviewAndApproval(t *testing.T) {
+	m := criticalMerchant()
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return m, nil
+		},
+	}
+	overrides := &mockManualOverrideStore{overrides: []ManualOverride{{
+		ID:                uuid.New(),
+		MerchantID:        m.ID,
+		HoldPeriod:        HoldPeriod14Days,
+		ReservePercentage: 10,
+		Justification:     "Chargebacks under dispute with the acquirer",
+		CreatedBy:         "analyst-1",
+		ExpiresAt:         time.Now().Add(24 * time.Hour),
+	}}}
+	queue := &mockReviewQueue{}
+	service := NewService(merchantStore, &mockDecisionRepository{}, WithManualOverrides(overrides),
+		WithApproval(testApprovalPolicy, &mockApprovalRepository{}), WithReviewQueue(queue))
+
+	decision, err := service.EvaluateMerchant(context.Background(), m.ID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.RiskLevel != RiskLevelCritical || decision.PayoutHoldPeriod != HoldPeriod14Days {
+		t.Fatalf("expected CRITICAL pinned to 14_DAYS, got %s %s", decision.RiskLevel, decision.PayoutHoldPeriod)
+	}
+	if decision.Status != DecisionEffective || decision.Reasoning.PendingApproval != nil {
+		t.Errorf("expected the pinned policy to take effect without approval, got %s", decision.Status)
+	}
+	if len(queue.opened) != 1 || queue.opened[0].ID != decision.ID {
+		t.Errorf("expected a review case for the pinned CRITICAL decision, got %d", len(queue.opened))
+	}
+}
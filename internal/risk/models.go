@@ -32,22 +32,70 @@ type RiskDecision struct {
 	ID                       uuid.UUID        `json:"decision_id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	MerchantID               uuid.UUID        `json:"merchant_id" gorm:"type:uuid;not null"`
 	BatchID                  *uuid.UUID       `json:"batch_id,omitempty" gorm:"type:uuid"`
+	TriggerSnapshotID        *uuid.UUID       `json:"trigger_snapshot_id,omitempty" gorm:"type:uuid"`
 	RiskScore                int              `json:"risk_score" gorm:"not null"`
 	RiskLevel                RiskLevel        `json:"risk_level" gorm:"not null"`
 	PayoutHoldPeriod         HoldPeriod       `json:"payout_hold_period" gorm:"not null"`
 	RollingReservePercentage int              `json:"rolling_reserve_percentage" gorm:"not null"`
 	Reasoning                Reasoning        `json:"reasoning" gorm:"type:jsonb;not null"`
+	Inputs                   *DecisionInputs  `json:"inputs,omitempty" gorm:"type:jsonb"`
+	ModelVersion             string           `json:"model_version" gorm:"not null"`
+	PolicyVersion            string           `json:"policy_version" gorm:"not null"`
 	EvaluatedAt              time.Time        `json:"evaluated_at" gorm:"not null;default:now()"`
 	Simulation               bool             `json:"simulation" gorm:"not null;default:false"`
+	Status                   DecisionStatus   `json:"status" gorm:"not null;default:'EFFECTIVE'"`
+	ReviewedBy               string           `json:"reviewed_by,omitempty" gorm:"not null;default:''"`
+	ReviewedAt               *time.Time       `json:"reviewed_at,omitempty"`
+	ReviewNote               string           `json:"review_note,omitempty" gorm:"not null;default:''"`
 }
 
 func (RiskDecision) TableName() string {
 	return "risk_decisions"
 }
 
+// ModelVersionSummary describes a scoring model version and how many
+// persisted decisions it produced.
+type ModelVersionSummary struct {
+	Version       string    `json:"version"`
+	DecisionCount int64     `json:"decision_count"`
+	FirstSeenAt   time.Time `json:"first_seen_at"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
+	Active        bool      `json:"active"`
+}
+
 type Reasoning struct {
-	PrimaryFactors     []FactorExplanation `json:"primary_factors"`
-	PolicyExplanation  string              `json:"policy_explanation"`
+	PrimaryFactors     []FactorExplanation    `json:"primary_factors"`
+	PolicyExplanation  string                 `json:"policy_explanation"`
+	PolicyMessages     []Message              `json:"policy_messages,omitempty"`
+	PolicyOverride     *AppliedPolicyOverride `json:"policy_override,omitempty"`
+	Hysteresis         *HysteresisHold        `json:"hysteresis,omitempty"`
+	ManualOverride     *AppliedManualOverride `json:"manual_override,omitempty"`
+	PendingApproval    *PendingApproval       `json:"pending_approval,omitempty"`
+}
+
+// AppliedPolicyOverride records which market override shaped the decision's
+// tier table.
+type AppliedPolicyOverride struct {
+	ID                  uuid.UUID          `json:"id"`
+	Name                string             `json:"name"`
+	Country             string             `json:"country,omitempty"`
+	Industry            string             `json:"industry,omitempty"`
+	Mode                PolicyOverrideMode `json:"mode"`
+	HoldAdjustmentSteps int                `json:"hold_adjustment_steps,omitempty"`
+	ReserveAdjustment   int                `json:"reserve_adjustment,omitempty"`
+}
+
+// AppliedManualOverride records an analyst override that replaced the
+// computed hold period and reserve, alongside the values the policy computed.
+type AppliedManualOverride struct {
+	ID                        uuid.UUID  `json:"id"`
+	HoldPeriod                HoldPeriod `json:"hold_period"`
+	ReservePercentage         int        `json:"reserve_percentage"`
+	Justification             string     `json:"justification"`
+	CreatedBy                 string     `json:"created_by"`
+	ExpiresAt                 time.Time  `json:"expires_at"`
+	ComputedHoldPeriod        HoldPeriod `json:"computed_hold_period"`
+	ComputedReservePercentage int        `json:"computed_reserve_percentage"`
 }
 
 func (r *Reasoning) Scan(value interface{}) error {
@@ -62,20 +110,115 @@ func (r Reasoning) Value() (driver.Value, error) {
 	return json.Marshal(r)
 }
 
+// FactorExplanation describes one factor's contribution to a decision.
+// ReasonCode is the stable identifier to key on; Contribution is display
+// text, stored in English and rendered from Messages in the caller's
+// language when returned.
 type FactorExplanation struct {
-	Factor       string `json:"factor"`
-	Score        int    `json:"score"`
-	Contribution string `json:"contribution"`
-	Impact       string `json:"impact"`
+	Factor       string        `json:"factor"`
+	Score        int           `json:"score"`
+	Contribution string        `json:"contribution"`
+	Messages     []Message     `json:"messages,omitempty"`
+	Impact       string        `json:"impact"`
+	ReasonCode   string        `json:"reason_code,omitempty"`
+	Adjustment   *RateEstimate `json:"adjustment,omitempty"`
 }
 
 type PolicyTier struct {
-	MinScore                 int
-	MaxScore                 int
-	RiskLevel                RiskLevel
-	HoldPeriod               HoldPeriod
-	ReservePercentage        int
-	Label                    string
+	MinScore                 int        `json:"min_score"`
+	MaxScore                 int        `json:"max_score"`
+	RiskLevel                RiskLevel  `json:"risk_level"`
+	HoldPeriod               HoldPeriod `json:"hold_period"`
+	ReservePercentage        int        `json:"reserve_percentage"`
+	Label                    string     `json:"label"`
+}
+
+type PolicyTiers []PolicyTier
+
+func (pt *PolicyTiers) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+	return json.Unmarshal(bytes, pt)
+}
+
+func (pt PolicyTiers) Value() (driver.Value, error) {
+	return json.Marshal(pt)
+}
+
+type TierTableStatus string
+
+const (
+	TierTableStatusDraft  TierTableStatus = "DRAFT"
+	TierTableStatusActive TierTableStatus = "ACTIVE"
+)
+
+// PolicyTierTable is a versioned set of policy tiers. Once activated it
+// applies to evaluations between EffectiveFrom and EffectiveUntil; when
+// several active tables overlap, the one with the latest EffectiveFrom wins.
+type PolicyTierTable struct {
+	ID             uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Version        string          `json:"version" gorm:"not null;uniqueIndex"`
+	Status         TierTableStatus `json:"status" gorm:"not null;default:'DRAFT'"`
+	Tiers          PolicyTiers     `json:"tiers" gorm:"type:jsonb;not null"`
+	EffectiveFrom  *time.Time      `json:"effective_from,omitempty"`
+	EffectiveUntil *time.Time      `json:"effective_until,omitempty"`
+	ActivatedAt    *time.Time      `json:"activated_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (PolicyTierTable) TableName() string {
+	return "policy_tier_tables"
+}
+
+type PolicyOverrideMode string
+
+const (
+	PolicyOverrideModeReplace PolicyOverrideMode = "REPLACE"
+	PolicyOverrideModeAdjust  PolicyOverrideMode = "ADJUST"
+)
+
+// PolicyOverride tailors payout policy for merchants in a given country
+// and/or industry. An empty Country or Industry matches any value.
+type PolicyOverride struct {
+	ID                  uuid.UUID          `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name                string             `json:"name" gorm:"not null"`
+	Description         string             `json:"description" gorm:"not null;default:''"`
+	Country             string             `json:"country" gorm:"not null;default:''"`
+	Industry            string             `json:"industry" gorm:"not null;default:''"`
+	Mode                PolicyOverrideMode `json:"mode" gorm:"not null"`
+	Tiers               PolicyTiers        `json:"tiers,omitempty" gorm:"type:jsonb"`
+	HoldAdjustmentSteps int                `json:"hold_adjustment_steps" gorm:"not null;default:0"`
+	ReserveAdjustment   int                `json:"reserve_adjustment" gorm:"not null;default:0"`
+	Active              bool               `json:"active" gorm:"not null;default:true"`
+	CreatedAt           time.Time          `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt           time.Time          `json:"updated_at" gorm:"not null;default:now()"`
+}
+
+func (PolicyOverride) TableName() string {
+	return "policy_overrides"
+}
+
+// ManualOverride pins one merchant to a hold period and reserve chosen by a
+// risk analyst, regardless of score, until it expires or is revoked. Rows are
+// never deleted so the table doubles as the audit trail.
+type ManualOverride struct {
+	ID                uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MerchantID        uuid.UUID  `json:"merchant_id" gorm:"type:uuid;not null"`
+	HoldPeriod        HoldPeriod `json:"hold_period" gorm:"not null"`
+	ReservePercentage int        `json:"reserve_percentage" gorm:"not null"`
+	Justification     string     `json:"justification" gorm:"not null"`
+	CreatedBy         string     `json:"created_by" gorm:"not null"`
+	ExpiresAt         time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy         string     `json:"revoked_by,omitempty" gorm:"not null;default:''"`
+	RevokeReason      string     `json:"revoke_reason,omitempty" gorm:"not null;default:''"`
+	CreatedAt         time.Time  `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (ManualOverride) TableName() string {
+	return "manual_overrides"
 }
 
 type FactorScore struct {
@@ -85,6 +228,7 @@ type FactorScore struct {
 	Category       int
 	KYC            int
 	Refund         int
+	Trend          int
 }
 
 type BatchReport struct {
@@ -126,5 +270,6 @@ type HighRiskMerchant struct {
 	RiskScore          int       `json:"risk_score"`
 	RiskLevel          RiskLevel `json:"risk_level"`
 	PrimaryConcerns    []string  `json:"primary_concerns"`
+	ReasonCodes        []string  `json:"reason_codes,omitempty"`
 	RecommendedAction  string    `json:"recommended_action"`
 }
@@ -1,56 +1,91 @@
 package risk
 
+import (
+	"sort"
+
+	"github.com/yuno-payments/papaya-payout-engine/internal/platform/constants"
+)
+
 type PolicyMapper struct {
-	tiers []PolicyTier
+	version string
+	tiers   []PolicyTier
 }
 
 func NewPolicyMapper() *PolicyMapper {
+	return NewPolicyMapperFromTiers(constants.DefaultPolicyVersion, DefaultPolicyTiers())
+}
+
+// NewPolicyMapperFromTiers builds a mapper over an arbitrary tier table, such
+// as one activated through the policy admin endpoints. Tiers are expected to
+// have passed ValidateTiers.
+func NewPolicyMapperFromTiers(version string, tiers []PolicyTier) *PolicyMapper {
+	sorted := make([]PolicyTier, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinScore < sorted[j].MinScore
+	})
+
 	return &PolicyMapper{
-		tiers: []PolicyTier{
-			{
-				MinScore:          0,
-				MaxScore:          20,
-				RiskLevel:         RiskLevelLow,
-				HoldPeriod:        HoldPeriodImmediate,
-				ReservePercentage: 0,
-				Label:             "Low Risk - Trusted Merchant",
-			},
-			{
-				MinScore:          21,
-				MaxScore:          40,
-				RiskLevel:         RiskLevelMediumLow,
-				HoldPeriod:        HoldPeriod7Days,
-				ReservePercentage: 0,
-				Label:             "Medium-Low Risk - Standard Processing",
-			},
-			{
-				MinScore:          41,
-				MaxScore:          60,
-				RiskLevel:         RiskLevelMedium,
-				HoldPeriod:        HoldPeriod14Days,
-				ReservePercentage: 10,
-				Label:             "Medium Risk - Enhanced Monitoring",
-			},
-			{
-				MinScore:          61,
-				MaxScore:          80,
-				RiskLevel:         RiskLevelHigh,
-				HoldPeriod:        HoldPeriod45Days,
-				ReservePercentage: 20,
-				Label:             "High Risk - Requires Review",
-			},
-			{
-				MinScore:          81,
-				MaxScore:          100,
-				RiskLevel:         RiskLevelCritical,
-				HoldPeriod:        HoldPeriod45Days,
-				ReservePercentage: 20,
-				Label:             "Critical Risk - Manual Approval Required",
-			},
+		version: version,
+		tiers:   sorted,
+	}
+}
+
+// DefaultPolicyTiers returns the built-in tier table used when no table is
+// active in the database.
+func DefaultPolicyTiers() []PolicyTier {
+	return []PolicyTier{
+		{
+			MinScore:          0,
+			MaxScore:          20,
+			RiskLevel:         RiskLevelLow,
+			HoldPeriod:        HoldPeriodImmediate,
+			ReservePercentage: 0,
+			Label:             "Low Risk - Trusted Merchant",
+		},
+		{
+			MinScore:          21,
+			MaxScore:          40,
+			RiskLevel:         RiskLevelMediumLow,
+			HoldPeriod:        HoldPeriod7Days,
+			ReservePercentage: 0,
+			Label:             "Medium-Low Risk - Standard Processing",
+		},
+		{
+			MinScore:          41,
+			MaxScore:          60,
+			RiskLevel:         RiskLevelMedium,
+			HoldPeriod:        HoldPeriod14Days,
+			ReservePercentage: 10,
+			Label:             "Medium Risk - Enhanced Monitoring",
+		},
+		{
+			MinScore:          61,
+			MaxScore:          80,
+			RiskLevel:         RiskLevelHigh,
+			HoldPeriod:        HoldPeriod45Days,
+			ReservePercentage: 20,
+			Label:             "High Risk - Requires Review",
+		},
+		{
+			MinScore:          81,
+			MaxScore:          100,
+			RiskLevel:         RiskLevelCritical,
+			HoldPeriod:        HoldPeriod45Days,
+			ReservePercentage: 20,
+			Label:             "Critical Risk - Manual Approval Required",
 		},
 	}
 }
 
+func (p *PolicyMapper) Version() string {
+	return p.version
+}
+
+func (p *PolicyMapper) Tiers() []PolicyTier {
+	return p.tiers
+}
+
 func (p *PolicyMapper) DeterminePolicyTier(score int) PolicyTier {
 	for _, tier := range p.tiers {
 		if score >= tier.MinScore && score <= tier.MaxScore {
@@ -67,3 +102,14 @@ func (p *PolicyMapper) GetHoldPeriod(score int) HoldPeriod {
 func (p *PolicyMapper) GetReservePercentage(score int) int {
 	return p.DeterminePolicyTier(score).ReservePercentage
 }
+
+// TierAtLeast returns the lowest-scoring tier whose risk level is at least
+// level. Hard stops use it to force a tier without changing the score.
+func (p *PolicyMapper) TierAtLeast(level RiskLevel) (PolicyTier, bool) {
+	for _, tier := range p.tiers {
+		if riskLevelRank[tier.RiskLevel] >= riskLevelRank[level] {
+			return tier, true
+		}
+	}
+	return PolicyTier{}, false
+}
@@ -0,0 +1,208 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidPolicyOverride = errors.New("invalid policy override")
+
+// holdPeriodLadder orders hold periods from least to most restrictive so
+// overrides can shift a tier's hold up or down.
+var holdPeriodLadder = []HoldPeriod{
+	HoldPeriodImmediate,
+	HoldPeriod7Days,
+	HoldPeriod14Days,
+	HoldPeriod45Days,
+}
+
+// Specificity ranks how closely an override targets a merchant: an override
+// keyed on both country and industry beats one keyed on either alone.
+func (o PolicyOverride) Specificity() int {
+	specificity := 0
+	if o.Country != "" {
+		specificity++
+	}
+	if o.Industry != "" {
+		specificity++
+	}
+	return specificity
+}
+
+func (o PolicyOverride) Matches(country, industry string) bool {
+	if !o.Active {
+		return false
+	}
+	if o.Country != "" && !strings.EqualFold(o.Country, country) {
+		return false
+	}
+	if o.Industry != "" && !strings.EqualFold(o.Industry, industry) {
+		return false
+	}
+	return o.Country != "" || o.Industry != ""
+}
+
+// Apply returns a mapper with the override's changes layered on top of base.
+// REPLACE swaps the whole tier table; ADJUST shifts every tier's hold period
+// along the ladder and adds the reserve adjustment, clamped to [0, 100].
+func (o PolicyOverride) Apply(base *PolicyMapper) *PolicyMapper {
+	if o.Mode == PolicyOverrideModeReplace {
+		return NewPolicyMapperFromTiers(base.Version(), o.Tiers)
+	}
+
+	tiers := make([]PolicyTier, len(base.Tiers()))
+	for i, tier := range base.Tiers() {
+		tier.HoldPeriod = shiftHoldPeriod(tier.HoldPeriod, o.HoldAdjustmentSteps)
+		tier.ReservePercentage = clampPercentage(tier.ReservePercentage + o.ReserveAdjustment)
+		tiers[i] = tier
+	}
+
+	return NewPolicyMapperFromTiers(base.Version(), tiers)
+}
+
+func (o PolicyOverride) Validate() error {
+	var problems []string
+
+	if o.Name == "" {
+		problems = append(problems, "name is required")
+	}
+	if o.Country == "" && o.Industry == "" {
+		problems = append(problems, "country or industry is required")
+	}
+	if o.Country != "" && len(o.Country) != 2 {
+		problems = append(problems, "country must be an ISO 3166-1 alpha-2 code")
+	}
+
+	switch o.Mode {
+	case PolicyOverrideModeReplace:
+		if err := ValidateTiers(o.Tiers); err != nil {
+			problems = append(problems, err.Error())
+		}
+	case PolicyOverrideModeAdjust:
+		if o.HoldAdjustmentSteps == 0 && o.ReserveAdjustment == 0 {
+			problems = append(problems, "adjust overrides must change hold_adjustment_steps or reserve_adjustment")
+		}
+		if o.HoldAdjustmentSteps < -len(holdPeriodLadder)+1 || o.HoldAdjustmentSteps > len(holdPeriodLadder)-1 {
+			problems = append(problems, fmt.Sprintf("hold_adjustment_steps must be between %d and %d",
+				-len(holdPeriodLadder)+1, len(holdPeriodLadder)-1))
+		}
+		if o.ReserveAdjustment < -100 || o.ReserveAdjustment > 100 {
+			problems = append(problems, "reserve_adjustment must be between -100 and 100")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("unknown mode %q", o.Mode))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidPolicyOverride, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// SelectPolicyOverride picks the most specific active override matching the
+// merchant's country and industry. Among equally specific overrides the most
+// recently created one wins.
+func SelectPolicyOverride(overrides []PolicyOverride, country, industry string) *PolicyOverride {
+	var selected *PolicyOverride
+	for i := range overrides {
+		o := &overrides[i]
+		if !o.Matches(country, industry) {
+			continue
+		}
+		if selected == nil ||
+			o.Specificity() > selected.Specificity() ||
+			(o.Specificity() == selected.Specificity() && o.CreatedAt.After(selected.CreatedAt)) {
+			selected = o
+		}
+	}
+	return selected
+}
+
+func shiftHoldPeriod(hold HoldPeriod, steps int) HoldPeriod {
+	for i, h := range holdPeriodLadder {
+		if h != hold {
+			continue
+		}
+		idx := i + steps
+		if idx < 0 {
+			idx = 0
+		}
+		if idx >= len(holdPeriodLadder) {
+			idx = len(holdPeriodLadder) - 1
+		}
+		return holdPeriodLadder[idx]
+	}
+	return hold
+}
+
+func clampPercentage(p int) int {
+	if p < 0 {
+		return 0
+	}
+	if p > 100 {
+		return 100
+	}
+	return p
+}
+
+type PolicyOverrideRepository interface {
+	ListActive(ctx context.Context) ([]PolicyOverride, error)
+	// Get returns an override whether or not it is still active.
+	Get(ctx context.Context, id uuid.UUID) (*PolicyOverride, error)
+}
+
+type PolicyOverrideStore interface {
+	PolicyOverrideRepository
+	Create(ctx context.Context, override *PolicyOverride) error
+	List(ctx context.Context) ([]PolicyOverride, error)
+	Deactivate(ctx context.Context, id uuid.UUID) error
+}
+
+// PolicyOverrideService manages country- and industry-specific adjustments to
+// the tier table.
+type PolicyOverrideService struct {
+	store PolicyOverrideStore
+}
+
+func NewPolicyOverrideService(store PolicyOverrideStore) *PolicyOverrideService {
+	return &PolicyOverrideService{store: store}
+}
+
+func (s *PolicyOverrideService) Create(ctx context.Context, override *PolicyOverride) (*PolicyOverride, error) {
+	override.Country = strings.ToUpper(override.Country)
+	override.Industry = strings.ToUpper(override.Industry)
+	override.Active = true
+
+	if err := override.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Create(ctx, override); err != nil {
+		return nil, fmt.Errorf("failed to create policy override: %w", err)
+	}
+
+	log.Printf("[INFO] Created policy override %s (country=%q, industry=%q, mode=%s)",
+		override.Name, override.Country, override.Industry, override.Mode)
+	return override, nil
+}
+
+func (s *PolicyOverrideService) Get(ctx context.Context, id uuid.UUID) (*PolicyOverride, error) {
+	return s.store.Get(ctx, id)
+}
+
+func (s *PolicyOverrideService) List(ctx context.Context) ([]PolicyOverride, error) {
+	return s.store.List(ctx)
+}
+
+func (s *PolicyOverrideService) Deactivate(ctx context.Context, id uuid.UUID) error {
+	if err := s.store.Deactivate(ctx, id); err != nil {
+		return fmt.Errorf("failed to deactivate policy override: %w", err)
+	}
+	log.Printf("[INFO] Deactivated policy override %s", id)
+	return nil
+}
@@ -0,0 +1,226 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+type mockPolicyOverrideRepository struct {
+	listActive func(ctx context.Context) ([]PolicyOverride, error)
+}
+
+func (m *mockPolicyOverrideRepository) ListActive(ctx context.Context) ([]PolicyOverride, error) {
+	if m.listActive != nil {
+		return m.listActive(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockPolicyOverrideRepository) Get(ctx context.Context, id uuid.UUID) (*PolicyOverride, error) {
+	return nil, errors.New("policy override not found")
+}
+
+func TestSelectPolicyOverride(t *testing.T) {
+	now := time.Now()
+	overrides := []PolicyOverride{
+		{Name: "brazil", Country: "BR", Mode: PolicyOverrideModeAdjust, Active: true, CreatedAt: now},
+		{Name: "brazil-travel", Country: "BR", Industry: "TRAVEL", Mode: PolicyOverrideModeAdjust, Active: true, CreatedAt: now},
+		{Name: "travel", Industry: "TRAVEL", Mode: PolicyOverrideModeAdjust, Active: true, CreatedAt: now},
+		{Name: "mexico-old", Country: "MX", Mode: PolicyOverrideModeAdjust, Active: true, CreatedAt: now.Add(-time.Hour)},
+		{Name: "mexico-new", Country: "MX", Mode: PolicyOverrideModeAdjust, Active: true, CreatedAt: now},
+		{Name: "argentina-inactive", Country: "AR", Mode: PolicyOverrideModeAdjust, Active: false, CreatedAt: now},
+	}
+
+	tests := []struct {
+		name     string
+		country  string
+		industry string
+		want     string
+	}{
+		{"country and industry beats country", "BR", "TRAVEL", "brazil-travel"},
+		{"country only", "BR", "RETAIL", "brazil"},
+		{"industry only", "CO", "TRAVEL", "travel"},
+		{"latest among equals", "MX", "RETAIL", "mexico-new"},
+		{"case insensitive country", "br", "RETAIL", "brazil"},
+		{"inactive ignored", "AR", "RETAIL", ""},
+		{"no match", "US", "RETAIL", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SelectPolicyOverride(overrides, tt.country, tt.industry)
+
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("expected no override, got %s", got.Name)
+				}
+				return
+			}
+			if got == nil || got.Name != tt.want {
+				t.Errorf("expected override %s, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPolicyOverrideApply(t *testing.T) {
+	base := NewPolicyMapper()
+
+	t.Run("adjust shifts hold and reserve", func(t *testing.T) {
+		override := PolicyOverride{
+			Mode:                PolicyOverrideModeAdjust,
+			HoldAdjustmentSteps: 1,
+			ReserveAdjustment:   5,
+		}
+
+		mapper := override.Apply(base)
+
+		low := mapper.DeterminePolicyTier(10)
+		if low.HoldPeriod != HoldPeriod7Days || low.ReservePercentage != 5 {
+			t.Errorf("LOW tier: got %s/%d%%, want 7_DAYS/5%%", low.HoldPeriod, low.ReservePercentage)
+		}
+		critical := mapper.DeterminePolicyTier(90)
+		if critical.HoldPeriod != HoldPeriod45Days || critical.ReservePercentage != 25 {
+			t.Errorf("CRITICAL tier: got %s/%d%%, want 45_DAYS/25%%", critical.HoldPeriod, critical.ReservePercentage)
+		}
+		if base.DeterminePolicyTier(10).HoldPeriod != HoldPeriodImmediate {
+			t.Error("base mapper must not be modified")
+		}
+	})
+
+	t.Run("adjust clamps reserve", func(t *testing.T) {
+		override := PolicyOverride{Mode: PolicyOverrideModeAdjust, ReserveAdjustment: -50}
+
+		if got := override.Apply(base).DeterminePolicyTier(90).ReservePercentage; got != 0 {
+			t.Errorf("expected reserve clamped to 0, got %d", got)
+		}
+	})
+
+	t.Run("replace swaps tiers", func(t *testing.T) {
+		tiers := DefaultPolicyTiers()
+		tiers[0].HoldPeriod = HoldPeriod14Days
+		override := PolicyOverride{Mode: PolicyOverrideModeReplace, Tiers: tiers}
+
+		mapper := override.Apply(base)
+
+		if got := mapper.DeterminePolicyTier(0).HoldPeriod; got != HoldPeriod14Days {
+			t.Errorf("expected 14_DAYS, got %s", got)
+		}
+		if mapper.Version() != base.Version() {
+			t.Errorf("expected base policy version to be kept, got %s", mapper.Version())
+		}
+	})
+}
+
+func TestPolicyOverrideValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		override PolicyOverride
+		wantErr  string
+	}{
+		{
+			name:     "missing scope",
+			override: PolicyOverride{Name: "global", Mode: PolicyOverrideModeAdjust, ReserveAdjustment: 5},
+			wantErr:  "country or industry is required",
+		},
+		{
+			name:     "adjust without changes",
+			override: PolicyOverride{Name: "noop", Country: "BR", Mode: PolicyOverrideModeAdjust},
+			wantErr:  "must change hold_adjustment_steps or reserve_adjustment",
+		},
+		{
+			name:     "replace with invalid tiers",
+			override: PolicyOverride{Name: "broken", Country: "BR", Mode: PolicyOverrideModeReplace},
+			wantErr:  "at least one tier is required",
+		},
+		{
+			name:     "unknown mode",
+			override: PolicyOverride{Name: "odd", Country: "BR", Mode: "MULTIPLY"},
+			wantErr:  `unknown mode "MULTIPLY"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.override.Validate()
+
+			if !errors.Is(err, ErrInvalidPolicyOverride) {
+				t.Fatalf("expected ErrInvalidPolicyOverride, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error to contain %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEvaluateMerchantPolicyOverride(t *testing.T) {
+	merchantID := uuid.New()
+	brazilianMerchant := &merchant.Merchant{
+		ID:                 merchantID,
+		Industry:           "RETAIL",
+		Country:            "BR",
+		AccountAgeDays:     800,
+		ChargebackRate:     decimal.NewFromFloat(0.3),
+		RefundRate:         decimal.NewFromFloat(2.0),
+		VelocityMultiplier: decimal.NewFromFloat(1.2),
+		KYCVerified:        true,
+		KYCLevel:           "ENHANCED",
+	}
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return brazilianMerchant, nil
+		},
+	}
+
+	t.Run("override applied and recorded", func(t *testing.T) {
+		overrides := &mockPolicyOverrideRepository{
+			listActive: func(ctx context.Context) ([]PolicyOverride, error) {
+				return []PolicyOverride{{
+					ID:                  uuid.New(),
+					Name:                "BR hold uplift",
+					Country:             "BR",
+					Mode:                PolicyOverrideModeAdjust,
+					HoldAdjustmentSteps: 1,
+					Active:              true,
+				}}, nil
+			},
+		}
+
+		service := NewService(merchantStore, &mockDecisionRepository{}, WithPolicyOverrides(overrides))
+		decision, err := service.EvaluateMerchant(context.Background(), merchantID, true)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if decision.PayoutHoldPeriod != HoldPeriod7Days {
+			t.Errorf("expected 7_DAYS hold after override, got %s", decision.PayoutHoldPeriod)
+		}
+		if decision.Reasoning.PolicyOverride == nil || decision.Reasoning.PolicyOverride.Name != "BR hold uplift" {
+			t.Fatalf("expected override recorded in reasoning, got %+v", decision.Reasoning.PolicyOverride)
+		}
+		if !strings.Contains(decision.Reasoning.PolicyExplanation, "BR hold uplift") {
+			t.Errorf("expected policy explanation to mention override, got %q", decision.Reasoning.PolicyExplanation)
+		}
+	})
+
+	t.Run("override lookup failure", func(t *testing.T) {
+		overrides := &mockPolicyOverrideRepository{
+			listActive: func(ctx context.Context) ([]PolicyOverride, error) {
+				return nil, errors.New("database error")
+			},
+		}
+
+		service := NewService(merchantStore, &mockDecisionRepository{}, WithPolicyOverrides(overrides))
+		if _, err := service.EvaluateMerchant(context.Background(), merchantID, false); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,248 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+const (
+	portfolioPageSize      = 500
+	portfolioPolicyVersion = "portfolio-simulation"
+)
+
+var (
+	ErrInvalidPortfolioScenario = errors.New("invalid portfolio scenario")
+	ErrPortfolioUnavailable     = errors.New("portfolio simulation is not configured")
+)
+
+// PortfolioRepository pages through the merchants a portfolio simulation
+// covers, in a stable order.
+type PortfolioRepository interface {
+	ListForPortfolio(ctx context.Context, filter PortfolioFilter, limit, offset int) ([]merchant.Merchant, error)
+}
+
+// WithPortfolio enables portfolio-wide simulations over the merchants in
+// merchants.
+func WithPortfolio(merchants PortfolioRepository) Option {
+	return func(s *Service) {
+		s.portfolio = merchants
+	}
+}
+
+// PortfolioFilter narrows a portfolio simulation. Empty fields match every
+// merchant; values within a field are alternatives.
+type PortfolioFilter struct {
+	MerchantIDs []uuid.UUID `json:"merchant_ids,omitempty"`
+	Industries  []string    `json:"industries,omitempty"`
+	Countries   []string    `json:"countries,omitempty"`
+}
+
+// PortfolioScenario is the change a portfolio simulation applies:
+// ScoringThresholds as in SimulateMerchant overrides, Tiers in place of the
+// active tier table, or both.
+type PortfolioScenario struct {
+	Filter            PortfolioFilter    `json:"filter"`
+	ScoringThresholds *ScoringThresholds `json:"scoring_thresholds,omitempty"`
+	Tiers             []PolicyTier       `json:"tiers,omitempty"`
+}
+
+func (p PortfolioScenario) Validate() error {
+	if p.ScoringThresholds.Empty() && p.Tiers == nil {
+		return fmt.Errorf("%w: scoring_thresholds or tiers is required", ErrInvalidPortfolioScenario)
+	}
+	if p.Tiers != nil {
+		if err := ValidateTiers(p.Tiers); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPortfolioScenario, err)
+		}
+	}
+	return nil
+}
+
+// PortfolioSimulation compares the merchants matching a filter under the live
+// configuration (the baseline) and under a scenario.
+type PortfolioSimulation struct {
+	Filter      PortfolioFilter  `json:"filter"`
+	Merchants   int              `json:"merchants"`
+	Baseline    PortfolioSummary `json:"baseline"`
+	Scenario    PortfolioSummary `json:"scenario"`
+	Delta       PortfolioDelta   `json:"delta"`
+	SimulatedAt time.Time        `json:"simulated_at"`
+}
+
+// PortfolioSummary carries the batch evaluation aggregates, with
+// VolumeByTier keyed by hold period, plus the reserve those merchants would
+// hold: 30-day volume times reserve percentage.
+type PortfolioSummary struct {
+	BatchSummary
+	ReserveAmount decimal.Decimal `json:"reserve_amount"`
+}
+
+// PortfolioDelta is the scenario minus the baseline.
+type PortfolioDelta struct {
+	RiskLevelChanges   int                        `json:"risk_level_changes"`
+	HoldPeriodChanges  int                        `json:"hold_period_changes"`
+	ReserveChanges     int                        `json:"reserve_changes"`
+	VolumeByHoldPeriod map[string]decimal.Decimal `json:"volume_by_hold_period"`
+	ReserveAmount      decimal.Decimal            `json:"reserve_amount"`
+}
+
+func newPortfolioSummary() PortfolioSummary {
+	return PortfolioSummary{BatchSummary: BatchSummary{
+		ByHoldPeriod: make(map[string]int),
+		ByReserve:    make(map[string]int),
+		ByRiskLevel:  make(map[string]int),
+		VolumeByTier: make(map[string]float64),
+	}}
+}
+
+func (p *PortfolioSummary) add(tier PolicyTier, volume decimal.Decimal) {
+	p.ByHoldPeriod[string(tier.HoldPeriod)]++
+	p.ByReserve[fmt.Sprintf("%d_PERCENT", tier.ReservePercentage)]++
+	p.ByRiskLevel[string(tier.RiskLevel)]++
+	p.TotalVolume += volume.InexactFloat64()
+	p.VolumeByTier[string(tier.HoldPeriod)] += volume.InexactFloat64()
+	p.ReserveAmount = p.ReserveAmount.Add(reserveAmount(volume, tier.ReservePercentage))
+}
+
+// portfolioConfig scores and tiers merchants for one side of a portfolio
+// simulation.
+type portfolioConfig struct {
+	scorer Scorer
+	rules  *Ruleset
+	policy *PolicyMapper
+}
+
+func (c portfolioConfig) tier(m *merchant.Merchant, history []merchant.MetricSnapshot, override *PolicyOverride, at time.Time) PolicyTier {
+	policy := c.policy
+	if override != nil {
+		policy = override.Apply(policy)
+	}
+	score, _, _ := scoreWithHistory(c.scorer, m, history, at)
+	tier, _ := ApplyHardStops(c.rules.HardStops, m, policy, policy.DeterminePolicyTier(score))
+	return tier
+}
+
+// SimulatePortfolio scores every merchant matching the scenario's filter under
+// the live configuration and under the scenario, and aggregates both. Market
+// overrides, hard stops and trends apply to both sides; hysteresis and
+// analyst overrides apply to neither. Nothing is persisted.
+func (s *Service) SimulatePortfolio(ctx context.Context, scenario PortfolioScenario) (*PortfolioSimulation, error) {
+	if s.portfolio == nil {
+		return nil, ErrPortfolioUnavailable
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	scenario.Filter = normalizePortfolioFilter(scenario.Filter)
+
+	simulatedAt := time.Now()
+	policy, err := s.policyAt(ctx, simulatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve policy tiers: %w", err)
+	}
+	var overrides []PolicyOverride
+	if s.overrides != nil {
+		if overrides, err = s.overrides.ListActive(ctx); err != nil {
+			return nil, fmt.Errorf("failed to list policy overrides: %w", err)
+		}
+	}
+
+	baseline := portfolioConfig{scorer: s.scorer, rules: s.evaluator.Ruleset(), policy: policy}
+	candidate := baseline
+	if !scenario.ScoringThresholds.Empty() {
+		candidate.scorer, candidate.rules, err = s.simulationScorer(&SimulationOverrides{
+			ScoringThresholds: scenario.ScoringThresholds,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if scenario.Tiers != nil {
+		candidate.policy = NewPolicyMapperFromTiers(portfolioPolicyVersion, scenario.Tiers)
+	}
+
+	log.Printf("[INFO] Starting portfolio simulation: %d merchant IDs, %d industries, %d countries",
+		len(scenario.Filter.MerchantIDs), len(scenario.Filter.Industries), len(scenario.Filter.Countries))
+
+	result := &PortfolioSimulation{
+		Filter:      scenario.Filter,
+		Baseline:    newPortfolioSummary(),
+		Scenario:    newPortfolioSummary(),
+		Delta:       PortfolioDelta{VolumeByHoldPeriod: make(map[string]decimal.Decimal)},
+		SimulatedAt: simulatedAt,
+	}
+
+	for offset := 0; ; offset += portfolioPageSize {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("portfolio simulation cancelled: %w", err)
+		}
+
+		merchants, err := s.portfolio.ListForPortfolio(ctx, scenario.Filter, portfolioPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list merchants: %w", err)
+		}
+
+		for i := range merchants {
+			m := &merchants[i]
+			history, err := s.trendHistory(ctx, s.scorer, m.ID, simulatedAt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load metric history for merchant %s: %w", m.ID, err)
+			}
+
+			override := SelectPolicyOverride(overrides, m.Country, m.Industry)
+			before := baseline.tier(m, history, override, simulatedAt)
+			after := candidate.tier(m, history, override, simulatedAt)
+			result.add(before, after, m.TransactionVolume30d)
+		}
+
+		if len(merchants) < portfolioPageSize {
+			break
+		}
+	}
+
+	result.Delta.ReserveAmount = result.Scenario.ReserveAmount.Sub(result.Baseline.ReserveAmount)
+
+	log.Printf("[INFO] Portfolio simulation complete: %d merchants, %d risk level changes, reserve delta %s",
+		result.Merchants, result.Delta.RiskLevelChanges, result.Delta.ReserveAmount.StringFixed(2))
+
+	return result, nil
+}
+
+func (p *PortfolioSimulation) add(before, after PolicyTier, volume decimal.Decimal) {
+	p.Merchants++
+	p.Baseline.add(before, volume)
+	p.Scenario.add(after, volume)
+	delta := p.Delta.VolumeByHoldPeriod
+	delta[string(after.HoldPeriod)] = delta[string(after.HoldPeriod)].Add(volume)
+	delta[string(before.HoldPeriod)] = delta[string(before.HoldPeriod)].Sub(volume)
+	if before.RiskLevel != after.RiskLevel {
+		p.Delta.RiskLevelChanges++
+	}
+	if before.HoldPeriod != after.HoldPeriod {
+		p.Delta.HoldPeriodChanges++
+	}
+	if before.ReservePercentage != after.ReservePercentage {
+		p.Delta.ReserveChanges++
+	}
+}
+
+// normalizePortfolioFilter upper-cases industries and countries to match how
+// merchants store them.
+func normalizePortfolioFilter(f PortfolioFilter) PortfolioFilter {
+	normalized := PortfolioFilter{MerchantIDs: f.MerchantIDs}
+	for _, industry := range f.Industries {
+		normalized.Industries = append(normalized.Industries, strings.ToUpper(strings.TrimSpace(industry)))
+	}
+	for _, country := range f.Countries {
+		normalized.Countries = append(normalized.Countries, strings.ToUpper(strings.TrimSpace(country)))
+	}
+	return normalized
+}
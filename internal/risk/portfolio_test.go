@@ -0,0 +1,151 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+type mockPortfolioRepository struct {
+	merchants []merchant.Merchant
+	filter    PortfolioFilter
+}
+
+func (m *mockPortfolioRepository) ListForPortfolio(ctx context.Context, filter PortfolioFilter, limit, offset int) ([]merchant.Merchant, error) {
+	m.filter = filter
+	if offset >= len(m.merchants) {
+		return nil, nil
+	}
+	return m.merchants[offset:min(offset+limit, len(m.merchants))], nil
+}
+
+func TestSimulatePortfolio(t *testing.T) {
+	portfolio := &mockPortfolioRepository{merchants: []merchant.Merchant{
+		{ID: uuid.New(), Industry: "RETAIL", Country: "BR", AccountAgeDays: 800, KYCVerified: true, KYCLevel: "ENHANCED",
+			TransactionVolume30d: decimal.NewFromInt(100000), ChargebackRate: decimal.NewFromFloat(0.3),
+			VelocityMultiplier: decimal.NewFromFloat(1.0), RefundRate: decimal.NewFromFloat(1.0)},
+		{ID: uuid.New(), Industry: "RETAIL", Country: "BR", AccountAgeDays: 200, KYCVerified: true, KYCLevel: "FULL",
+			TransactionVolume30d: decimal.NewFromInt(50000), ChargebackRate: decimal.NewFromFloat(0.6),
+			VelocityMultiplier: decimal.NewFromFloat(1.2), RefundRate: decimal.NewFromFloat(2.0)},
+		{ID: uuid.New(), Industry: "TRAVEL", Country: "MX", AccountAgeDays: 60, KYCVerified: true, KYCLevel: "BASIC",
+			TransactionVolume30d: decimal.NewFromInt(25000), ChargebackRate: decimal.NewFromFloat(1.0),
+			VelocityMultiplier: decimal.NewFromFloat(2.0), RefundRate: decimal.NewFromFloat(4.0)},
+	}}
+	service := NewService(&mockMerchantRepository{}, &mockDecisionRepository{}, WithPortfolio(portfolio))
+
+	// Narrow LOW and MEDIUM_LOW so the first two merchants (scores 5 and 28)
+	// each move up one tier.
+	tiers := DefaultPolicyTiers()
+	tiers[0].MaxScore = 3
+	tiers[1].MinScore, tiers[1].MaxScore = 4, 25
+	tiers[2].MinScore = 26
+
+	t.Run("tier change", func(t *testing.T) {
+		result, err := service.SimulatePortfolio(context.Background(), PortfolioScenario{
+			Filter: PortfolioFilter{Industries: []string{"retail", "Travel "}},
+			Tiers:  tiers,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := portfolio.filter.Industries; len(got) != 2 || got[0] != "RETAIL" || got[1] != "TRAVEL" {
+			t.Errorf("expected normalized industries, got %v", got)
+		}
+
+		if result.Merchants != 3 || result.Baseline.TotalVolume != 175000 || result.Scenario.TotalVolume != 175000 {
+			t.Fatalf("expected 3 merchants on 175000 volume, got %d (%v, %v)",
+				result.Merchants, result.Baseline.TotalVolume, result.Scenario.TotalVolume)
+		}
+		if result.Baseline.ByRiskLevel["LOW"] != 1 || result.Baseline.ByRiskLevel["MEDIUM_LOW"] != 1 || result.Baseline.ByRiskLevel["HIGH"] != 1 {
+			t.Fatalf("unexpected baseline risk levels %v", result.Baseline.ByRiskLevel)
+		}
+		if result.Scenario.ByRiskLevel["MEDIUM_LOW"] != 1 || result.Scenario.ByRiskLevel["MEDIUM"] != 1 || result.Scenario.ByRiskLevel["HIGH"] != 1 {
+			t.Errorf("unexpected scenario risk levels %v", result.Scenario.ByRiskLevel)
+		}
+		if result.Scenario.ByReserve["10_PERCENT"] != 1 || result.Scenario.ByReserve["0_PERCENT"] != 1 {
+			t.Errorf("unexpected scenario reserves %v", result.Scenario.ByReserve)
+		}
+
+		delta := result.Delta
+		if delta.RiskLevelChanges != 2 || delta.HoldPeriodChanges != 2 || delta.ReserveChanges != 1 {
+			t.Errorf("expected 2 tier, 2 hold and 1 reserve change, got %+v", delta)
+		}
+		if !delta.VolumeByHoldPeriod["IMMEDIATE"].Equal(decimal.NewFromInt(-100000)) ||
+			!delta.VolumeByHoldPeriod["7_DAYS"].Equal(decimal.NewFromInt(50000)) ||
+			!delta.VolumeByHoldPeriod["14_DAYS"].Equal(decimal.NewFromInt(50000)) {
+			t.Errorf("unexpected volume delta by hold period %v", delta.VolumeByHoldPeriod)
+		}
+		if !result.Baseline.ReserveAmount.Equal(decimal.NewFromInt(5000)) || !result.Scenario.ReserveAmount.Equal(decimal.NewFromInt(10000)) ||
+			!delta.ReserveAmount.Equal(decimal.NewFromInt(5000)) {
+			t.Errorf("expected reserve 5000 -> 10000, got %v -> %v (delta %v)",
+				result.Baseline.ReserveAmount, result.Scenario.ReserveAmount, delta.ReserveAmount)
+		}
+	})
+
+	t.Run("scoring thresholds", func(t *testing.T) {
+		result, err := service.SimulatePortfolio(context.Background(), PortfolioScenario{
+			ScoringThresholds: &ScoringThresholds{ChargebackAcceptable: ptr(0.55), ChargebackCritical: ptr(0.6)},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		delta := result.Delta
+		if delta.HoldPeriodChanges != 1 || !delta.VolumeByHoldPeriod["14_DAYS"].Equal(decimal.NewFromInt(50000)) ||
+			!delta.ReserveAmount.Equal(decimal.NewFromInt(5000)) {
+			t.Errorf("expected stricter chargeback bands to move the 0.6%% merchant to a 14-day hold, got %+v", delta)
+		}
+	})
+
+	t.Run("reserve amounts keep cents", func(t *testing.T) {
+		portfolio := &mockPortfolioRepository{merchants: []merchant.Merchant{
+			{ID: uuid.New(), Industry: "RETAIL", Country: "BR", AccountAgeDays: 200, KYCVerified: true, KYCLevel: "FULL",
+				TransactionVolume30d: decimal.RequireFromString("0.30"), ChargebackRate: decimal.NewFromFloat(0.6),
+				VelocityMultiplier: decimal.NewFromFloat(1.2), RefundRate: decimal.NewFromFloat(2.0)},
+		}}
+		service := NewService(&mockMerchantRepository{}, &mockDecisionRepository{}, WithPortfolio(portfolio))
+		result, err := service.SimulatePortfolio(context.Background(), PortfolioScenario{Tiers: tiers})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Scenario.ReserveAmount.Equal(decimal.RequireFromString("0.03")) || !result.Delta.ReserveAmount.Equal(decimal.RequireFromString("0.03")) {
+			t.Errorf("expected an exact 0.03 reserve, got %s (delta %s)", result.Scenario.ReserveAmount, result.Delta.ReserveAmount)
+		}
+	})
+
+	t.Run("thresholds without bands", func(t *testing.T) {
+		service := NewService(&mockMerchantRepository{}, &mockDecisionRepository{}, WithPortfolio(portfolio),
+			WithScorer(NewLogisticScorer(&LogisticModel{
+				Version:  "logistic-test",
+				Features: []LogisticFeature{{Name: "kyc_verified", Coefficient: 2}},
+			})))
+		_, err := service.SimulatePortfolio(context.Background(), PortfolioScenario{
+			ScoringThresholds: &ScoringThresholds{VelocityElevated: ptr(3.5)},
+		})
+		var fields OverrideErrors
+		if !errors.As(err, &fields) || fields["scoring_thresholds"] != "not supported by scorer logistic-test" {
+			t.Errorf("expected a scoring_thresholds field error, got %v", err)
+		}
+	})
+
+	t.Run("invalid scenario", func(t *testing.T) {
+		for name, scenario := range map[string]PortfolioScenario{
+			"empty":    {},
+			"tier gap": {Tiers: tiers[1:]},
+		} {
+			if _, err := service.SimulatePortfolio(context.Background(), scenario); !errors.Is(err, ErrInvalidPortfolioScenario) {
+				t.Errorf("%s: expected ErrInvalidPortfolioScenario, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		service := NewService(&mockMerchantRepository{}, &mockDecisionRepository{})
+		if _, err := service.SimulatePortfolio(context.Background(), PortfolioScenario{Tiers: tiers}); !errors.Is(err, ErrPortfolioUnavailable) {
+			t.Errorf("expected ErrPortfolioUnavailable, got %v", err)
+		}
+	})
+}
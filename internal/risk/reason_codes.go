@@ -0,0 +1,140 @@
+package risk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReasonCode is a stable, machine-readable identifier for why a factor
+// scored the way it did. Integrations should key on Code rather than on the
+// localized contribution text, which may change wording at any time.
+type ReasonCode struct {
+	Code        string `json:"code"`
+	Factor      string `json:"factor"`
+	Impact      string `json:"impact"`
+	Description string `json:"description"`
+}
+
+const (
+	reasonCodeHardStopPrefix = "HARD_STOP_"
+	reasonCodeTrendPrefix    = "TREND_"
+)
+
+// bandedReasonCodes names each banded factor and the prefix its band codes
+// share. A band's code is the prefix followed by its upper-cased label, so
+// CB_RATE_CRITICAL is the chargeback band labelled "critical".
+var bandedReasonCodes = []struct {
+	factor string
+	name   string
+	prefix string
+}{
+	{"chargeback", "Chargeback Rate", "CB_RATE"},
+	{"account_age", "Account Age", "ACCOUNT_AGE"},
+	{"velocity", "Transaction Velocity", "VELOCITY"},
+	{"refund", "Refund Rate", "REFUND_RATE"},
+}
+
+// fixedReasonCodes are the codes for the categorical factors, whose grades do
+// not depend on the ruleset.
+var fixedReasonCodes = []ReasonCode{
+	{"CATEGORY_HIGH_RISK", "Business Category", "NEGATIVE", "Industry carries the highest category points"},
+	{"CATEGORY_MEDIUM_RISK", "Business Category", "NEUTRAL", "Industry carries at least two thirds of the category points"},
+	{"CATEGORY_LOW_RISK", "Business Category", "POSITIVE", "Industry carries some category points"},
+	{"CATEGORY_MINIMAL_RISK", "Business Category", "POSITIVE", "Industry carries no category points"},
+	{"KYC_NONE", "KYC Verification", "CRITICAL", "KYC not verified"},
+	{"KYC_PARTIAL", "KYC Verification", "NEGATIVE", "Partial KYC verification"},
+	{"KYC_FULL", "KYC Verification", "NEUTRAL", "Full KYC verification"},
+	{"KYC_ENHANCED", "KYC Verification", "POSITIVE", "Enhanced KYC verification"},
+}
+
+var categoryReasonCodes = map[string]string{
+	"category.high":    "CATEGORY_HIGH_RISK",
+	"category.medium":  "CATEGORY_MEDIUM_RISK",
+	"category.low":     "CATEGORY_LOW_RISK",
+	"category.minimal": "CATEGORY_MINIMAL_RISK",
+}
+
+var kycReasonCodes = map[string]string{
+	"kyc.none":     "KYC_NONE",
+	"kyc.partial":  "KYC_PARTIAL",
+	"kyc.full":     "KYC_FULL",
+	"kyc.enhanced": "KYC_ENHANCED",
+}
+
+// bandReasonCode returns the code for the band with the given label.
+func bandReasonCode(factor, label string) string {
+	for _, b := range bandedReasonCodes {
+		if b.factor == factor {
+			return b.prefix + "_" + strings.ToUpper(label)
+		}
+	}
+	return strings.ToUpper(factor + "_" + label)
+}
+
+// ReasonCodeCatalog lists every reason code a decision scored with rs can
+// carry: one per band of each banded factor, the categorical grades, and one
+// per hard-stop rule and trend signal.
+func ReasonCodeCatalog(rs *Ruleset) []ReasonCode {
+	var codes []ReasonCode
+
+	for _, b := range bandedReasonCodes {
+		f := rs.bandedFactor(b.factor)
+		lower := 0.0
+		for _, band := range f.Bands {
+			impact, known := bandImpacts[b.factor][band.Label]
+			if !known {
+				impact = impactFromPoints(band.Points, f.MaxPoints)
+			}
+
+			var description string
+			if band.Below == nil {
+				description = fmt.Sprintf("%s of %g or more", b.name, lower)
+			} else {
+				description = fmt.Sprintf("%s from %g to under %g", b.name, lower, *band.Below)
+				lower = *band.Below
+			}
+
+			codes = append(codes, ReasonCode{
+				Code:        bandReasonCode(b.factor, band.Label),
+				Factor:      b.name,
+				Impact:      impact,
+				Description: description,
+			})
+		}
+	}
+
+	codes = append(codes, fixedReasonCodes...)
+
+	for _, rule := range rs.HardStops {
+		codes = append(codes, ReasonCode{
+			Code:        reasonCodeHardStopPrefix + rule.Code,
+			Factor:      "Hard Stop: " + rule.Code,
+			Impact:      "HARD_STOP",
+			Description: rule.Description,
+		})
+	}
+
+	if rs.Trends != nil {
+		for _, signal := range rs.Trends.Signals {
+			description := fmt.Sprintf("%s rose in each of the last %d periods", signal.Metric, signal.Periods)
+			if signal.Kind == TrendDrop {
+				description = fmt.Sprintf("%s fell %g%% or more over the last %d periods",
+					signal.Metric, signal.DropPercent, signal.Periods)
+			}
+			codes = append(codes, ReasonCode{
+				Code:        reasonCodeTrendPrefix + signal.Code,
+				Factor:      "Trend: " + signal.Code,
+				Impact:      "NEGATIVE",
+				Description: description,
+			})
+		}
+	}
+
+	return codes
+}
+
+// ReasonCodes lists the reason codes of the ruleset new decisions are scored
+// with.
+func (s *Service) ReasonCodes() []ReasonCode {
+	return ReasonCodeCatalog(s.evaluator.Ruleset())
+}
@@ -0,0 +1,68 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+func TestReasonCodeCatalog(t *testing.T) {
+	catalog := ReasonCodeCatalog(DefaultRuleset())
+
+	codes := make(map[string]ReasonCode, len(catalog))
+	for _, code := range catalog {
+		if _, dup := codes[code.Code]; dup {
+			t.Errorf("duplicate reason code %s", code.Code)
+		}
+		codes[code.Code] = code
+	}
+
+	for _, want := range []string{"CB_RATE_CRITICAL", "ACCOUNT_AGE_VERY_NEW", "VELOCITY_HIGH_RISK", "REFUND_RATE_HIGH",
+		"CATEGORY_HIGH_RISK", "KYC_NONE", "HARD_STOP_UNVERIFIED_HIGH_VOLUME", "TREND_CHARGEBACK_RATE_RISING"} {
+		if _, ok := codes[want]; !ok {
+			t.Errorf("expected %s in catalog", want)
+		}
+	}
+
+	if got := codes["CB_RATE_CONCERNING"].Description; got != "Chargeback Rate from 1 to under 1.5" {
+		t.Errorf("unexpected description %q", got)
+	}
+}
+
+func TestExplanationsCarryCatalogCodes(t *testing.T) {
+	rules := DefaultRuleset()
+	catalog := make(map[string]bool)
+	for _, code := range ReasonCodeCatalog(rules) {
+		catalog[code.Code] = true
+	}
+
+	m := &merchant.Merchant{
+		Industry:             "TRAVEL",
+		AccountAgeDays:       10,
+		TransactionVolume30d: decimal.NewFromFloat(80000),
+		ChargebackRate:       decimal.NewFromFloat(3.5),
+		VelocityMultiplier:   decimal.NewFromFloat(4.0),
+		RefundRate:           decimal.NewFromFloat(7.0),
+	}
+	e := NewExplainerFromRuleset(rules, "es")
+
+	reasoning := e.GenerateReasoning(m, 100, FactorScore{}, PolicyTier{})
+	e.ApplyHardStops(&reasoning, rules.HardStops, PolicyTier{}, PolicyTier{})
+	e.ApplyTrends(&reasoning, []TrendMatch{{Code: "VOLUME_DROP", Kind: TrendDrop, From: 2, To: 1}})
+
+	want := []string{"CB_RATE_CRITICAL", "ACCOUNT_AGE_VERY_NEW", "VELOCITY_HIGH_RISK", "CATEGORY_HIGH_RISK",
+		"KYC_NONE", "REFUND_RATE_HIGH", "HARD_STOP_UNVERIFIED_HIGH_VOLUME", "HARD_STOP_CHARGEBACK_RATE_CRITICAL",
+		"TREND_VOLUME_DROP"}
+	if len(reasoning.PrimaryFactors) != len(want) {
+		t.Fatalf("expected %d factors, got %d", len(want), len(reasoning.PrimaryFactors))
+	}
+	for i, factor := range reasoning.PrimaryFactors {
+		if factor.ReasonCode != want[i] {
+			t.Errorf("factor %s: expected code %s, got %s", factor.Factor, want[i], factor.ReasonCode)
+		}
+		if !catalog[factor.ReasonCode] {
+			t.Errorf("code %s missing from catalog", factor.ReasonCode)
+		}
+	}
+}
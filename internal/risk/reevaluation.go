@@ -0,0 +1,93 @@
+package risk
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+// ReevaluationQueue re-evaluates merchants in the background after their
+// score-relevant metrics change. The queue only speeds things up: the change's
+// snapshot is already marked due, so a change dropped while the queue is full
+// or lost on restart is re-evaluated by the scheduler's next cycle.
+type ReevaluationQueue struct {
+	service *Service
+	changes chan merchant.MetricChange
+	workers int
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func NewReevaluationQueue(service *Service, size, workers int) *ReevaluationQueue {
+	if size <= 0 {
+		size = 1000
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	return &ReevaluationQueue{
+		service: service,
+		changes: make(chan merchant.MetricChange, size),
+		workers: workers,
+	}
+}
+
+// Start launches the workers. Changes enqueued before Start wait in the
+// buffer.
+func (q *ReevaluationQueue) Start() {
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for change := range q.changes {
+				q.process(change)
+			}
+		}()
+	}
+}
+
+// Stop stops accepting changes and waits for the queued ones to be evaluated.
+func (q *ReevaluationQueue) Stop() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.changes)
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
+
+// Enqueue queues the change without blocking. It reports false when the queue
+// is full or stopped.
+func (q *ReevaluationQueue) Enqueue(change merchant.MetricChange) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return false
+	}
+
+	select {
+	case q.changes <- change:
+		return true
+	default:
+		return false
+	}
+}
+
+func (q *ReevaluationQueue) process(change merchant.MetricChange) {
+	decision, err := q.service.ReevaluateMetricChange(context.Background(), change)
+	if err != nil {
+		log.Printf("[ERROR] Re-evaluation of merchant %s after metric change %s failed: %v",
+			change.MerchantID, change.SnapshotID, err)
+		return
+	}
+	log.Printf("[INFO] Metric change %s re-evaluated merchant %s as %s (decision %s)",
+		change.SnapshotID, change.MerchantID, decision.RiskLevel, decision.ID)
+}
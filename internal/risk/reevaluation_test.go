@@ -0,0 +1,51 @@
+package risk
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+func TestReevaluationQueue(t *testing.T) {
+	m := &merchant.Merchant{ID: uuid.New(), Industry: "RETAIL", AccountAgeDays: 400, KYCVerified: true,
+		ChargebackRate: decimal.NewFromFloat(2.0), VelocityMultiplier: decimal.NewFromFloat(1.0)}
+
+	var mu sync.Mutex
+	var saved []*RiskDecision
+	service := NewService(&mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) { return m, nil },
+	}, &mockDecisionRepository{
+		createDecision: func(ctx context.Context, decision *RiskDecision) error {
+			mu.Lock()
+			defer mu.Unlock()
+			saved = append(saved, decision)
+			return nil
+		},
+	})
+
+	queue := NewReevaluationQueue(service, 1, 1)
+	change := merchant.MetricChange{MerchantID: m.ID, SnapshotID: uuid.New()}
+	if !queue.Enqueue(change) {
+		t.Fatal("expected change to be queued")
+	}
+	if queue.Enqueue(merchant.MetricChange{MerchantID: m.ID, SnapshotID: uuid.New()}) {
+		t.Error("expected change to be dropped while the queue is full")
+	}
+
+	queue.Start()
+	queue.Stop()
+
+	if len(saved) != 1 {
+		t.Fatalf("expected one persisted decision, got %d", len(saved))
+	}
+	if saved[0].Simulation || saved[0].TriggerSnapshotID == nil || *saved[0].TriggerSnapshotID != change.SnapshotID {
+		t.Errorf("expected persisted decision linked to snapshot %s, got %+v", change.SnapshotID, saved[0])
+	}
+	if queue.Enqueue(change) {
+		t.Error("expected stopped queue to refuse changes")
+	}
+}
@@ -0,0 +1,429 @@
+package risk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/yuno-payments/papaya-payout-engine/internal/platform/constants"
+)
+
+const (
+	ScoringModeBanded     = "banded"
+	ScoringModeContinuous = "continuous"
+)
+
+var ErrInvalidScoringMode = errors.New("invalid scoring mode")
+
+// Ruleset declares how every risk factor is scored. It is loaded from a JSON
+// file at startup so the risk team can tune bands and points without a deploy.
+// Mode selects step bands (the default) or continuous interpolation.
+type Ruleset struct {
+	Version    string            `json:"version"`
+	Mode       string            `json:"mode,omitempty"`
+	Chargeback BandedFactor      `json:"chargeback"`
+	AccountAge BandedFactor      `json:"account_age"`
+	Velocity   BandedFactor      `json:"velocity"`
+	Category   CategoricalFactor `json:"category"`
+	KYC        CategoricalFactor `json:"kyc"`
+	Refund     BandedFactor      `json:"refund"`
+	HardStops  []HardStopRule    `json:"hard_stops,omitempty"`
+	Smoothing  *RateSmoothing    `json:"smoothing,omitempty"`
+	Trends     *TrendFactor      `json:"trends,omitempty"`
+}
+
+// Band is one step of a numeric factor. A value falls in the first band whose
+// Below bound it is strictly under; the last band has no bound and catches
+// everything else.
+// Label also names the band's reason code, so renaming it is a breaking change
+// for integrations keyed on the code.
+type Band struct {
+	Label  string   `json:"label"`
+	Below  *float64 `json:"below,omitempty"`
+	Points int      `json:"points"`
+}
+
+// BandedFactor scores a numeric merchant metric with ascending bands.
+type BandedFactor struct {
+	MaxPoints int    `json:"max_points"`
+	Bands     []Band `json:"bands"`
+}
+
+// CategoricalFactor scores a string attribute by exact match, falling back to
+// Default for values the ruleset does not list.
+type CategoricalFactor struct {
+	MaxPoints int            `json:"max_points"`
+	Values    map[string]int `json:"values"`
+	Default   int            `json:"default"`
+}
+
+func (f BandedFactor) Score(value float64) int {
+	if band, ok := f.Band(value); ok {
+		return band.Points
+	}
+	return f.MaxPoints
+}
+
+// Band returns the band value falls in.
+func (f BandedFactor) Band(value float64) (Band, bool) {
+	for _, band := range f.Bands {
+		if band.Below == nil || value < *band.Below {
+			return band, true
+		}
+	}
+	return Band{}, false
+}
+
+// Interpolate scores value on a continuous scale: within each band the points
+// move linearly from the band's own points at its lower bound to the next
+// band's points at its upper bound, so there is no jump at band edges. The
+// first band starts at zero and the open last band keeps its points.
+func (f BandedFactor) Interpolate(value float64) float64 {
+	lower := 0.0
+	for i, band := range f.Bands {
+		if band.Below == nil {
+			return float64(band.Points)
+		}
+		if value < *band.Below {
+			if value <= lower || *band.Below <= lower {
+				return float64(band.Points)
+			}
+			next := f.Bands[i+1].Points
+			t := (value - lower) / (*band.Below - lower)
+			return float64(band.Points) + t*float64(next-band.Points)
+		}
+		lower = *band.Below
+	}
+	return float64(f.MaxPoints)
+}
+
+// Threshold returns the upper bound of the band with the given label.
+func (f BandedFactor) Threshold(label string) (float64, bool) {
+	for _, band := range f.Bands {
+		if band.Label == label && band.Below != nil {
+			return *band.Below, true
+		}
+	}
+	return 0, false
+}
+
+func (f *BandedFactor) setThreshold(label string, value float64) bool {
+	for i := range f.Bands {
+		if f.Bands[i].Label == label && f.Bands[i].Below != nil {
+			v := value
+			f.Bands[i].Below = &v
+			return true
+		}
+	}
+	return false
+}
+
+func (f CategoricalFactor) Score(value string) int {
+	if points, ok := f.Values[value]; ok {
+		return points
+	}
+	return f.Default
+}
+
+func (r *Ruleset) bandedFactor(name string) *BandedFactor {
+	switch name {
+	case "chargeback":
+		return &r.Chargeback
+	case "account_age":
+		return &r.AccountAge
+	case "velocity":
+		return &r.Velocity
+	case "refund":
+		return &r.Refund
+	default:
+		return nil
+	}
+}
+
+func (f BandedFactor) clone() BandedFactor {
+	bands := make([]Band, len(f.Bands))
+	for i, band := range f.Bands {
+		bands[i] = band
+		if band.Below != nil {
+			v := *band.Below
+			bands[i].Below = &v
+		}
+	}
+	return BandedFactor{MaxPoints: f.MaxPoints, Bands: bands}
+}
+
+func (f CategoricalFactor) clone() CategoricalFactor {
+	values := make(map[string]int, len(f.Values))
+	for k, v := range f.Values {
+		values[k] = v
+	}
+	return CategoricalFactor{MaxPoints: f.MaxPoints, Values: values, Default: f.Default}
+}
+
+// Clone returns a deep copy so callers can adjust thresholds without touching
+// the ruleset shared by the service.
+func (r *Ruleset) Clone() *Ruleset {
+	return &Ruleset{
+		Version:    r.Version,
+		Mode:       r.Mode,
+		Chargeback: r.Chargeback.clone(),
+		AccountAge: r.AccountAge.clone(),
+		Velocity:   r.Velocity.clone(),
+		Category:   r.Category.clone(),
+		KYC:        r.KYC.clone(),
+		Refund:     r.Refund.clone(),
+		HardStops:  cloneHardStops(r.HardStops),
+		Smoothing:  r.Smoothing.clone(),
+		Trends:     r.Trends.clone(),
+	}
+}
+
+func cloneHardStops(rules []HardStopRule) []HardStopRule {
+	if rules == nil {
+		return nil
+	}
+	cloned := make([]HardStopRule, len(rules))
+	for i, rule := range rules {
+		cloned[i] = rule
+		cloned[i].Conditions = append([]RuleCondition(nil), rule.Conditions...)
+	}
+	return cloned
+}
+
+// Validate checks that every factor is internally consistent: bands ascend,
+// points stay within the factor maximum, labels are unique and hard-stop rules
+// reference known fields and policies.
+func (r *Ruleset) Validate() error {
+	var errs []error
+
+	if r.Version == "" {
+		errs = append(errs, errors.New("version is required"))
+	}
+	if err := validateScoringMode(r.Mode); err != nil {
+		errs = append(errs, err)
+	}
+
+	errs = append(errs, validateBandedFactor("chargeback", r.Chargeback)...)
+	errs = append(errs, validateBandedFactor("account_age", r.AccountAge)...)
+	errs = append(errs, validateBandedFactor("velocity", r.Velocity)...)
+	errs = append(errs, validateCategoricalFactor("category", r.Category)...)
+	errs = append(errs, validateCategoricalFactor("kyc", r.KYC)...)
+	errs = append(errs, validateBandedFactor("refund", r.Refund)...)
+	errs = append(errs, validateHardStops(r.HardStops)...)
+	errs = append(errs, validateSmoothing(r.Smoothing)...)
+	errs = append(errs, validateTrends(r.Trends)...)
+
+	return errors.Join(errs...)
+}
+
+func validateScoringMode(mode string) error {
+	switch mode {
+	case "", ScoringModeBanded, ScoringModeContinuous:
+		return nil
+	default:
+		return fmt.Errorf("%w %q: must be %s or %s", ErrInvalidScoringMode, mode, ScoringModeBanded, ScoringModeContinuous)
+	}
+}
+
+// Continuous reports whether factors are interpolated rather than banded.
+func (r *Ruleset) Continuous() bool {
+	return r.Mode == ScoringModeContinuous
+}
+
+// bandLabelPattern keeps band labels usable in reason codes, which are built
+// from them (ACCOUNT_AGE_VERY_NEW from very_new).
+var bandLabelPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+func validateBandedFactor(name string, f BandedFactor) []error {
+	var errs []error
+
+	if f.MaxPoints <= 0 {
+		errs = append(errs, fmt.Errorf("%s: max_points must be positive", name))
+	}
+	if len(f.Bands) == 0 {
+		return append(errs, fmt.Errorf("%s: at least one band is required", name))
+	}
+
+	labels := make(map[string]bool, len(f.Bands))
+	for i, band := range f.Bands {
+		if band.Label == "" {
+			errs = append(errs, fmt.Errorf("%s: band %d has no label", name, i))
+		} else if !bandLabelPattern.MatchString(band.Label) {
+			errs = append(errs, fmt.Errorf("%s: band label %q must be lowercase letters, digits or underscores", name, band.Label))
+		} else if labels[band.Label] {
+			errs = append(errs, fmt.Errorf("%s: duplicate band label %q", name, band.Label))
+		}
+		labels[band.Label] = true
+
+		if band.Points < 0 || band.Points > f.MaxPoints {
+			errs = append(errs, fmt.Errorf("%s: band %q points %d outside [0, %d]",
+				name, band.Label, band.Points, f.MaxPoints))
+		}
+
+		last := i == len(f.Bands)-1
+		switch {
+		case last && band.Below != nil:
+			errs = append(errs, fmt.Errorf("%s: last band %q must not set below", name, band.Label))
+		case !last && band.Below == nil:
+			errs = append(errs, fmt.Errorf("%s: band %q must set below", name, band.Label))
+		case !last && i > 0 && f.Bands[i-1].Below != nil && *band.Below <= *f.Bands[i-1].Below:
+			errs = append(errs, fmt.Errorf("%s: band %q below %.2f must be greater than previous band",
+				name, band.Label, *band.Below))
+		}
+	}
+
+	return errs
+}
+
+func validateCategoricalFactor(name string, f CategoricalFactor) []error {
+	var errs []error
+
+	if f.MaxPoints <= 0 {
+		errs = append(errs, fmt.Errorf("%s: max_points must be positive", name))
+	}
+	if f.Default < 0 || f.Default > f.MaxPoints {
+		errs = append(errs, fmt.Errorf("%s: default points %d outside [0, %d]", name, f.Default, f.MaxPoints))
+	}
+	for value, points := range f.Values {
+		if points < 0 || points > f.MaxPoints {
+			errs = append(errs, fmt.Errorf("%s: %q points %d outside [0, %d]", name, value, points, f.MaxPoints))
+		}
+	}
+
+	return errs
+}
+
+// ParseRuleset decodes a JSON ruleset, rejecting unknown fields, and validates it.
+func ParseRuleset(data []byte) (*Ruleset, error) {
+	var rs Ruleset
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rs); err != nil {
+		return nil, fmt.Errorf("failed to decode ruleset: %w", err)
+	}
+
+	if err := rs.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ruleset: %w", err)
+	}
+
+	return &rs, nil
+}
+
+func LoadRuleset(path string) (*Ruleset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ruleset %s: %w", path, err)
+	}
+
+	rs, err := ParseRuleset(data)
+	if err != nil {
+		return nil, fmt.Errorf("ruleset %s: %w", path, err)
+	}
+
+	return rs, nil
+}
+
+func below(v float64) *float64 {
+	return &v
+}
+
+// DefaultRuleset reproduces the built-in scoring model. It is used when no
+// ruleset file is configured.
+func DefaultRuleset() *Ruleset {
+	return &Ruleset{
+		Version: constants.DefaultRulesetVersion,
+		Chargeback: BandedFactor{
+			MaxPoints: 30,
+			Bands: []Band{
+				{Label: "excellent", Below: below(constants.DefaultChargebackExcellent), Points: 0},
+				{Label: "acceptable", Below: below(constants.DefaultChargebackAcceptable), Points: 10},
+				{Label: "concerning", Below: below(constants.DefaultChargebackCritical), Points: 20},
+				{Label: "critical", Points: 30},
+			},
+		},
+		AccountAge: BandedFactor{
+			MaxPoints: 25,
+			Bands: []Band{
+				{Label: "very_new", Below: below(constants.AccountAgeVeryNew), Points: 25},
+				{Label: "new", Below: below(constants.AccountAgeNew), Points: 20},
+				{Label: "early", Below: below(constants.AccountAgeEarly), Points: 15},
+				{Label: "established", Below: below(constants.AccountAgeEstablished), Points: 10},
+				{Label: "mature", Below: below(constants.AccountAgeMature), Points: 5},
+				{Label: "veteran", Points: 0},
+			},
+		},
+		Velocity: BandedFactor{
+			MaxPoints: 20,
+			Bands: []Band{
+				{Label: "normal", Below: below(constants.DefaultVelocityNormal), Points: 0},
+				{Label: "elevated", Below: below(constants.DefaultVelocityElevated), Points: 5},
+				{Label: "concerning", Below: below(constants.DefaultVelocityConcerning), Points: 10},
+				{Label: "high_risk", Below: below(constants.DefaultVelocityHighRisk), Points: 15},
+				{Label: "critical", Points: 20},
+			},
+		},
+		Category: CategoricalFactor{
+			MaxPoints: 15,
+			Values: map[string]int{
+				"DIGITAL_GOODS": 15,
+				"TRAVEL":        15,
+				"ELECTRONICS":   15,
+				"FASHION":       10,
+				"SERVICES":      10,
+				"FOOD_DELIVERY": 5,
+				"RETAIL":        5,
+				"UTILITIES":     0,
+				"HEALTHCARE":    0,
+			},
+			Default: 10,
+		},
+		KYC: CategoricalFactor{
+			MaxPoints: 10,
+			Values: map[string]int{
+				"NONE":     10,
+				"PARTIAL":  7,
+				"FULL":     3,
+				"ENHANCED": 0,
+			},
+			Default: 10,
+		},
+		Refund: BandedFactor{
+			MaxPoints: 5,
+			Bands: []Band{
+				{Label: "normal", Below: below(constants.DefaultRefundNormal), Points: 0},
+				{Label: "elevated", Below: below(constants.DefaultRefundElevated), Points: 3},
+				{Label: "high", Points: 5},
+			},
+		},
+		HardStops: []HardStopRule{
+			{
+				Code:        "UNVERIFIED_HIGH_VOLUME",
+				Description: "Unverified KYC with 30-day volume above 50000",
+				Conditions: []RuleCondition{
+					{Field: "kyc_verified", Op: "eq", Value: false},
+					{Field: "transaction_volume_30d", Op: "gt", Value: float64(50000)},
+				},
+				MinRiskLevel: RiskLevelHigh,
+			},
+			{
+				Code:        "CHARGEBACK_RATE_CRITICAL",
+				Description: "Chargeback rate above 3%",
+				Conditions: []RuleCondition{
+					{Field: "chargeback_rate", Op: "gt", Value: float64(3)},
+				},
+				MinRiskLevel: RiskLevelCritical,
+			},
+		},
+		Trends: &TrendFactor{
+			MaxPoints:  15,
+			PeriodDays: 7,
+			Signals: []TrendSignal{
+				{Code: "CHARGEBACK_RATE_RISING", Metric: "chargeback_rate", Kind: TrendRising, Periods: 3, Points: 10},
+				{Code: "VOLUME_DROP", Metric: "transaction_volume_30d", Kind: TrendDrop, Periods: 2, DropPercent: 50, Points: 5},
+			},
+		},
+	}
+}
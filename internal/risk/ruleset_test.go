@@ -0,0 +1,305 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+func TestLoadRuleset(t *testing.T) {
+	t.Run("shipped default matches built-in ruleset", func(t *testing.T) {
+		rs, err := LoadRuleset("../../rulesets/default.json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(rs, DefaultRuleset()) {
+			t.Error("rulesets/default.json drifted from DefaultRuleset()")
+		}
+	})
+
+	t.Run("fixture ruleset drives the evaluator", func(t *testing.T) {
+		rs, err := LoadRuleset("testdata/strict_ruleset.json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		e := NewEvaluatorFromRuleset(rs)
+		m := &merchant.Merchant{
+			ChargebackRate: decimal.NewFromFloat(0.4),
+			AccountAgeDays: 400,
+			Industry:       "UNKNOWN",
+			KYCVerified:    true,
+			KYCLevel:       "FULL",
+		}
+
+		if got := e.CalculateChargebackScore(m); got != 15 {
+			t.Errorf("CalculateChargebackScore() = %d, want 15", got)
+		}
+		if got := e.CalculateAccountAgeScore(m); got != 10 {
+			t.Errorf("CalculateAccountAgeScore() = %d, want 10", got)
+		}
+		if got := e.CalculateCategoryScore(m); got != 12 {
+			t.Errorf("CalculateCategoryScore() = %d, want 12", got)
+		}
+		if got := e.CalculateKYCScore(m); got != 5 {
+			t.Errorf("CalculateKYCScore() = %d, want 5", got)
+		}
+	})
+
+	t.Run("invalid ruleset is rejected", func(t *testing.T) {
+		_, err := LoadRuleset("testdata/invalid_ruleset.json")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		for _, want := range []string{
+			`chargeback: band "acceptable" below 0.50 must be greater than previous band`,
+			`chargeback: band "critical" points 45 outside [0, 30]`,
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to contain %q, got %v", want, err)
+			}
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadRuleset("testdata/does_not_exist.json"); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestParseRuleset(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{
+			name:    "unknown field",
+			body:    `{"chargeback": {"max_points": 30, "bands": [], "weight": 2}}`,
+			wantErr: "unknown field",
+		},
+		{
+			name:    "malformed json",
+			body:    `{"chargeback":`,
+			wantErr: "failed to decode ruleset",
+		},
+		{
+			name:    "missing factors",
+			body:    `{}`,
+			wantErr: "chargeback: max_points must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRuleset([]byte(tt.body))
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error to contain %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRulesetValidate(t *testing.T) {
+	t.Run("default ruleset is valid", func(t *testing.T) {
+		if err := DefaultRuleset().Validate(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("version is required", func(t *testing.T) {
+		rs := DefaultRuleset()
+		rs.Version = ""
+
+		if err := rs.Validate(); err == nil {
+			t.Error("expected error for missing version")
+		}
+	})
+
+	t.Run("last band must be open", func(t *testing.T) {
+		rs := DefaultRuleset()
+		rs.Refund.Bands[2].Below = below(10)
+
+		if err := rs.Validate(); err == nil {
+			t.Error("expected error for bounded last band")
+		}
+	})
+
+	t.Run("duplicate labels", func(t *testing.T) {
+		rs := DefaultRuleset()
+		rs.Velocity.Bands[1].Label = "normal"
+
+		if err := rs.Validate(); err == nil {
+			t.Error("expected error for duplicate band label")
+		}
+	})
+
+	t.Run("labels must fit reason codes", func(t *testing.T) {
+		for _, label := range []string{"very new", "high-risk", "Normal"} {
+			rs := DefaultRuleset()
+			rs.AccountAge.Bands[0].Label = label
+
+			if err := rs.Validate(); err == nil {
+				t.Errorf("expected error for band label %q", label)
+			}
+		}
+	})
+
+	t.Run("categorical points above max", func(t *testing.T) {
+		rs := DefaultRuleset()
+		rs.Category.Values["GAMBLING"] = 20
+
+		if err := rs.Validate(); err == nil {
+			t.Error("expected error for categorical points above max")
+		}
+	})
+}
+
+func TestEvaluatorWithThresholds(t *testing.T) {
+	base := NewEvaluator()
+	custom := base.WithThresholds(ScoringThresholds{ChargebackExcellent: ptr(0.3)})
+
+	m := &merchant.Merchant{ChargebackRate: decimal.NewFromFloat(0.4)}
+
+	if got := custom.CalculateChargebackScore(m); got != 10 {
+		t.Errorf("custom CalculateChargebackScore() = %d, want 10", got)
+	}
+	if got := base.CalculateChargebackScore(m); got != 0 {
+		t.Errorf("base evaluator was modified: CalculateChargebackScore() = %d, want 0", got)
+	}
+	if custom.Version() == base.Version() {
+		t.Errorf("expected custom thresholds to change the model version, got %s", custom.Version())
+	}
+}
+
+func TestBandedFactorInterpolate(t *testing.T) {
+	chargeback := DefaultRuleset().Chargeback
+	accountAge := DefaultRuleset().AccountAge
+
+	tests := []struct {
+		name   string
+		factor BandedFactor
+		value  float64
+		want   float64
+	}{
+		{"zero", chargeback, 0, 0},
+		{"just below first edge", chargeback, 0.49, 9.8},
+		{"first edge", chargeback, 0.5, 10},
+		{"mid second band", chargeback, 0.75, 15},
+		{"open last band keeps max", chargeback, 4.0, 30},
+		{"decreasing factor start", accountAge, 0, 25},
+		{"decreasing factor mid band", accountAge, 15, 22.5},
+		{"decreasing factor veteran", accountAge, 1000, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.factor.Interpolate(tt.value)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Interpolate(%v) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluatorContinuousMode(t *testing.T) {
+	rs, err := LoadRuleset("../../rulesets/continuous.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	continuous := NewEvaluatorFromRuleset(rs)
+	banded := NewEvaluator()
+
+	below := &merchant.Merchant{ChargebackRate: decimal.NewFromFloat(0.49)}
+	at := &merchant.Merchant{ChargebackRate: decimal.NewFromFloat(0.50)}
+
+	if diff := banded.CalculateChargebackScore(at) - banded.CalculateChargebackScore(below); diff != 10 {
+		t.Errorf("expected banded scoring to jump 10 points at the edge, got %d", diff)
+	}
+	if diff := continuous.CalculateChargebackScore(at) - continuous.CalculateChargebackScore(below); diff > 1 {
+		t.Errorf("expected continuous scoring to move at most 1 point at the edge, got %d", diff)
+	}
+
+	worst := &merchant.Merchant{
+		ChargebackRate:     decimal.NewFromFloat(5),
+		VelocityMultiplier: decimal.NewFromFloat(10),
+		RefundRate:         decimal.NewFromFloat(10),
+		Industry:           "TRAVEL",
+	}
+	_, factors := continuous.CalculateTotalScore(worst)
+	if factors.Chargeback != 30 || factors.AccountAge != 25 || factors.Velocity != 20 || factors.Refund != 5 {
+		t.Errorf("expected factor maxima preserved, got %+v", factors)
+	}
+
+	t.Run("WithMode", func(t *testing.T) {
+		switched, err := banded.WithMode(ScoringModeContinuous)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !switched.Ruleset().Continuous() || banded.Ruleset().Continuous() {
+			t.Error("expected only the copy to switch to continuous mode")
+		}
+		if switched.Version() != banded.Version()+"+continuous" {
+			t.Errorf("unexpected version %s", switched.Version())
+		}
+
+		if same, _ := banded.WithMode(ScoringModeBanded); same != banded {
+			t.Error("expected same evaluator when mode is unchanged")
+		}
+		if _, err := banded.WithMode("smooth"); !errors.Is(err, ErrInvalidScoringMode) {
+			t.Errorf("expected ErrInvalidScoringMode, got %v", err)
+		}
+	})
+}
+
+func TestSimulateMerchantScoringMode(t *testing.T) {
+	merchantID := uuid.New()
+	service := NewService(&mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return &merchant.Merchant{
+				ID:             merchantID,
+				Industry:       "UTILITIES",
+				AccountAgeDays: 900,
+				ChargebackRate: decimal.NewFromFloat(0.49),
+				KYCVerified:    true,
+				KYCLevel:       "ENHANCED",
+			}, nil
+		},
+	}, &mockDecisionRepository{})
+
+	banded, err := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	continuous, err := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{
+		ScoringMode: ptr(ScoringModeContinuous),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if banded.RiskScore != 0 || continuous.RiskScore != 10 {
+		t.Errorf("expected banded 0 and continuous 10, got %d and %d", banded.RiskScore, continuous.RiskScore)
+	}
+	if !strings.HasSuffix(continuous.ModelVersion, "+continuous") {
+		t.Errorf("expected continuous model version, got %s", continuous.ModelVersion)
+	}
+
+	if _, err := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{
+		ScoringMode: ptr("smooth"),
+	}); !errors.Is(err, ErrInvalidScoringMode) {
+		t.Errorf("expected ErrInvalidScoringMode, got %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+package risk
+
+import (
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+const (
+	ScorerAdditive = "additive"
+	ScorerLogistic = "logistic"
+)
+
+// Scorer turns a merchant into a 0-100 risk score together with a per-factor
+// breakdown used to explain the decision. Version identifies the model and is
+// stamped on every decision it produces.
+type Scorer interface {
+	Score(m *merchant.Merchant) (int, FactorScore)
+	Version() string
+}
+
+// Score implements Scorer with the additive ruleset model.
+func (e *Evaluator) Score(m *merchant.Merchant) (int, FactorScore) {
+	return e.CalculateTotalScore(m)
+}
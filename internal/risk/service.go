@@ -4,10 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/shopspring/decimal"
 	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
 )
 
@@ -18,28 +18,129 @@ type MerchantRepository interface {
 type DecisionRepository interface {
 	Create(ctx context.Context, decision *RiskDecision) error
 	GetLatestByMerchant(ctx context.Context, merchantID uuid.UUID) (*RiskDecision, error)
+	// ListRecentByMerchant returns up to limit of the merchant's EFFECTIVE
+	// decisions, newest first.
+	ListRecentByMerchant(ctx context.Context, merchantID uuid.UUID, limit int) ([]RiskDecision, error)
 	BulkCreate(ctx context.Context, decisions []RiskDecision) error
 }
 
+// MetricHistoryRepository reads the metric snapshots trend factors score.
+type MetricHistoryRepository interface {
+	ListByMerchantSince(ctx context.Context, merchantID uuid.UUID, since time.Time) ([]merchant.MetricSnapshot, error)
+}
+
+// ReviewQueue receives persisted decisions that need a manual review.
+type ReviewQueue interface {
+	OpenCase(ctx context.Context, decision *RiskDecision) error
+}
+
 type Service struct {
 	merchantStore MerchantRepository
 	decisionStore DecisionRepository
+	tierTables    TierTableRepository
+	overrides     PolicyOverrideRepository
+	history       MetricHistoryRepository
+	hysteresis    Hysteresis
+	manual        ManualOverrideRepository
+	reviews       ReviewQueue
+	approval      ApprovalPolicy
+	approvals     ApprovalRepository
+	shadows       ShadowRepository
+	challengers   []Challenger
+	portfolio     PortfolioRepository
 	evaluator     *Evaluator
+	scorer        Scorer
 	policy        *PolicyMapper
-	explainer     *Explainer
+}
+
+// Option customizes a Service at construction time.
+type Option func(*Service)
+
+// WithEvaluator replaces the default evaluator, typically with one built from
+// a ruleset file.
+func WithEvaluator(evaluator *Evaluator) Option {
+	return func(s *Service) {
+		s.evaluator = evaluator
+	}
+}
+
+// WithScorer replaces the model that produces the risk score. The evaluator's
+// ruleset still supplies hard-stop rules.
+func WithScorer(scorer Scorer) Option {
+	return func(s *Service) {
+		s.scorer = scorer
+	}
+}
+
+// WithTierTables resolves policy tiers from the tier table active at
+// evaluation time instead of the built-in tiers.
+func WithTierTables(tierTables TierTableRepository) Option {
+	return func(s *Service) {
+		s.tierTables = tierTables
+	}
+}
+
+// WithPolicyOverrides layers country- and industry-specific overrides on top
+// of the active tier table.
+func WithPolicyOverrides(overrides PolicyOverrideRepository) Option {
+	return func(s *Service) {
+		s.overrides = overrides
+	}
+}
+
+// WithMetricHistory enables trend factors by supplying each merchant's metric
+// snapshots. Without it only point-in-time factors are scored.
+func WithMetricHistory(history MetricHistoryRepository) Option {
+	return func(s *Service) {
+		s.history = history
+	}
+}
+
+// WithHysteresis damps upgrades to a better tier for merchants whose score
+// sits near a tier boundary.
+func WithHysteresis(hysteresis Hysteresis) Option {
+	return func(s *Service) {
+		s.hysteresis = hysteresis
+	}
+}
+
+// WithManualOverrides lets analyst overrides take precedence over the computed
+// hold period and reserve while they are active.
+func WithManualOverrides(manual ManualOverrideRepository) Option {
+	return func(s *Service) {
+		s.manual = manual
+	}
+}
+
+// WithReviewQueue opens a review case for every persisted decision at or above
+// HIGH risk.
+func WithReviewQueue(reviews ReviewQueue) Option {
+	return func(s *Service) {
+		s.reviews = reviews
+	}
 }
 
 func NewService(
 	merchantStore MerchantRepository,
 	decisionStore DecisionRepository,
+	opts ...Option,
 ) *Service {
-	return &Service{
+	s := &Service{
 		merchantStore: merchantStore,
 		decisionStore: decisionStore,
 		evaluator:     NewEvaluator(),
 		policy:        NewPolicyMapper(),
-		explainer:     NewExplainer(),
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.scorer == nil {
+		s.scorer = s.evaluator
+	}
+
+	return s
 }
 
 // EvaluateMerchant performs a comprehensive risk assessment of a merchant and
@@ -59,6 +160,18 @@ func NewService(
 // Returns a RiskDecision containing the risk score (0-100), assigned tier,
 // policy parameters, and detailed reasoning for the decision.
 func (s *Service) EvaluateMerchant(ctx context.Context, merchantID uuid.UUID, simulation bool) (*RiskDecision, error) {
+	return s.evaluate(ctx, merchantID, simulation, nil)
+}
+
+// ReevaluateMetricChange persists a new decision for a merchant whose
+// score-relevant metrics changed. The decision's TriggerSnapshotID links it to
+// the snapshot recording the change.
+func (s *Service) ReevaluateMetricChange(ctx context.Context, change merchant.MetricChange) (*RiskDecision, error) {
+	log.Printf("[INFO] Re-evaluating merchant %s after metric change %s", change.MerchantID, change.SnapshotID)
+	return s.evaluate(ctx, change.MerchantID, false, &change.SnapshotID)
+}
+
+func (s *Service) evaluate(ctx context.Context, merchantID uuid.UUID, simulation bool, trigger *uuid.UUID) (*RiskDecision, error) {
 	log.Printf("[INFO] Evaluating merchant %s (simulation=%v)", merchantID, simulation)
 
 	m, err := s.merchantStore.Get(ctx, merchantID)
@@ -67,19 +180,93 @@ func (s *Service) EvaluateMerchant(ctx context.Context, merchantID uuid.UUID, si
 		return nil, fmt.Errorf("failed to get merchant %s: %w", merchantID, err)
 	}
 
-	totalScore, factors := s.evaluator.CalculateTotalScore(m)
-	tier := s.policy.DeterminePolicyTier(totalScore)
-	reasoning := s.explainer.GenerateReasoning(m, factors, tier)
+	evaluatedAt := time.Now()
+	policy, override, err := s.resolvePolicy(ctx, m, evaluatedAt)
+	if err != nil {
+		log.Printf("[ERROR] Failed to resolve policy tiers for merchant %s: %v", merchantID, err)
+		return nil, fmt.Errorf("failed to resolve policy tiers: %w", err)
+	}
+
+	history, err := s.trendHistory(ctx, s.scorer, merchantID, evaluatedAt)
+	if err != nil {
+		log.Printf("[ERROR] Failed to load metric history for merchant %s: %v", merchantID, err)
+		return nil, fmt.Errorf("failed to load metric history: %w", err)
+	}
+
+	totalScore, factors, trends := scoreWithHistory(s.scorer, m, history, evaluatedAt)
+	scoredTier := policy.DeterminePolicyTier(totalScore)
+	stoppedTier, hardStops := ApplyHardStops(s.evaluator.Ruleset().HardStops, m, policy, scoredTier)
+
+	tier, hold, err := s.applyHysteresis(ctx, merchantID, policy, totalScore, stoppedTier)
+	if err != nil {
+		log.Printf("[ERROR] Failed to load decision history for merchant %s: %v", merchantID, err)
+		return nil, fmt.Errorf("failed to load decision history: %w", err)
+	}
+
+	manual, err := s.activeManualOverride(ctx, merchantID, evaluatedAt)
+	if err != nil {
+		log.Printf("[ERROR] Failed to load manual override for merchant %s: %v", merchantID, err)
+		return nil, fmt.Errorf("failed to load manual override: %w", err)
+	}
+	var pinned *AppliedManualOverride
+	if manual != nil {
+		tier, pinned = manual.Apply(tier)
+	}
+
+	explainer := explainerFor(s.evaluator.Ruleset())
+	reasoning := explainer.GenerateReasoning(m, totalScore, factors, scoredTier)
+	explainer.ApplyTrends(&reasoning, trends)
+	explainer.ApplyPolicyOverride(&reasoning, override)
+	explainer.ApplyHardStops(&reasoning, hardStops, scoredTier, stoppedTier)
+	explainer.ApplyHysteresis(&reasoning, hold)
+	explainer.ApplyManualOverride(&reasoning, pinned)
+
+	// A manual override already fixes the hold and reserve, so there is no
+	// computed policy left to approve. The risk level stays the computed one,
+	// so a pinned HIGH or CRITICAL merchant still gets a review case.
+	status := DecisionEffective
+	if s.approvals != nil && pinned == nil && s.approval.Requires(tier.RiskLevel) {
+		interim, err := s.interimPolicy(ctx, merchantID)
+		if err != nil {
+			log.Printf("[ERROR] Failed to load effective policy for merchant %s: %v", merchantID, err)
+			return nil, fmt.Errorf("failed to load effective policy: %w", err)
+		}
+		status = DecisionPendingApproval
+		explainer.ApplyPendingApproval(&reasoning, tier.RiskLevel, interim)
+	}
 
 	decision := &RiskDecision{
 		MerchantID:               merchantID,
+		TriggerSnapshotID:        trigger,
 		RiskScore:                totalScore,
 		RiskLevel:                tier.RiskLevel,
 		PayoutHoldPeriod:         tier.HoldPeriod,
 		RollingReservePercentage: tier.ReservePercentage,
 		Reasoning:                reasoning,
-		EvaluatedAt:              time.Now(),
+		Inputs:                   NewDecisionInputs(m),
+		ModelVersion:             s.scorer.Version(),
+		PolicyVersion:            policy.Version(),
+		EvaluatedAt:              evaluatedAt,
 		Simulation:               simulation,
+		Status:                   status,
+	}
+
+	for _, rule := range hardStops {
+		log.Printf("[WARN] Hard stop %s triggered for merchant %s", rule.Code, merchantID)
+	}
+	if hold != nil {
+		log.Printf("[INFO] Hysteresis held merchant %s in %s (scored %s)",
+			merchantID, hold.HeldRiskLevel, hold.ScoredRiskLevel)
+	}
+	if pinned != nil {
+		log.Printf("[WARN] Manual override %s pins merchant %s to hold=%s, reserve=%d%% (computed hold=%s, reserve=%d%%)",
+			pinned.ID, merchantID, pinned.HoldPeriod, pinned.ReservePercentage,
+			pinned.ComputedHoldPeriod, pinned.ComputedReservePercentage)
+	}
+
+	if status == DecisionPendingApproval {
+		log.Printf("[WARN] %s policy for merchant %s requires approval before it takes effect",
+			tier.RiskLevel, merchantID)
 	}
 
 	if totalScore >= 60 {
@@ -96,6 +283,16 @@ func (s *Service) EvaluateMerchant(ctx context.Context, merchantID uuid.UUID, si
 			return nil, fmt.Errorf("failed to save decision for merchant %s: %w", merchantID, err)
 		}
 		log.Printf("[INFO] Decision saved for merchant %s", merchantID)
+
+		// The decision is already saved, so a failure here is logged rather
+		// than failing the evaluation.
+		if s.reviews != nil && decision.RiskLevel.AtLeast(RiskLevelHigh) {
+			if err := s.reviews.OpenCase(ctx, decision); err != nil {
+				log.Printf("[ERROR] Failed to open review case for merchant %s: %v", merchantID, err)
+			}
+		}
+
+		s.shadowEvaluate(ctx, decision, m, policy, stoppedTier)
 	}
 
 	return decision, nil
@@ -104,31 +301,29 @@ func (s *Service) EvaluateMerchant(ctx context.Context, merchantID uuid.UUID, si
 // SimulateMerchant performs a "what-if" risk evaluation with modified merchant data
 // or custom scoring thresholds. The actual merchant record is not modified.
 //
-// Supported merchant data overrides:
-//   - chargeback_rate (float64)
-//   - account_age_days (float64)
-//   - kyc_verified (bool)
-//   - velocity_multiplier (float64)
-//
-// Supported scoring threshold overrides (in scoring_thresholds map):
-//   - chargeback_excellent (float64)
-//   - chargeback_acceptable (float64)
-//   - chargeback_critical (float64)
-//   - velocity_normal (float64)
-//   - refund_normal (float64)
-//   - refund_elevated (float64)
+// Every scoring input can be overridden, and scoring_thresholds replace any
+// chargeback, account age, velocity or refund band bound (see
+// SimulationOverrides and ScoringThresholds).
 //
 // Example: Test impact of stricter chargeback thresholds:
-//   overrides := map[string]interface{}{
-//       "scoring_thresholds": map[string]interface{}{
-//           "chargeback_excellent": 0.3,  // Lower from 0.5%
-//           "chargeback_critical": 1.0,   // Lower from 1.5%
+//   excellent, critical := 0.3, 1.2 // Lower from 0.5% and 1.5%
+//   overrides := SimulationOverrides{
+//       ScoringThresholds: &ScoringThresholds{
+//           ChargebackExcellent: &excellent,
+//           ChargebackCritical:  &critical,
 //       },
 //   }
 //
-// The simulation result is never persisted to the database.
-func (s *Service) SimulateMerchant(ctx context.Context, merchantID uuid.UUID, overrides map[string]interface{}) (*RiskDecision, error) {
-	log.Printf("[INFO] Simulating merchant %s with %d overrides", merchantID, len(overrides))
+// scoring_mode ("banded" or "continuous") scores the simulation in the given
+// mode so it can be compared against the ruleset's own. An unknown mode
+// returns ErrInvalidScoringMode, and thresholds that leave a factor's bands
+// out of order return OverrideErrors.
+//
+// The result echoes the overrides that were applied and compares the
+// simulation against a baseline: the unmodified merchant scored with the live
+// configuration at the same instant. Neither is persisted to the database.
+func (s *Service) SimulateMerchant(ctx context.Context, merchantID uuid.UUID, overrides SimulationOverrides) (*SimulationResult, error) {
+	log.Printf("[INFO] Simulating merchant %s", merchantID)
 
 	m, err := s.merchantStore.Get(ctx, merchantID)
 	if err != nil {
@@ -136,34 +331,255 @@ func (s *Service) SimulateMerchant(ctx context.Context, merchantID uuid.UUID, ov
 		return nil, fmt.Errorf("failed to get merchant %s: %w", merchantID, err)
 	}
 
+	if err := overrides.validateCodes(s.evaluator.Ruleset()); err != nil {
+		return nil, err
+	}
+
 	simulatedMerchant := *m
-	s.applyOverrides(&simulatedMerchant, overrides)
+	applied := overrides.Apply(&simulatedMerchant)
 
-	evaluator := s.evaluator
-	if thresholds, ok := overrides["scoring_thresholds"].(map[string]interface{}); ok {
-		log.Printf("[INFO] Using custom scoring thresholds for simulation")
-		evaluator = NewEvaluatorWithThresholds(thresholds)
+	scorer, rules, err := s.simulationScorer(&applied)
+	if err != nil {
+		return nil, err
 	}
 
-	totalScore, factors := evaluator.CalculateTotalScore(&simulatedMerchant)
-	tier := s.policy.DeterminePolicyTier(totalScore)
-	reasoning := s.explainer.GenerateReasoning(&simulatedMerchant, factors, tier)
+	evaluatedAt := time.Now()
+	baseline, baselineFactors, err := s.simulateDecision(ctx, m, s.scorer, s.evaluator.Ruleset(), evaluatedAt)
+	if err != nil {
+		return nil, err
+	}
+	decision, factors, err := s.simulateDecision(ctx, &simulatedMerchant, scorer, rules, evaluatedAt)
+	if err != nil {
+		return nil, err
+	}
 
-	decision := &RiskDecision{
-		MerchantID:               merchantID,
+	log.Printf("[INFO] Simulation complete for merchant %s: score=%d, level=%s (baseline score=%d, level=%s)",
+		merchantID, decision.RiskScore, decision.RiskLevel, baseline.RiskScore, baseline.RiskLevel)
+
+	return &SimulationResult{
+		RiskDecision:     decision,
+		Baseline:         baseline,
+		Changes:          compareSimulation(baseline, decision, baselineFactors, factors),
+		AppliedOverrides: applied,
+	}, nil
+}
+
+// simulateDecision scores m with scorer and tiers it under the policy in force
+// at evaluatedAt, with hard stops from rules. Hysteresis and manual overrides
+// do not apply. It also returns the factor scores behind the decision.
+func (s *Service) simulateDecision(ctx context.Context, m *merchant.Merchant, scorer Scorer, rules *Ruleset, evaluatedAt time.Time) (*RiskDecision, FactorScore, error) {
+	policy, override, err := s.resolvePolicy(ctx, m, evaluatedAt)
+	if err != nil {
+		log.Printf("[ERROR] Failed to resolve policy tiers for simulation of merchant %s: %v", m.ID, err)
+		return nil, FactorScore{}, fmt.Errorf("failed to resolve policy tiers: %w", err)
+	}
+
+	history, err := s.trendHistory(ctx, scorer, m.ID, evaluatedAt)
+	if err != nil {
+		log.Printf("[ERROR] Failed to load metric history for simulation of merchant %s: %v", m.ID, err)
+		return nil, FactorScore{}, fmt.Errorf("failed to load metric history: %w", err)
+	}
+
+	totalScore, factors, trends := scoreWithHistory(scorer, m, history, evaluatedAt)
+	scoredTier := policy.DeterminePolicyTier(totalScore)
+	tier, hardStops := ApplyHardStops(rules.HardStops, m, policy, scoredTier)
+
+	explainer := explainerFor(rules)
+	reasoning := explainer.GenerateReasoning(m, totalScore, factors, scoredTier)
+	explainer.ApplyTrends(&reasoning, trends)
+	explainer.ApplyPolicyOverride(&reasoning, override)
+	explainer.ApplyHardStops(&reasoning, hardStops, scoredTier, tier)
+
+	return &RiskDecision{
+		MerchantID:               m.ID,
 		RiskScore:                totalScore,
 		RiskLevel:                tier.RiskLevel,
 		PayoutHoldPeriod:         tier.HoldPeriod,
 		RollingReservePercentage: tier.ReservePercentage,
 		Reasoning:                reasoning,
-		EvaluatedAt:              time.Now(),
+		ModelVersion:             scorer.Version(),
+		PolicyVersion:            policy.Version(),
+		EvaluatedAt:              evaluatedAt,
 		Simulation:               true,
+	}, factors, nil
+}
+
+// simulationScorer applies the scoring_thresholds and scoring_mode overrides
+// to the additive evaluator. Other scorers have no bands, so either override
+// is rejected for them. Thresholds that were not applied are cleared from
+// overrides.
+func (s *Service) simulationScorer(overrides *SimulationOverrides) (Scorer, *Ruleset, error) {
+	evaluator, additive := s.scorer.(*Evaluator)
+	if !additive {
+		fields := OverrideErrors{}
+		if !overrides.ScoringThresholds.Empty() {
+			fields["scoring_thresholds"] = "not supported by scorer " + s.scorer.Version()
+		}
+		if overrides.ScoringMode != nil {
+			fields["scoring_mode"] = "not supported by scorer " + s.scorer.Version()
+		}
+		if len(fields) > 0 {
+			return nil, nil, fields
+		}
+		overrides.ScoringThresholds = nil
+		return s.scorer, s.evaluator.Ruleset(), nil
 	}
 
-	log.Printf("[INFO] Simulation complete for merchant %s: score=%d (original would be different)",
-		merchantID, totalScore)
+	if thresholds := overrides.ScoringThresholds; !thresholds.Empty() {
+		var applied ScoringThresholds
+		evaluator, applied = evaluator.applyThresholds(*thresholds)
+		if err := evaluator.Ruleset().Validate(); err != nil {
+			return nil, nil, OverrideErrors{"scoring_thresholds": strings.ReplaceAll(err.Error(), "\n", "; ")}
+		}
+		overrides.ScoringThresholds = &applied
+		log.Printf("[INFO] Using custom scoring thresholds for simulation")
+	}
+	if overrides.ScoringThresholds.Empty() {
+		overrides.ScoringThresholds = nil
+	}
+	if overrides.ScoringMode != nil {
+		var err error
+		evaluator, err = evaluator.WithMode(*overrides.ScoringMode)
+		if err != nil {
+			return nil, nil, err
+		}
+		log.Printf("[INFO] Using %s scoring mode for simulation", *overrides.ScoringMode)
+	}
 
-	return decision, nil
+	return evaluator, evaluator.Ruleset(), nil
+}
+
+// applyHysteresis keeps the merchant in its previous tier when an upgrade
+// has not yet cleared the configured hysteresis, judged against the
+// merchant's persisted decisions.
+func (s *Service) applyHysteresis(ctx context.Context, merchantID uuid.UUID, policy *PolicyMapper, score int, tier PolicyTier) (PolicyTier, *HysteresisHold, error) {
+	if !s.hysteresis.Enabled() {
+		return tier, nil, nil
+	}
+
+	limit := s.hysteresis.ConsecutiveEvaluations
+	if limit < 1 {
+		limit = 1
+	}
+	recent, err := s.decisionStore.ListRecentByMerchant(ctx, merchantID, limit)
+	if err != nil {
+		return PolicyTier{}, nil, err
+	}
+
+	held, hold := s.hysteresis.Apply(policy, score, tier, recent)
+	return held, hold, nil
+}
+
+// activeManualOverride returns the analyst override in force for the merchant
+// at the given time, if any.
+func (s *Service) activeManualOverride(ctx context.Context, merchantID uuid.UUID, at time.Time) (*ManualOverride, error) {
+	if s.manual == nil {
+		return nil, nil
+	}
+	return s.manual.GetActive(ctx, merchantID, at)
+}
+
+// trendHistory loads the metric snapshots the scorer's trend factor needs. It
+// returns nil when no history store is configured or the scorer does not
+// score trends.
+func (s *Service) trendHistory(ctx context.Context, scorer Scorer, merchantID uuid.UUID, at time.Time) ([]merchant.MetricSnapshot, error) {
+	trendScorer, ok := scorer.(TrendScorer)
+	if !ok || s.history == nil {
+		return nil, nil
+	}
+
+	lookback := trendScorer.TrendLookback()
+	if lookback == 0 {
+		return nil, nil
+	}
+
+	return s.history.ListByMerchantSince(ctx, merchantID, at.Add(-lookback))
+}
+
+// scoreWithHistory scores m and, when the scorer supports it, adds trend
+// points from the merchant's history. The total stays capped at 100.
+func scoreWithHistory(scorer Scorer, m *merchant.Merchant, history []merchant.MetricSnapshot, at time.Time) (int, FactorScore, []TrendMatch) {
+	total, factors := scorer.Score(m)
+
+	trendScorer, ok := scorer.(TrendScorer)
+	if !ok || len(history) == 0 {
+		return total, factors, nil
+	}
+
+	points, trends := trendScorer.ScoreTrends(m, history, at)
+	factors.Trend = points
+	total += points
+	if total > 100 {
+		total = 100
+	}
+
+	return total, factors, trends
+}
+
+// policyAt returns the policy mapper for the tier table in force at the given
+// time. The built-in tiers apply when no table is configured or active.
+func (s *Service) policyAt(ctx context.Context, at time.Time) (*PolicyMapper, error) {
+	if s.tierTables == nil {
+		return s.policy, nil
+	}
+
+	table, err := s.tierTables.GetActiveAt(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		return s.policy, nil
+	}
+
+	return NewPolicyMapperFromTiers(table.Version, table.Tiers), nil
+}
+
+// resolvePolicy combines the tier table in force at the given time with the
+// most specific market override for the merchant, if any.
+func (s *Service) resolvePolicy(ctx context.Context, m *merchant.Merchant, at time.Time) (*PolicyMapper, *AppliedPolicyOverride, error) {
+	policy, err := s.policyAt(ctx, at)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.overrides == nil {
+		return policy, nil, nil
+	}
+
+	overrides, err := s.overrides.ListActive(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list policy overrides: %w", err)
+	}
+
+	override := SelectPolicyOverride(overrides, m.Country, m.Industry)
+	if override == nil {
+		return policy, nil, nil
+	}
+
+	log.Printf("[INFO] Applying policy override %s to merchant %s", override.Name, m.ID)
+	return override.Apply(policy), &AppliedPolicyOverride{
+		ID:                  override.ID,
+		Name:                override.Name,
+		Country:             override.Country,
+		Industry:            override.Industry,
+		Mode:                override.Mode,
+		HoldAdjustmentSteps: override.HoldAdjustmentSteps,
+		ReserveAdjustment:   override.ReserveAdjustment,
+	}, nil
+}
+
+// explainerFor builds an explainer that describes scores against rules.
+// Reasoning is always written in the default language, so stored decisions
+// do not depend on who triggered them; callers localize it with
+// Reasoning.Localize.
+func explainerFor(rules *Ruleset) *Explainer {
+	return NewExplainerFromRuleset(rules, DefaultLanguage)
+}
+
+// ModelVersion returns the version of the scoring model currently applied to
+// new decisions.
+func (s *Service) ModelVersion() string {
+	return s.scorer.Version()
 }
 
 func (s *Service) GetMerchantProfile(ctx context.Context, merchantID uuid.UUID) (*merchant.MerchantProfile, error) {
@@ -197,32 +613,64 @@ func (s *Service) GetMerchantProfile(ctx context.Context, merchantID uuid.UUID)
 		},
 	}
 
-	if latestDecision != nil {
-		profile.CurrentPolicy = &merchant.PolicyInfo{
-			RiskScore:                latestDecision.RiskScore,
-			PayoutHoldPeriod:         string(latestDecision.PayoutHoldPeriod),
-			RollingReservePercentage: latestDecision.RollingReservePercentage,
-			LastEvaluatedAt:          latestDecision.EvaluatedAt,
+	effective := latestDecision
+	if latestDecision != nil && s.approvals != nil &&
+		latestDecision.Status != "" && latestDecision.Status != DecisionEffective {
+		if latestDecision.Status == DecisionPendingApproval {
+			profile.PendingPolicy = policyInfo(latestDecision)
+		}
+		if effective, err = s.approvals.GetLatestEffectiveByMerchant(ctx, merchantID); err != nil {
+			return nil, fmt.Errorf("failed to get effective decision: %w", err)
 		}
+		if effective == nil {
+			profile.CurrentPolicy = &merchant.PolicyInfo{
+				PayoutHoldPeriod:         string(s.approval.DefaultHoldPeriod),
+				RollingReservePercentage: s.approval.DefaultReservePercentage,
+				Status:                   "SAFE_DEFAULT",
+			}
+		}
+	}
+	if effective != nil {
+		profile.CurrentPolicy = policyInfo(effective)
+	}
+
+	manual, err := s.activeManualOverride(ctx, merchantID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manual override: %w", err)
+	}
+	if manual != nil {
+		profile.ManualOverride = &merchant.ManualOverrideInfo{
+			ID:                manual.ID,
+			HoldPeriod:        string(manual.HoldPeriod),
+			ReservePercentage: manual.ReservePercentage,
+			Justification:     manual.Justification,
+			CreatedBy:         manual.CreatedBy,
+			ExpiresAt:         manual.ExpiresAt,
+		}
+		// The latest decision may predate the override; the override wins. A
+		// merchant never evaluated is still paid out under the override.
+		if profile.CurrentPolicy == nil {
+			profile.CurrentPolicy = &merchant.PolicyInfo{Status: "MANUAL_OVERRIDE"}
+		}
+		profile.CurrentPolicy.PayoutHoldPeriod = string(manual.HoldPeriod)
+		profile.CurrentPolicy.RollingReservePercentage = manual.ReservePercentage
 	}
 
 	return profile, nil
 }
 
-func (s *Service) applyOverrides(m *merchant.Merchant, overrides map[string]interface{}) {
-	if val, ok := overrides["chargeback_rate"].(float64); ok {
-		m.ChargebackRate = decimal.NewFromFloat(val)
-	}
-	if val, ok := overrides["account_age_days"].(float64); ok {
-		m.AccountAgeDays = int(val)
-	}
-	if val, ok := overrides["kyc_verified"].(bool); ok {
-		m.KYCVerified = val
-		if val && m.KYCLevel == "NONE" {
-			m.KYCLevel = "FULL"
-		}
+func policyInfo(d *RiskDecision) *merchant.PolicyInfo {
+	status := d.Status
+	if status == "" {
+		status = DecisionEffective
 	}
-	if val, ok := overrides["velocity_multiplier"].(float64); ok {
-		m.VelocityMultiplier = decimal.NewFromFloat(val)
+	return &merchant.PolicyInfo{
+		DecisionID:               &d.ID,
+		RiskScore:                d.RiskScore,
+		RiskLevel:                string(d.RiskLevel),
+		PayoutHoldPeriod:         string(d.PayoutHoldPeriod),
+		RollingReservePercentage: d.RollingReservePercentage,
+		LastEvaluatedAt:          d.EvaluatedAt,
+		Status:                   string(status),
 	}
 }
@@ -25,6 +25,7 @@ func (m *mockMerchantRepository) Get(ctx context.Context, id uuid.UUID) (*mercha
 type mockDecisionRepository struct {
 	createDecision          func(ctx context.Context, decision *RiskDecision) error
 	getLatestByMerchant     func(ctx context.Context, merchantID uuid.UUID) (*RiskDecision, error)
+	listRecentByMerchant    func(ctx context.Context, merchantID uuid.UUID, limit int) ([]RiskDecision, error)
 	bulkCreate              func(ctx context.Context, decisions []RiskDecision) error
 }
 
@@ -42,6 +43,13 @@ func (m *mockDecisionRepository) GetLatestByMerchant(ctx context.Context, mercha
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockDecisionRepository) ListRecentByMerchant(ctx context.Context, merchantID uuid.UUID, limit int) ([]RiskDecision, error) {
+	if m.listRecentByMerchant != nil {
+		return m.listRecentByMerchant(ctx, merchantID, limit)
+	}
+	return nil, nil
+}
+
 func (m *mockDecisionRepository) BulkCreate(ctx context.Context, decisions []RiskDecision) error {
 	if m.bulkCreate != nil {
 		return m.bulkCreate(ctx, decisions)
@@ -102,6 +110,9 @@ func TestEvaluateMerchant(t *testing.T) {
 		if decision.RiskScore != 5 {
 			t.Errorf("expected risk score 5 (only category risk), got %d", decision.RiskScore)
 		}
+		if decision.ModelVersion != DefaultRuleset().Version {
+			t.Errorf("expected model version %s, got %s", DefaultRuleset().Version, decision.ModelVersion)
+		}
 	})
 
 	t.Run("successful evaluation - with simulation", func(t *testing.T) {
@@ -213,6 +224,87 @@ func TestEvaluateMerchant(t *testing.T) {
 	})
 }
 
+func TestEvaluateMerchantTierTables(t *testing.T) {
+	merchantID := uuid.New()
+	testMerchant := &merchant.Merchant{
+		ID:                 merchantID,
+		Industry:           "RETAIL",
+		AccountAgeDays:     800,
+		ChargebackRate:     decimal.NewFromFloat(0.3),
+		RefundRate:         decimal.NewFromFloat(2.0),
+		VelocityMultiplier: decimal.NewFromFloat(1.2),
+		KYCVerified:        true,
+		KYCLevel:           "ENHANCED",
+	}
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return testMerchant, nil
+		},
+	}
+
+	t.Run("uses the tier table active at evaluation time", func(t *testing.T) {
+		strictTiers := DefaultPolicyTiers()
+		strictTiers[0].HoldPeriod = HoldPeriod7Days
+
+		var resolvedAt time.Time
+		tierTables := &mockTierTableStore{
+			activeAt: func(ctx context.Context, at time.Time) (*PolicyTierTable, error) {
+				resolvedAt = at
+				return &PolicyTierTable{Version: "2025-Q1", Tiers: strictTiers}, nil
+			},
+		}
+
+		service := NewService(merchantStore, &mockDecisionRepository{}, WithTierTables(tierTables))
+		decision, err := service.EvaluateMerchant(context.Background(), merchantID, true)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if decision.PayoutHoldPeriod != HoldPeriod7Days {
+			t.Errorf("expected 7_DAYS hold from active table, got %s", decision.PayoutHoldPeriod)
+		}
+		if decision.PolicyVersion != "2025-Q1" {
+			t.Errorf("expected policy version 2025-Q1, got %s", decision.PolicyVersion)
+		}
+		if !resolvedAt.Equal(decision.EvaluatedAt) {
+			t.Errorf("expected tiers resolved at %v, got %v", decision.EvaluatedAt, resolvedAt)
+		}
+	})
+
+	t.Run("falls back to built-in tiers when none is active", func(t *testing.T) {
+		service := NewService(merchantStore, &mockDecisionRepository{}, WithTierTables(&mockTierTableStore{}))
+		decision, err := service.EvaluateMerchant(context.Background(), merchantID, true)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if decision.PayoutHoldPeriod != HoldPeriodImmediate {
+			t.Errorf("expected IMMEDIATE hold, got %s", decision.PayoutHoldPeriod)
+		}
+		if decision.PolicyVersion != NewPolicyMapper().Version() {
+			t.Errorf("expected built-in policy version, got %s", decision.PolicyVersion)
+		}
+	})
+
+	t.Run("tier lookup failure", func(t *testing.T) {
+		tierTables := &mockTierTableStore{
+			activeAt: func(ctx context.Context, at time.Time) (*PolicyTierTable, error) {
+				return nil, errors.New("database error")
+			},
+		}
+
+		service := NewService(merchantStore, &mockDecisionRepository{}, WithTierTables(tierTables))
+		decision, err := service.EvaluateMerchant(context.Background(), merchantID, false)
+
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if decision != nil {
+			t.Error("expected nil decision when tiers cannot be resolved")
+		}
+	})
+}
+
 func TestSimulateMerchant(t *testing.T) {
 	merchantID := uuid.New()
 	baseMerchant := &merchant.Merchant{
@@ -236,8 +328,8 @@ func TestSimulateMerchant(t *testing.T) {
 		decisionStore := &mockDecisionRepository{}
 
 		service := NewService(merchantStore, decisionStore)
-		overrides := map[string]interface{}{
-			"chargeback_rate": 4.5,
+		overrides := SimulationOverrides{
+			ChargebackRate: ptr(decimal.NewFromFloat(4.5)),
 		}
 
 		decision, err := service.SimulateMerchant(context.Background(), merchantID, overrides)
@@ -262,8 +354,8 @@ func TestSimulateMerchant(t *testing.T) {
 		decisionStore := &mockDecisionRepository{}
 
 		service := NewService(merchantStore, decisionStore)
-		overrides := map[string]interface{}{
-			"account_age_days": 15.0,
+		overrides := SimulationOverrides{
+			AccountAgeDays: ptr(15),
 		}
 
 		decision, err := service.SimulateMerchant(context.Background(), merchantID, overrides)
@@ -285,8 +377,8 @@ func TestSimulateMerchant(t *testing.T) {
 		decisionStore := &mockDecisionRepository{}
 
 		service := NewService(merchantStore, decisionStore)
-		overrides := map[string]interface{}{
-			"velocity_multiplier": 8.0,
+		overrides := SimulationOverrides{
+			VelocityMultiplier: ptr(decimal.NewFromFloat(8.0)),
 		}
 
 		decision, err := service.SimulateMerchant(context.Background(), merchantID, overrides)
@@ -321,11 +413,11 @@ func TestSimulateMerchant(t *testing.T) {
 
 		service := NewService(merchantStore, decisionStore)
 
-		baseDecision, _ := service.SimulateMerchant(context.Background(), merchantID, map[string]interface{}{})
+		baseDecision, _ := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{})
 		baseScore := baseDecision.RiskScore
 
-		overrides := map[string]interface{}{
-			"kyc_verified": true,
+		overrides := SimulationOverrides{
+			KYCVerified: ptr(true),
 		}
 		decision, err := service.SimulateMerchant(context.Background(), merchantID, overrides)
 
@@ -346,10 +438,10 @@ func TestSimulateMerchant(t *testing.T) {
 		decisionStore := &mockDecisionRepository{}
 
 		service := NewService(merchantStore, decisionStore)
-		overrides := map[string]interface{}{
-			"chargeback_rate":     4.5,
-			"account_age_days":    15.0,
-			"velocity_multiplier": 8.0,
+		overrides := SimulationOverrides{
+			ChargebackRate:     ptr(decimal.NewFromFloat(4.5)),
+			AccountAgeDays:     ptr(15),
+			VelocityMultiplier: ptr(decimal.NewFromFloat(8.0)),
 		}
 
 		decision, err := service.SimulateMerchant(context.Background(), merchantID, overrides)
@@ -371,7 +463,7 @@ func TestSimulateMerchant(t *testing.T) {
 		decisionStore := &mockDecisionRepository{}
 
 		service := NewService(merchantStore, decisionStore)
-		decision, err := service.SimulateMerchant(context.Background(), merchantID, map[string]interface{}{})
+		decision, err := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{})
 
 		if err == nil {
 			t.Fatal("expected error, got nil")
@@ -513,9 +605,7 @@ func TestGetMerchantProfile(t *testing.T) {
 	})
 }
 
-func TestApplyOverrides(t *testing.T) {
-	service := NewService(nil, nil)
-
+func TestSimulationOverridesApply(t *testing.T) {
 	t.Run("apply all overrides", func(t *testing.T) {
 		m := &merchant.Merchant{
 			ChargebackRate:     decimal.NewFromFloat(0.5),
@@ -525,14 +615,14 @@ func TestApplyOverrides(t *testing.T) {
 			VelocityMultiplier: decimal.NewFromFloat(1.0),
 		}
 
-		overrides := map[string]interface{}{
-			"chargeback_rate":     2.5,
-			"account_age_days":    500.0,
-			"kyc_verified":        true,
-			"velocity_multiplier": 3.0,
+		overrides := SimulationOverrides{
+			ChargebackRate:     ptr(decimal.NewFromFloat(2.5)),
+			AccountAgeDays:     ptr(500),
+			KYCVerified:        ptr(true),
+			VelocityMultiplier: ptr(decimal.NewFromFloat(3.0)),
 		}
 
-		service.applyOverrides(m, overrides)
+		overrides.Apply(m)
 
 		if m.ChargebackRate.InexactFloat64() != 2.5 {
 			t.Errorf("expected chargeback rate 2.5, got %v", m.ChargebackRate.InexactFloat64())
@@ -557,11 +647,11 @@ func TestApplyOverrides(t *testing.T) {
 			KYCLevel:    "NONE",
 		}
 
-		overrides := map[string]interface{}{
-			"kyc_verified": true,
+		overrides := SimulationOverrides{
+			KYCVerified: ptr(true),
 		}
 
-		service.applyOverrides(m, overrides)
+		applied := overrides.Apply(m)
 
 		if !m.KYCVerified {
 			t.Error("expected KYC verified to be true")
@@ -569,6 +659,12 @@ func TestApplyOverrides(t *testing.T) {
 		if m.KYCLevel != "FULL" {
 			t.Errorf("expected KYC level to be updated to FULL, got %s", m.KYCLevel)
 		}
+		if applied.KYCLevel == nil || *applied.KYCLevel != "FULL" {
+			t.Errorf("expected the implied KYC level to be reported as applied, got %v", applied.KYCLevel)
+		}
+		if overrides.KYCLevel != nil {
+			t.Error("requested overrides should not be modified")
+		}
 	})
 
 	t.Run("partial overrides", func(t *testing.T) {
@@ -578,11 +674,11 @@ func TestApplyOverrides(t *testing.T) {
 			VelocityMultiplier: decimal.NewFromFloat(1.0),
 		}
 
-		overrides := map[string]interface{}{
-			"chargeback_rate": 1.5,
+		overrides := SimulationOverrides{
+			ChargebackRate: ptr(decimal.NewFromFloat(1.5)),
 		}
 
-		service.applyOverrides(original, overrides)
+		overrides.Apply(original)
 
 		if original.ChargebackRate.InexactFloat64() != 1.5 {
 			t.Error("chargeback rate should be overridden")
@@ -602,7 +698,7 @@ func TestApplyOverrides(t *testing.T) {
 			VelocityMultiplier: decimal.NewFromFloat(1.0),
 		}
 
-		service.applyOverrides(original, map[string]interface{}{})
+		SimulationOverrides{}.Apply(original)
 
 		if original.ChargebackRate.InexactFloat64() != 0.5 {
 			t.Error("chargeback rate should not change")
@@ -612,24 +708,78 @@ func TestApplyOverrides(t *testing.T) {
 		}
 	})
 
-	t.Run("invalid type overrides ignored", func(t *testing.T) {
-		original := &merchant.Merchant{
-			ChargebackRate: decimal.NewFromFloat(0.5),
-			AccountAgeDays: 100,
-		}
-
+	t.Run("invalid type overrides rejected", func(t *testing.T) {
 		overrides := map[string]interface{}{
 			"chargeback_rate":  "not a number",
 			"account_age_days": "also not a number",
 		}
 
-		service.applyOverrides(original, overrides)
+		_, err := ParseSimulationOverrides(overrides)
 
-		if original.ChargebackRate.InexactFloat64() != 0.5 {
-			t.Error("invalid overrides should be ignored")
+		var fields OverrideErrors
+		if !errors.As(err, &fields) {
+			t.Fatalf("expected OverrideErrors, got %v", err)
 		}
-		if original.AccountAgeDays != 100 {
-			t.Error("invalid overrides should be ignored")
+		if fields["chargeback_rate"] != "must be a number" || fields["account_age_days"] != "must be a non-negative integer" {
+			t.Errorf("unexpected field errors %v", fields)
 		}
 	})
 }
+
+type mockReviewQueue struct {
+	opened []*RiskDecision
+}
+
+func (m *mockReviewQueue) OpenCase(ctx context.Context, decision *RiskDecision) error {
+	m.opened = append(m.opened, decision)
+	return nil
+}
+
+func TestEvaluateMerchantReviewQueue(t *testing.T) {
+	highRisk := &merchant.Merchant{
+		ID:                   uuid.New(),
+		Industry:             "TRAVEL",
+		AccountAgeDays:       10,
+		TransactionVolume30d: decimal.NewFromFloat(20000),
+		ChargebackRate:       decimal.NewFromFloat(3.5),
+		VelocityMultiplier:   decimal.NewFromFloat(4.0),
+		RefundRate:           decimal.NewFromFloat(7.0),
+	}
+	lowRisk := &merchant.Merchant{
+		ID:                 uuid.New(),
+		Industry:           "RETAIL",
+		AccountAgeDays:     800,
+		ChargebackRate:     decimal.NewFromFloat(0.3),
+		VelocityMultiplier: decimal.NewFromFloat(1.0),
+		RefundRate:         decimal.NewFromFloat(1.0),
+		KYCVerified:        true,
+		KYCLevel:           "ENHANCED",
+	}
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			if id == highRisk.ID {
+				return highRisk, nil
+			}
+			return lowRisk, nil
+		},
+	}
+
+	queue := &mockReviewQueue{}
+	service := NewService(merchantStore, &mockDecisionRepository{}, WithReviewQueue(queue))
+
+	for _, eval := range []struct {
+		id         uuid.UUID
+		simulation bool
+	}{{highRisk.ID, true}, {lowRisk.ID, false}, {highRisk.ID, false}} {
+		if _, err := service.EvaluateMerchant(context.Background(), eval.id, eval.simulation); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(queue.opened) != 1 {
+		t.Fatalf("expected one case for the persisted high-risk decision, got %d", len(queue.opened))
+	}
+	if queue.opened[0].MerchantID != highRisk.ID || queue.opened[0].RiskLevel != RiskLevelCritical {
+		t.Errorf("unexpected case decision %+v", queue.opened[0])
+	}
+}
@@ -0,0 +1,283 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+// Challenger is a candidate scoring configuration evaluated in shadow next to
+// the champion. Its results are stored apart from decisions and never affect
+// payouts. Ruleset supplies the challenger's hard stops; when nil the
+// champion's apply.
+type Challenger struct {
+	Scorer  Scorer
+	Ruleset *Ruleset
+}
+
+// NewRulesetChallenger scores with the additive model and hard stops of rs.
+func NewRulesetChallenger(rs *Ruleset) Challenger {
+	return Challenger{Scorer: NewEvaluatorFromRuleset(rs), Ruleset: rs}
+}
+
+// Name identifies the challenger in shadow results. It is the version of its
+// scoring model.
+func (c Challenger) Name() string {
+	return c.Scorer.Version()
+}
+
+// ShadowDecision records how a challenger scored a merchant alongside the
+// champion decision persisted for the same evaluation. Both sides are
+// compared after hard stops but before hysteresis and manual overrides, so
+// differences come from the scoring configuration alone.
+type ShadowDecision struct {
+	ID                          uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	DecisionID                  uuid.UUID       `json:"decision_id" gorm:"type:uuid;not null"`
+	MerchantID                  uuid.UUID       `json:"merchant_id" gorm:"type:uuid;not null"`
+	Challenger                  string          `json:"challenger" gorm:"not null"`
+	ChampionVersion             string          `json:"champion_version" gorm:"not null"`
+	ChampionScore               int             `json:"champion_score" gorm:"not null"`
+	ChampionRiskLevel           RiskLevel       `json:"champion_risk_level" gorm:"not null"`
+	ChampionHoldPeriod          HoldPeriod      `json:"champion_hold_period" gorm:"not null"`
+	ChampionReservePercentage   int             `json:"champion_reserve_percentage" gorm:"not null"`
+	ChallengerScore             int             `json:"challenger_score" gorm:"not null"`
+	ChallengerRiskLevel         RiskLevel       `json:"challenger_risk_level" gorm:"not null"`
+	ChallengerHoldPeriod        HoldPeriod      `json:"challenger_hold_period" gorm:"not null"`
+	ChallengerReservePercentage int             `json:"challenger_reserve_percentage" gorm:"not null"`
+	TransactionVolume30d        decimal.Decimal `json:"transaction_volume_30d" gorm:"type:decimal(15,2);not null"`
+	EvaluatedAt                 time.Time       `json:"evaluated_at" gorm:"not null"`
+}
+
+func (ShadowDecision) TableName() string {
+	return "shadow_decisions"
+}
+
+// ShadowCell aggregates the shadow decisions of one challenger that moved a
+// merchant from one champion risk level to one challenger risk level.
+// Reserve amounts are volume times reserve percentage.
+type ShadowCell struct {
+	Challenger          string
+	ChampionRiskLevel   RiskLevel
+	ChallengerRiskLevel RiskLevel
+	Count               int64
+	ScoreDelta          int64
+	Volume              decimal.Decimal
+	HoldChangedVolume   decimal.Decimal
+	ChampionReserve     decimal.Decimal
+	ChallengerReserve   decimal.Decimal
+}
+
+type ShadowRepository interface {
+	CreateShadowDecisions(ctx context.Context, decisions []ShadowDecision) error
+	// SummarizeShadowDecisions groups shadow decisions evaluated in [from, to)
+	// by challenger and champion and challenger risk level. An empty
+	// challenger includes all of them.
+	SummarizeShadowDecisions(ctx context.Context, challenger string, from, to time.Time) ([]ShadowCell, error)
+}
+
+// WithChallengers scores every persisted evaluation with each challenger as
+// well and stores the results in shadows.
+func WithChallengers(shadows ShadowRepository, challengers ...Challenger) Option {
+	return func(s *Service) {
+		s.shadows = shadows
+		s.challengers = challengers
+	}
+}
+
+// Challengers returns the names of the configured challengers.
+func (s *Service) Challengers() []string {
+	names := make([]string, 0, len(s.challengers))
+	for _, c := range s.challengers {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+// shadowEvaluate scores m with each challenger under the policy the champion
+// used and records the results against decision. champion is the tier the
+// champion reached before hysteresis and manual overrides. Failures are
+// logged: shadow scoring must never fail a live evaluation.
+func (s *Service) shadowEvaluate(ctx context.Context, decision *RiskDecision, m *merchant.Merchant, policy *PolicyMapper, champion PolicyTier) {
+	if s.shadows == nil || len(s.challengers) == 0 {
+		return
+	}
+
+	shadows := make([]ShadowDecision, 0, len(s.challengers))
+	for _, c := range s.challengers {
+		history, err := s.trendHistory(ctx, c.Scorer, m.ID, decision.EvaluatedAt)
+		if err != nil {
+			log.Printf("[ERROR] Failed to load metric history for challenger %s on merchant %s: %v", c.Name(), m.ID, err)
+			continue
+		}
+
+		rules := c.Ruleset
+		if rules == nil {
+			rules = s.evaluator.Ruleset()
+		}
+		score, _, _ := scoreWithHistory(c.Scorer, m, history, decision.EvaluatedAt)
+		tier, _ := ApplyHardStops(rules.HardStops, m, policy, policy.DeterminePolicyTier(score))
+
+		shadows = append(shadows, ShadowDecision{
+			DecisionID:                  decision.ID,
+			MerchantID:                  m.ID,
+			Challenger:                  c.Name(),
+			ChampionVersion:             decision.ModelVersion,
+			ChampionScore:               decision.RiskScore,
+			ChampionRiskLevel:           champion.RiskLevel,
+			ChampionHoldPeriod:          champion.HoldPeriod,
+			ChampionReservePercentage:   champion.ReservePercentage,
+			ChallengerScore:             score,
+			ChallengerRiskLevel:         tier.RiskLevel,
+			ChallengerHoldPeriod:        tier.HoldPeriod,
+			ChallengerReservePercentage: tier.ReservePercentage,
+			TransactionVolume30d:        m.TransactionVolume30d,
+			EvaluatedAt:                 decision.EvaluatedAt,
+		})
+
+		if tier.RiskLevel != champion.RiskLevel {
+			log.Printf("[INFO] Challenger %s disagrees on merchant %s: %s (score=%d) vs champion %s (score=%d)",
+				c.Name(), m.ID, tier.RiskLevel, score, champion.RiskLevel, decision.RiskScore)
+		}
+	}
+
+	if len(shadows) == 0 {
+		return
+	}
+	if err := s.shadows.CreateShadowDecisions(ctx, shadows); err != nil {
+		log.Printf("[ERROR] Failed to save shadow decisions for merchant %s: %v", m.ID, err)
+	}
+}
+
+// ShadowReport compares a challenger with the champion over a time window.
+type ShadowReport struct {
+	Challenger  string    `json:"challenger"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Evaluations int64     `json:"evaluations"`
+	// AgreementRate is the share of evaluations where both assigned the same
+	// risk level.
+	AgreementRate float64 `json:"agreement_rate"`
+	Agreements    int64   `json:"agreements"`
+	// Escalations are evaluations the challenger placed in a stricter tier;
+	// de-escalations in a more lenient one.
+	Escalations    int64               `json:"escalations"`
+	Deescalations  int64               `json:"deescalations"`
+	MeanScoreDelta float64             `json:"mean_score_delta"`
+	TierMigration  TierMigrationMatrix `json:"tier_migration"`
+	VolumeImpact   VolumeImpact        `json:"volume_impact"`
+}
+
+// TierMigrationMatrix counts evaluations by baseline risk level (rows) and
+// candidate risk level (columns), both in Levels order, from least to most
+// severe.
+type TierMigrationMatrix struct {
+	Levels []RiskLevel `json:"levels"`
+	Counts [][]int64   `json:"counts"`
+}
+
+func NewTierMigrationMatrix() TierMigrationMatrix {
+	levels := []RiskLevel{RiskLevelLow, RiskLevelMediumLow, RiskLevelMedium, RiskLevelHigh, RiskLevelCritical}
+	counts := make([][]int64, len(levels))
+	for i := range levels {
+		counts[i] = make([]int64, len(levels))
+	}
+	return TierMigrationMatrix{Levels: levels, Counts: counts}
+}
+
+// Add counts n evaluations that moved from one risk level to another.
+// Unknown levels are ignored.
+func (m TierMigrationMatrix) Add(from, to RiskLevel, n int64) {
+	row, rowKnown := riskLevelRank[from]
+	col, colKnown := riskLevelRank[to]
+	if rowKnown && colKnown {
+		m.Counts[row][col] += n
+	}
+}
+
+// VolumeImpact measures the 30-day transaction volume behind the
+// evaluations and how much of it the challenger would treat differently.
+type VolumeImpact struct {
+	TotalVolume       decimal.Decimal `json:"total_volume"`
+	AgreedVolume      decimal.Decimal `json:"agreed_volume"`
+	EscalatedVolume   decimal.Decimal `json:"escalated_volume"`
+	DeescalatedVolume decimal.Decimal `json:"deescalated_volume"`
+	// HoldChangedVolume is the volume whose payout hold period would change.
+	HoldChangedVolume decimal.Decimal `json:"hold_changed_volume"`
+	ChampionReserve   decimal.Decimal `json:"champion_reserve"`
+	ChallengerReserve decimal.Decimal `json:"challenger_reserve"`
+	ReserveDelta      decimal.Decimal `json:"reserve_delta"`
+}
+
+// ShadowReports compares each challenger with the champion over the
+// evaluations in [from, to). An empty challenger reports on every challenger
+// with shadow decisions in the window.
+func (s *Service) ShadowReports(ctx context.Context, challenger string, from, to time.Time) ([]ShadowReport, error) {
+	if s.shadows == nil {
+		return []ShadowReport{}, nil
+	}
+
+	cells, err := s.shadows.SummarizeShadowDecisions(ctx, challenger, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize shadow decisions: %w", err)
+	}
+	return BuildShadowReports(cells, from, to), nil
+}
+
+// BuildShadowReports folds aggregated shadow decisions into one report per
+// challenger, ordered by challenger name.
+func BuildShadowReports(cells []ShadowCell, from, to time.Time) []ShadowReport {
+	byChallenger := make(map[string]*ShadowReport)
+	scoreDeltas := make(map[string]int64)
+	for _, cell := range cells {
+		report, ok := byChallenger[cell.Challenger]
+		if !ok {
+			report = &ShadowReport{Challenger: cell.Challenger, From: from, To: to,
+				TierMigration: NewTierMigrationMatrix()}
+			byChallenger[cell.Challenger] = report
+		}
+
+		report.TierMigration.Add(cell.ChampionRiskLevel, cell.ChallengerRiskLevel, cell.Count)
+
+		impact := &report.VolumeImpact
+		report.Evaluations += cell.Count
+		scoreDeltas[cell.Challenger] += cell.ScoreDelta
+		impact.TotalVolume = impact.TotalVolume.Add(cell.Volume)
+		impact.HoldChangedVolume = impact.HoldChangedVolume.Add(cell.HoldChangedVolume)
+		impact.ChampionReserve = impact.ChampionReserve.Add(cell.ChampionReserve)
+		impact.ChallengerReserve = impact.ChallengerReserve.Add(cell.ChallengerReserve)
+
+		champion, candidate := riskLevelRank[cell.ChampionRiskLevel], riskLevelRank[cell.ChallengerRiskLevel]
+		switch {
+		case candidate > champion:
+			report.Escalations += cell.Count
+			impact.EscalatedVolume = impact.EscalatedVolume.Add(cell.Volume)
+		case candidate < champion:
+			report.Deescalations += cell.Count
+			impact.DeescalatedVolume = impact.DeescalatedVolume.Add(cell.Volume)
+		default:
+			report.Agreements += cell.Count
+			impact.AgreedVolume = impact.AgreedVolume.Add(cell.Volume)
+		}
+	}
+
+	reports := make([]ShadowReport, 0, len(byChallenger))
+	for name, report := range byChallenger {
+		if report.Evaluations > 0 {
+			report.AgreementRate = float64(report.Agreements) / float64(report.Evaluations)
+			report.MeanScoreDelta = float64(scoreDeltas[name]) / float64(report.Evaluations)
+		}
+		report.VolumeImpact.ReserveDelta = report.VolumeImpact.ChallengerReserve.Sub(report.VolumeImpact.ChampionReserve)
+		reports = append(reports, *report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Challenger < reports[j].Challenger
+	})
+
+	return reports
+}
@@ -0,0 +1,155 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+type mockShadowRepository struct {
+	created []ShadowDecision
+	err     error
+}
+
+func (m *mockShadowRepository) CreateShadowDecisions(ctx context.Context, decisions []ShadowDecision) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.created = append(m.created, decisions...)
+	return nil
+}
+
+func (m *mockShadowRepository) SummarizeShadowDecisions(ctx context.Context, challenger string, from, to time.Time) ([]ShadowCell, error) {
+	return nil, nil
+}
+
+// fixedScorer scores every merchant the same.
+type fixedScorer struct {
+	version string
+	score   int
+}
+
+func (s fixedScorer) Score(m *merchant.Merchant) (int, FactorScore) {
+	return s.score, FactorScore{}
+}
+
+func (s fixedScorer) Version() string {
+	return s.version
+}
+
+func TestShadowEvaluate(t *testing.T) {
+	m := &merchant.Merchant{ID: uuid.New(), Industry: "RETAIL", AccountAgeDays: 800, KYCVerified: true, KYCLevel: "ENHANCED",
+		TransactionVolume30d: decimal.NewFromFloat(50000),
+		ChargebackRate:       decimal.NewFromFloat(0.3), VelocityMultiplier: decimal.NewFromFloat(1.0), RefundRate: decimal.NewFromFloat(1.0)}
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) { return m, nil },
+	}
+	strict := Challenger{Scorer: fixedScorer{version: "strict-v2", score: 90}}
+	agreeing := NewRulesetChallenger(DefaultRuleset().Clone())
+	agreeing.Ruleset.Version = "builtin-v1-copy"
+
+	t.Run("challengers are recorded next to the champion", func(t *testing.T) {
+		shadows := &mockShadowRepository{}
+		service := NewService(merchantStore, &mockDecisionRepository{}, WithChallengers(shadows, strict, agreeing))
+
+		decision, err := service.EvaluateMerchant(context.Background(), m.ID, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if decision.RiskLevel != RiskLevelLow || decision.ModelVersion != "builtin-v3" {
+			t.Fatalf("expected champion LOW decision from builtin-v3, got %s from %s", decision.RiskLevel, decision.ModelVersion)
+		}
+		if len(shadows.created) != 2 {
+			t.Fatalf("expected 2 shadow decisions, got %d", len(shadows.created))
+		}
+
+		shadow := shadows.created[0]
+		if shadow.Challenger != "strict-v2" || shadow.ChallengerScore != 90 || shadow.ChallengerRiskLevel != RiskLevelCritical {
+			t.Errorf("expected strict-v2 to score CRITICAL, got %+v", shadow)
+		}
+		if shadow.ChampionVersion != "builtin-v3" || shadow.ChampionScore != decision.RiskScore ||
+			shadow.ChampionRiskLevel != decision.RiskLevel || !shadow.TransactionVolume30d.Equal(m.TransactionVolume30d) {
+			t.Errorf("expected champion side to match the decision, got %+v", shadow)
+		}
+
+		copied := shadows.created[1]
+		if copied.Challenger != "builtin-v1-copy" || copied.ChallengerScore != decision.RiskScore {
+			t.Errorf("expected identical ruleset to agree with the champion, got %+v", copied)
+		}
+	})
+
+	t.Run("simulations are not shadowed", func(t *testing.T) {
+		shadows := &mockShadowRepository{}
+		service := NewService(merchantStore, &mockDecisionRepository{}, WithChallengers(shadows, strict))
+
+		if _, err := service.EvaluateMerchant(context.Background(), m.ID, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(shadows.created) != 0 {
+			t.Errorf("expected no shadow decisions, got %d", len(shadows.created))
+		}
+	})
+
+	t.Run("shadow failures do not fail the evaluation", func(t *testing.T) {
+		shadows := &mockShadowRepository{err: errors.New("database down")}
+		service := NewService(merchantStore, &mockDecisionRepository{}, WithChallengers(shadows, strict))
+
+		decision, err := service.EvaluateMerchant(context.Background(), m.ID, false)
+		if err != nil {
+			t.Fatalf("expected evaluation to succeed, got %v", err)
+		}
+		if decision.RiskLevel != RiskLevelLow {
+			t.Errorf("expected the champion decision to stand, got %s", decision.RiskLevel)
+		}
+	})
+}
+
+func TestBuildShadowReports(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+	cells := []ShadowCell{
+		{Challenger: "v2", ChampionRiskLevel: RiskLevelLow, ChallengerRiskLevel: RiskLevelLow, Count: 6,
+			Volume: decimal.NewFromInt(60000)},
+		{Challenger: "v2", ChampionRiskLevel: RiskLevelLow, ChallengerRiskLevel: RiskLevelMedium, Count: 3, ScoreDelta: 45,
+			Volume: decimal.NewFromInt(30000), HoldChangedVolume: decimal.NewFromInt(30000), ChallengerReserve: decimal.NewFromInt(1500)},
+		{Challenger: "v2", ChampionRiskLevel: RiskLevelHigh, ChallengerRiskLevel: RiskLevelMedium, Count: 1, ScoreDelta: -15,
+			Volume: decimal.NewFromInt(10000), HoldChangedVolume: decimal.NewFromInt(10000),
+			ChampionReserve: decimal.NewFromInt(1000), ChallengerReserve: decimal.NewFromInt(500)},
+		{Challenger: "a1", ChampionRiskLevel: RiskLevelCritical, ChallengerRiskLevel: RiskLevelCritical, Count: 2,
+			Volume: decimal.NewFromInt(5000)},
+	}
+
+	reports := BuildShadowReports(cells, from, to)
+	if len(reports) != 2 || reports[0].Challenger != "a1" || reports[1].Challenger != "v2" {
+		t.Fatalf("expected reports for a1 and v2 in order, got %+v", reports)
+	}
+
+	if reports[0].AgreementRate != 1 || reports[0].TierMigration.Counts[4][4] != 2 {
+		t.Errorf("expected a1 to agree on every evaluation, got %+v", reports[0])
+	}
+
+	report := reports[1]
+	if report.Evaluations != 10 || report.Agreements != 6 || report.Escalations != 3 || report.Deescalations != 1 {
+		t.Errorf("expected 10 evaluations (6 agreed, 3 escalated, 1 de-escalated), got %+v", report)
+	}
+	if report.AgreementRate != 0.6 || report.MeanScoreDelta != 3 {
+		t.Errorf("expected agreement 0.6 and mean delta 3, got %v and %v", report.AgreementRate, report.MeanScoreDelta)
+	}
+	if report.TierMigration.Counts[0][0] != 6 || report.TierMigration.Counts[0][2] != 3 || report.TierMigration.Counts[3][2] != 1 {
+		t.Errorf("unexpected tier migration matrix %v", report.TierMigration.Counts)
+	}
+
+	impact := report.VolumeImpact
+	if !impact.TotalVolume.Equal(decimal.NewFromInt(100000)) || !impact.EscalatedVolume.Equal(decimal.NewFromInt(30000)) ||
+		!impact.DeescalatedVolume.Equal(decimal.NewFromInt(10000)) || !impact.HoldChangedVolume.Equal(decimal.NewFromInt(40000)) {
+		t.Errorf("unexpected volume impact %+v", impact)
+	}
+	if !impact.ReserveDelta.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("expected reserve delta 1000, got %s", impact.ReserveDelta)
+	}
+}
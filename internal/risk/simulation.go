@@ -0,0 +1,384 @@
+package risk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+var ErrInvalidOverrides = errors.New("invalid simulation overrides")
+
+// OverrideErrors maps each rejected override to the reason it was rejected.
+// Threshold keys are prefixed, as in scoring_thresholds.velocity_normal.
+type OverrideErrors map[string]string
+
+func (e OverrideErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	problems := make([]string, len(fields))
+	for i, field := range fields {
+		problems[i] = field + " " + e[field]
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidOverrides, strings.Join(problems, "; "))
+}
+
+func (e OverrideErrors) Is(target error) bool {
+	return target == ErrInvalidOverrides
+}
+
+// SimulationOverrides are the what-if changes SimulateMerchant applies before
+// scoring. Nil fields keep the merchant's value. Industry, country and KYC
+// level are upper-cased; rates are percentages between 0 and 100.
+type SimulationOverrides struct {
+	Industry             *string            `json:"industry,omitempty"`
+	Country              *string            `json:"country,omitempty"`
+	AccountAgeDays       *int               `json:"account_age_days,omitempty"`
+	TransactionVolume30d *decimal.Decimal   `json:"transaction_volume_30d,omitempty"`
+	TransactionCount30d  *int               `json:"transaction_count_30d,omitempty"`
+	AvgTicketSize        *decimal.Decimal   `json:"avg_ticket_size,omitempty"`
+	ChargebackCount30d   *int               `json:"chargeback_count_30d,omitempty"`
+	ChargebackRate       *decimal.Decimal   `json:"chargeback_rate,omitempty"`
+	RefundRate           *decimal.Decimal   `json:"refund_rate,omitempty"`
+	VelocityMultiplier   *decimal.Decimal   `json:"velocity_multiplier,omitempty"`
+	KYCVerified          *bool              `json:"kyc_verified,omitempty"`
+	KYCLevel             *string            `json:"kyc_level,omitempty"`
+	ScoringThresholds    *ScoringThresholds `json:"scoring_thresholds,omitempty"`
+	ScoringMode          *string            `json:"scoring_mode,omitempty"`
+}
+
+var errUnknownOverride = errors.New("is not a supported override")
+
+// parseOverride sets the override named key from its decoded JSON value.
+func (o *SimulationOverrides) parseOverride(key string, value interface{}) (err error) {
+	switch key {
+	case "industry":
+		o.Industry, err = parseCode(value)
+	case "country":
+		o.Country, err = parseCode(value)
+	case "account_age_days":
+		o.AccountAgeDays, err = parseCount(value)
+	case "transaction_volume_30d":
+		o.TransactionVolume30d, err = parseAmount(value)
+	case "transaction_count_30d":
+		o.TransactionCount30d, err = parseCount(value)
+	case "avg_ticket_size":
+		o.AvgTicketSize, err = parseAmount(value)
+	case "chargeback_count_30d":
+		o.ChargebackCount30d, err = parseCount(value)
+	case "chargeback_rate":
+		o.ChargebackRate, err = parseRate(value)
+	case "refund_rate":
+		o.RefundRate, err = parseRate(value)
+	case "velocity_multiplier":
+		o.VelocityMultiplier, err = parseAmount(value)
+	case "kyc_verified":
+		o.KYCVerified, err = parseBool(value)
+	case "kyc_level":
+		o.KYCLevel, err = parseCode(value)
+	case "scoring_thresholds":
+		o.ScoringThresholds, err = parseScoringThresholds(value)
+	case "scoring_mode":
+		mode, ok := value.(string)
+		if !ok || mode == "" || validateScoringMode(mode) != nil {
+			return fmt.Errorf("must be %s or %s", ScoringModeBanded, ScoringModeContinuous)
+		}
+		o.ScoringMode = &mode
+	default:
+		return errUnknownOverride
+	}
+	return err
+}
+
+// ParseSimulationOverrides converts decoded JSON overrides into their typed
+// form. Unknown keys and invalid values are all reported in OverrideErrors.
+func ParseSimulationOverrides(raw map[string]interface{}) (SimulationOverrides, error) {
+	var overrides SimulationOverrides
+	errs := OverrideErrors{}
+
+	for key, value := range raw {
+		err := overrides.parseOverride(key, value)
+		var nested OverrideErrors
+		switch {
+		case errors.As(err, &nested):
+			for field, problem := range nested {
+				errs[key+"."+field] = problem
+			}
+		case err != nil:
+			errs[key] = err.Error()
+		}
+	}
+
+	if len(errs) > 0 {
+		return SimulationOverrides{}, errs
+	}
+	return overrides, nil
+}
+
+// UnmarshalJSON parses overrides strictly, so a request with unknown or
+// invalid overrides fails to decode with OverrideErrors.
+func (o *SimulationOverrides) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return OverrideErrors{"overrides": "must be an object"}
+	}
+
+	parsed, err := ParseSimulationOverrides(raw)
+	if err != nil {
+		return err
+	}
+	*o = parsed
+	return nil
+}
+
+// UnmarshalJSON rejects unknown thresholds and non-positive bounds with
+// OverrideErrors keyed as they are under scoring_thresholds.
+func (t *ScoringThresholds) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		return nil
+	}
+
+	parsed, err := parseScoringThresholds(raw)
+	var nested OverrideErrors
+	switch {
+	case errors.As(err, &nested):
+		errs := OverrideErrors{}
+		for field, problem := range nested {
+			errs["scoring_thresholds."+field] = problem
+		}
+		return errs
+	case err != nil:
+		return OverrideErrors{"scoring_thresholds": err.Error()}
+	}
+	*t = *parsed
+	return nil
+}
+
+func parseScoringThresholds(value interface{}) (*ScoringThresholds, error) {
+	raw, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("must be an object")
+	}
+
+	var thresholds ScoringThresholds
+	errs := OverrideErrors{}
+	for key, v := range raw {
+		field := thresholds.field(key)
+		if field == nil {
+			errs[key] = "is not a supported threshold"
+			continue
+		}
+		bound, ok := v.(float64)
+		if !ok || bound <= 0 {
+			errs[key] = "must be a positive number"
+			continue
+		}
+		*field = &bound
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return &thresholds, nil
+}
+
+func parseNumber(value interface{}) (float64, error) {
+	number, ok := value.(float64)
+	if !ok {
+		return 0, errors.New("must be a number")
+	}
+	return number, nil
+}
+
+func parseRate(value interface{}) (*decimal.Decimal, error) {
+	number, err := parseNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	if number < 0 || number > 100 {
+		return nil, errors.New("must be between 0 and 100")
+	}
+	rate := decimal.NewFromFloat(number)
+	return &rate, nil
+}
+
+func parseAmount(value interface{}) (*decimal.Decimal, error) {
+	number, err := parseNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	if number < 0 {
+		return nil, errors.New("must not be negative")
+	}
+	amount := decimal.NewFromFloat(number)
+	return &amount, nil
+}
+
+func parseCount(value interface{}) (*int, error) {
+	number, ok := value.(float64)
+	if !ok || number < 0 || number != math.Trunc(number) || number > math.MaxInt32 {
+		return nil, errors.New("must be a non-negative integer")
+	}
+	count := int(number)
+	return &count, nil
+}
+
+func parseBool(value interface{}) (*bool, error) {
+	b, ok := value.(bool)
+	if !ok {
+		return nil, errors.New("must be true or false")
+	}
+	return &b, nil
+}
+
+func parseCode(value interface{}) (*string, error) {
+	s, ok := value.(string)
+	code := strings.ToUpper(strings.TrimSpace(s))
+	if !ok || code == "" {
+		return nil, errors.New("must be a non-empty string")
+	}
+	return &code, nil
+}
+
+// validateCodes rejects industry and KYC level overrides rs does not list.
+// Scoring them would silently fall back to the factor's default points.
+func (o SimulationOverrides) validateCodes(rs *Ruleset) error {
+	errs := OverrideErrors{}
+	if o.Industry != nil {
+		if _, ok := rs.Category.Values[*o.Industry]; !ok {
+			errs["industry"] = "must be one of " + strings.Join(sortedKeys(rs.Category.Values), ", ")
+		}
+	}
+	if o.KYCLevel != nil {
+		if _, ok := rs.KYC.Values[*o.KYCLevel]; !ok {
+			errs["kyc_level"] = "must be one of " + strings.Join(sortedKeys(rs.KYC.Values), ", ")
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func sortedKeys(values map[string]int) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Apply sets the overridden fields on m. It returns the merchant overrides
+// applied, including the FULL KYC level implied by verifying a merchant with
+// no KYC level; scoring overrides are returned unchanged.
+func (o SimulationOverrides) Apply(m *merchant.Merchant) SimulationOverrides {
+	applied := o
+
+	if o.Industry != nil {
+		m.Industry = *o.Industry
+	}
+	if o.Country != nil {
+		m.Country = *o.Country
+	}
+	if o.AccountAgeDays != nil {
+		m.AccountAgeDays = *o.AccountAgeDays
+	}
+	if o.TransactionVolume30d != nil {
+		m.TransactionVolume30d = *o.TransactionVolume30d
+	}
+	if o.TransactionCount30d != nil {
+		m.TransactionCount30d = *o.TransactionCount30d
+	}
+	if o.AvgTicketSize != nil {
+		m.AvgTicketSize = *o.AvgTicketSize
+	}
+	if o.ChargebackCount30d != nil {
+		m.ChargebackCount30d = *o.ChargebackCount30d
+	}
+	if o.ChargebackRate != nil {
+		m.ChargebackRate = *o.ChargebackRate
+	}
+	if o.RefundRate != nil {
+		m.RefundRate = *o.RefundRate
+	}
+	if o.VelocityMultiplier != nil {
+		m.VelocityMultiplier = *o.VelocityMultiplier
+	}
+	if o.KYCVerified != nil {
+		m.KYCVerified = *o.KYCVerified
+	}
+	if o.KYCLevel != nil {
+		m.KYCLevel = *o.KYCLevel
+	} else if m.KYCVerified && o.KYCVerified != nil && m.KYCLevel == "NONE" {
+		level := "FULL"
+		m.KYCLevel = level
+		applied.KYCLevel = &level
+	}
+
+	return applied
+}
+
+// SimulationResult is a simulated decision with the overrides that produced
+// it, the baseline decision for the unmodified merchant and how the two
+// differ. Scoring overrides the scorer could not apply are left out.
+type SimulationResult struct {
+	*RiskDecision
+	Baseline         *RiskDecision       `json:"baseline"`
+	Changes          SimulationChanges   `json:"changes"`
+	AppliedOverrides SimulationOverrides `json:"applied_overrides"`
+}
+
+// SimulationChanges is the simulated decision compared with the baseline.
+// Deltas are simulated minus baseline.
+type SimulationChanges struct {
+	ScoreDelta        int          `json:"score_delta"`
+	FactorDeltas      FactorDeltas `json:"factor_deltas"`
+	RiskLevelChanged  bool         `json:"risk_level_changed"`
+	HoldPeriodChanged bool         `json:"hold_period_changed"`
+	ReserveChanged    bool         `json:"reserve_changed"`
+}
+
+// FactorDeltas are the per-factor score differences behind ScoreDelta. They
+// may not add up to it when the total is capped at 100.
+type FactorDeltas struct {
+	Chargeback int `json:"chargeback"`
+	AccountAge int `json:"account_age"`
+	Velocity   int `json:"velocity"`
+	Category   int `json:"category"`
+	KYC        int `json:"kyc"`
+	Refund     int `json:"refund"`
+	Trend      int `json:"trend"`
+}
+
+func compareSimulation(baseline, simulated *RiskDecision, baselineFactors, factors FactorScore) SimulationChanges {
+	return SimulationChanges{
+		ScoreDelta: simulated.RiskScore - baseline.RiskScore,
+		FactorDeltas: FactorDeltas{
+			Chargeback: factors.Chargeback - baselineFactors.Chargeback,
+			AccountAge: factors.AccountAge - baselineFactors.AccountAge,
+			Velocity:   factors.Velocity - baselineFactors.Velocity,
+			Category:   factors.Category - baselineFactors.Category,
+			KYC:        factors.KYC - baselineFactors.KYC,
+			Refund:     factors.Refund - baselineFactors.Refund,
+			Trend:      factors.Trend - baselineFactors.Trend,
+		},
+		RiskLevelChanged:  simulated.RiskLevel != baseline.RiskLevel,
+		HoldPeriodChanged: simulated.PayoutHoldPeriod != baseline.PayoutHoldPeriod,
+		ReserveChanged:    simulated.RollingReservePercentage != baseline.RollingReservePercentage,
+	}
+}
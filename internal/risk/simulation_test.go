@@ -0,0 +1,270 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestParseSimulationOverrides(t *testing.T) {
+	t.Run("every field", func(t *testing.T) {
+		var overrides SimulationOverrides
+		err := json.Unmarshal([]byte(`{
+			"industry": " travel", "country": "mx", "account_age_days": 45,
+			"transaction_volume_30d": 120000, "transaction_count_30d": 900, "avg_ticket_size": 133.5,
+			"chargeback_count_30d": 12, "chargeback_rate": 1.3, "refund_rate": 4,
+			"velocity_multiplier": 2.2, "kyc_verified": true, "kyc_level": "basic",
+			"scoring_thresholds": {"velocity_elevated": 3, "refund_normal": 2.5},
+			"scoring_mode": "continuous"
+		}`), &overrides)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if *overrides.Industry != "TRAVEL" || *overrides.Country != "MX" || *overrides.KYCLevel != "BASIC" {
+			t.Errorf("expected upper-cased codes, got %s %s %s", *overrides.Industry, *overrides.Country, *overrides.KYCLevel)
+		}
+		if *overrides.AccountAgeDays != 45 || *overrides.TransactionCount30d != 900 || *overrides.ChargebackCount30d != 12 {
+			t.Errorf("unexpected counts %+v", overrides)
+		}
+		if !overrides.AvgTicketSize.Equal(decimal.NewFromFloat(133.5)) || !overrides.ChargebackRate.Equal(decimal.NewFromFloat(1.3)) {
+			t.Errorf("unexpected amounts %s %s", overrides.AvgTicketSize, overrides.ChargebackRate)
+		}
+		if !*overrides.KYCVerified || *overrides.ScoringMode != ScoringModeContinuous {
+			t.Errorf("unexpected kyc_verified or scoring_mode %+v", overrides)
+		}
+		if *overrides.ScoringThresholds.VelocityElevated != 3 || *overrides.ScoringThresholds.RefundNormal != 2.5 {
+			t.Errorf("unexpected thresholds %+v", overrides.ScoringThresholds)
+		}
+	})
+
+	t.Run("errors reported per field", func(t *testing.T) {
+		_, err := ParseSimulationOverrides(map[string]interface{}{
+			"chargeback_rate":       120.0,
+			"transaction_count_30d": 1.5,
+			"kyc_verified":          "yes",
+			"industry":              "",
+			"scoring_mode":          "smooth",
+			"risk_appetite":         1.0,
+			"scoring_thresholds":    map[string]interface{}{"velocity_extreme": 9.0, "refund_normal": -1.0},
+		})
+		if !errors.Is(err, ErrInvalidOverrides) {
+			t.Fatalf("expected ErrInvalidOverrides, got %v", err)
+		}
+
+		want := OverrideErrors{
+			"chargeback_rate":                     "must be between 0 and 100",
+			"transaction_count_30d":               "must be a non-negative integer",
+			"kyc_verified":                        "must be true or false",
+			"industry":                            "must be a non-empty string",
+			"scoring_mode":                        "must be banded or continuous",
+			"risk_appetite":                       "is not a supported override",
+			"scoring_thresholds.velocity_extreme": "is not a supported threshold",
+			"scoring_thresholds.refund_normal":    "must be a positive number",
+		}
+		fields := err.(OverrideErrors)
+		if len(fields) != len(want) {
+			t.Errorf("expected %d field errors, got %v", len(want), fields)
+		}
+		for field, problem := range want {
+			if fields[field] != problem {
+				t.Errorf("%s: expected %q, got %q", field, problem, fields[field])
+			}
+		}
+	})
+
+	t.Run("not an object", func(t *testing.T) {
+		var overrides SimulationOverrides
+		err := json.Unmarshal([]byte(`[1, 2]`), &overrides)
+		var fields OverrideErrors
+		if !errors.As(err, &fields) || fields["overrides"] == "" {
+			t.Errorf("expected an overrides field error, got %v", err)
+		}
+	})
+}
+
+func TestNewEvaluatorWithThresholdsVelocity(t *testing.T) {
+	m := &merchant.Merchant{VelocityMultiplier: decimal.NewFromFloat(3.0)}
+
+	if got := NewEvaluator().CalculateVelocityScore(m); got != 10 {
+		t.Fatalf("default CalculateVelocityScore() = %d, want 10", got)
+	}
+	evaluator := NewEvaluatorWithThresholds(ScoringThresholds{VelocityElevated: ptr(3.5)})
+	if got := evaluator.CalculateVelocityScore(m); got != 5 {
+		t.Errorf("CalculateVelocityScore() with velocity_elevated 3.5 = %d, want 5", got)
+	}
+	evaluator = NewEvaluatorWithThresholds(ScoringThresholds{VelocityNormal: ptr(1.0), VelocityElevated: ptr(1.5),
+		VelocityConcerning: ptr(2.0), VelocityHighRisk: ptr(2.8)})
+	if got := evaluator.CalculateVelocityScore(m); got != 20 {
+		t.Errorf("CalculateVelocityScore() with velocity_high_risk 2.8 = %d, want 20", got)
+	}
+}
+
+func TestSimulateMerchantAppliedOverrides(t *testing.T) {
+	merchantID := uuid.New()
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return &merchant.Merchant{
+				ID:                 merchantID,
+				Industry:           "RETAIL",
+				AccountAgeDays:     800,
+				ChargebackRate:     decimal.NewFromFloat(0.3),
+				VelocityMultiplier: decimal.NewFromFloat(3.0),
+				KYCLevel:           "NONE",
+			}, nil
+		},
+	}
+
+	t.Run("echoes applied overrides", func(t *testing.T) {
+		service := NewService(merchantStore, &mockDecisionRepository{})
+		result, err := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{
+			KYCVerified:       ptr(true),
+			ScoringThresholds: &ScoringThresholds{VelocityElevated: ptr(3.5)},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		applied := result.AppliedOverrides
+		if applied.KYCLevel == nil || *applied.KYCLevel != "FULL" || applied.KYCVerified == nil {
+			t.Errorf("expected kyc_verified and the implied FULL level to be applied, got %+v", applied)
+		}
+		if applied.ScoringThresholds == nil || *applied.ScoringThresholds.VelocityElevated != 3.5 {
+			t.Errorf("expected velocity_elevated to be applied, got %+v", applied.ScoringThresholds)
+		}
+		if result.ModelVersion != "builtin-v3+custom-thresholds" {
+			t.Errorf("expected custom threshold model version, got %s", result.ModelVersion)
+		}
+	})
+
+	t.Run("thresholds rejected without bands", func(t *testing.T) {
+		scorer := NewLogisticScorer(&LogisticModel{
+			Version:  "logistic-test",
+			Features: []LogisticFeature{{Name: "kyc_verified", Coefficient: 2}},
+		})
+		service := NewService(merchantStore, &mockDecisionRepository{}, WithScorer(scorer))
+		_, err := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{
+			ChargebackRate:    ptr(decimal.NewFromFloat(1.2)),
+			ScoringThresholds: &ScoringThresholds{VelocityElevated: ptr(3.5)},
+			ScoringMode:       ptr(ScoringModeContinuous),
+		})
+		var fields OverrideErrors
+		if !errors.As(err, &fields) {
+			t.Fatalf("expected field errors, got %v", err)
+		}
+		want := "not supported by scorer logistic-test"
+		if fields["scoring_thresholds"] != want || fields["scoring_mode"] != want || len(fields) != 2 {
+			t.Errorf("expected scoring_thresholds and scoring_mode rejected, got %v", fields)
+		}
+
+		result, err := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{
+			ChargebackRate: ptr(decimal.NewFromFloat(1.2)),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.AppliedOverrides.ChargebackRate == nil {
+			t.Errorf("expected chargeback_rate to be applied, got %+v", result.AppliedOverrides)
+		}
+	})
+
+	t.Run("codes the ruleset does not score", func(t *testing.T) {
+		service := NewService(merchantStore, &mockDecisionRepository{})
+		_, err := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{
+			Industry: ptr("GAMBLNG"),
+			KYCLevel: ptr("ENHANCD"),
+		})
+		var fields OverrideErrors
+		if !errors.As(err, &fields) {
+			t.Fatalf("expected field errors, got %v", err)
+		}
+		if fields["kyc_level"] != "must be one of ENHANCED, FULL, NONE, PARTIAL" || !strings.HasPrefix(fields["industry"], "must be one of ") {
+			t.Errorf("expected industry and kyc_level rejected, got %v", fields)
+		}
+
+		if _, err := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{
+			Industry: ptr("TRAVEL"),
+			KYCLevel: ptr("ENHANCED"),
+		}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("thresholds out of order", func(t *testing.T) {
+		service := NewService(merchantStore, &mockDecisionRepository{})
+		_, err := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{
+			ScoringThresholds: &ScoringThresholds{VelocityNormal: ptr(5.0)},
+		})
+		var fields OverrideErrors
+		if !errors.As(err, &fields) || fields["scoring_thresholds"] == "" {
+			t.Errorf("expected a scoring_thresholds field error, got %v", err)
+		}
+	})
+}
+
+func TestSimulateMerchantComparesBaseline(t *testing.T) {
+	merchantID := uuid.New()
+	service := NewService(&mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return &merchant.Merchant{
+				ID:                 merchantID,
+				Industry:           "RETAIL",
+				AccountAgeDays:     800,
+				ChargebackRate:     decimal.NewFromFloat(0.3),
+				RefundRate:         decimal.NewFromFloat(2.0),
+				VelocityMultiplier: decimal.NewFromFloat(1.2),
+				KYCVerified:        true,
+				KYCLevel:           "ENHANCED",
+			}, nil
+		},
+	}, &mockDecisionRepository{})
+
+	t.Run("no overrides", func(t *testing.T) {
+		result, err := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Baseline == nil || result.Baseline.RiskScore != result.RiskScore {
+			t.Fatalf("expected baseline to match the simulation, got %+v", result.Baseline)
+		}
+		if result.Changes != (SimulationChanges{}) {
+			t.Errorf("expected no changes, got %+v", result.Changes)
+		}
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		result, err := service.SimulateMerchant(context.Background(), merchantID, SimulationOverrides{
+			ChargebackRate:     ptr(decimal.NewFromFloat(1.2)),
+			VelocityMultiplier: ptr(decimal.NewFromFloat(3.0)),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		baseline := result.Baseline
+		if baseline.RiskLevel != RiskLevelLow || result.RiskLevel != RiskLevelMediumLow || !baseline.Simulation {
+			t.Fatalf("expected LOW -> MEDIUM_LOW, got %s -> %s", baseline.RiskLevel, result.RiskLevel)
+		}
+
+		changes := result.Changes
+		if changes.ScoreDelta != result.RiskScore-baseline.RiskScore || changes.ScoreDelta != 30 {
+			t.Errorf("expected score delta 30, got %d (%d -> %d)", changes.ScoreDelta, baseline.RiskScore, result.RiskScore)
+		}
+		if changes.FactorDeltas != (FactorDeltas{Chargeback: 20, Velocity: 10}) {
+			t.Errorf("expected chargeback +20 and velocity +10, got %+v", changes.FactorDeltas)
+		}
+		if !changes.RiskLevelChanged || !changes.HoldPeriodChanged || changes.ReserveChanged {
+			t.Errorf("expected LOW -> MEDIUM_LOW to change tier and hold period but not reserve, got %+v", changes)
+		}
+	})
+}
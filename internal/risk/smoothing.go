@@ -0,0 +1,138 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+// RateSmoothing shrinks chargeback and refund rates toward an industry prior
+// in proportion to how few transactions back them. PriorWeight is the number
+// of pseudo-transactions the prior is worth: a merchant with that many real
+// transactions is weighted half on its own rate and half on the prior.
+type RateSmoothing struct {
+	PriorWeight float64   `json:"prior_weight"`
+	Chargeback  RatePrior `json:"chargeback"`
+	Refund      RatePrior `json:"refund"`
+}
+
+// RatePrior is the expected rate, in percent, for merchants without history.
+type RatePrior struct {
+	Default    float64            `json:"default"`
+	ByIndustry map[string]float64 `json:"by_industry,omitempty"`
+}
+
+// RateEstimate is a smoothed rate together with the inputs that produced it.
+// Confidence is the share of the estimate that comes from the merchant's own
+// transactions rather than the prior.
+type RateEstimate struct {
+	RawRate          float64 `json:"raw_rate"`
+	AdjustedRate     float64 `json:"adjusted_rate"`
+	PriorRate        float64 `json:"prior_rate"`
+	TransactionCount int     `json:"transaction_count"`
+	Confidence       float64 `json:"confidence"`
+}
+
+func (p RatePrior) rateFor(industry string) float64 {
+	if rate, ok := p.ByIndustry[strings.ToUpper(industry)]; ok {
+		return rate
+	}
+	return p.Default
+}
+
+func (p RatePrior) clone() RatePrior {
+	if p.ByIndustry == nil {
+		return p
+	}
+	byIndustry := make(map[string]float64, len(p.ByIndustry))
+	for k, v := range p.ByIndustry {
+		byIndustry[k] = v
+	}
+	return RatePrior{Default: p.Default, ByIndustry: byIndustry}
+}
+
+func (s *RateSmoothing) clone() *RateSmoothing {
+	if s == nil {
+		return nil
+	}
+	return &RateSmoothing{
+		PriorWeight: s.PriorWeight,
+		Chargeback:  s.Chargeback.clone(),
+		Refund:      s.Refund.clone(),
+	}
+}
+
+// estimate computes the posterior mean rate: observed events plus prior
+// pseudo-events over real plus prior transactions.
+func (s *RateSmoothing) estimate(prior RatePrior, rate float64, m *merchant.Merchant) RateEstimate {
+	n := float64(m.TransactionCount30d)
+	if n < 0 {
+		n = 0
+	}
+	priorRate := prior.rateFor(m.Industry)
+
+	return RateEstimate{
+		RawRate:          rate,
+		AdjustedRate:     (rate*n + priorRate*s.PriorWeight) / (n + s.PriorWeight),
+		PriorRate:        priorRate,
+		TransactionCount: m.TransactionCount30d,
+		Confidence:       n / (n + s.PriorWeight),
+	}
+}
+
+// ChargebackEstimate returns the chargeback rate the ruleset scores. Without
+// smoothing it is the raw rate with full confidence.
+func (r *Ruleset) ChargebackEstimate(m *merchant.Merchant) RateEstimate {
+	rate := m.ChargebackRate.InexactFloat64()
+	if r.Smoothing == nil {
+		return rawEstimate(rate, m)
+	}
+	return r.Smoothing.estimate(r.Smoothing.Chargeback, rate, m)
+}
+
+// RefundEstimate returns the refund rate the ruleset scores.
+func (r *Ruleset) RefundEstimate(m *merchant.Merchant) RateEstimate {
+	rate := m.RefundRate.InexactFloat64()
+	if r.Smoothing == nil {
+		return rawEstimate(rate, m)
+	}
+	return r.Smoothing.estimate(r.Smoothing.Refund, rate, m)
+}
+
+func rawEstimate(rate float64, m *merchant.Merchant) RateEstimate {
+	return RateEstimate{
+		RawRate:          rate,
+		AdjustedRate:     rate,
+		PriorRate:        rate,
+		TransactionCount: m.TransactionCount30d,
+		Confidence:       1,
+	}
+}
+
+func validateSmoothing(s *RateSmoothing) []error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+	if s.PriorWeight <= 0 {
+		errs = append(errs, errors.New("smoothing: prior_weight must be positive"))
+	}
+	priors := []struct {
+		name  string
+		prior RatePrior
+	}{{"chargeback", s.Chargeback}, {"refund", s.Refund}}
+	for _, p := range priors {
+		if p.prior.Default < 0 || p.prior.Default > 100 {
+			errs = append(errs, fmt.Errorf("smoothing: %s default %.2f outside [0, 100]", p.name, p.prior.Default))
+		}
+		for industry, rate := range p.prior.ByIndustry {
+			if rate < 0 || rate > 100 {
+				errs = append(errs, fmt.Errorf("smoothing: %s prior for %s %.2f outside [0, 100]", p.name, industry, rate))
+			}
+		}
+	}
+	return errs
+}
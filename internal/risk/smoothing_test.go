@@ -0,0 +1,130 @@
+package risk
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+func smoothedRuleset() *Ruleset {
+	rs := DefaultRuleset()
+	rs.Smoothing = &RateSmoothing{
+		PriorWeight: 200,
+		Chargeback: RatePrior{
+			Default:    0.6,
+			ByIndustry: map[string]float64{"TRAVEL": 1.0},
+		},
+		Refund: RatePrior{Default: 3.0},
+	}
+	return rs
+}
+
+func TestRateSmoothing(t *testing.T) {
+	rs := smoothedRuleset()
+
+	tests := []struct {
+		name           string
+		rate           float64
+		count          int
+		industry       string
+		wantAdjusted   float64
+		wantConfidence float64
+	}{
+		{"no history uses prior", 2.0, 0, "RETAIL", 0.6, 0},
+		{"low volume shrinks toward prior", 2.0, 50, "RETAIL", (2.0*50 + 0.6*200) / 250, 0.2},
+		{"high volume keeps own rate", 2.0, 19800, "RETAIL", (2.0*19800 + 0.6*200) / 20000, 0.99},
+		{"industry prior", 0, 200, "travel", 0.5, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &merchant.Merchant{
+				ChargebackRate:      decimal.NewFromFloat(tt.rate),
+				TransactionCount30d: tt.count,
+				Industry:            tt.industry,
+			}
+
+			got := rs.ChargebackEstimate(m)
+			if math.Abs(got.AdjustedRate-tt.wantAdjusted) > 1e-9 {
+				t.Errorf("AdjustedRate = %v, want %v", got.AdjustedRate, tt.wantAdjusted)
+			}
+			if math.Abs(got.Confidence-tt.wantConfidence) > 1e-9 {
+				t.Errorf("Confidence = %v, want %v", got.Confidence, tt.wantConfidence)
+			}
+			if got.RawRate != tt.rate {
+				t.Errorf("RawRate = %v, want %v", got.RawRate, tt.rate)
+			}
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		m := &merchant.Merchant{ChargebackRate: decimal.NewFromFloat(2.0), TransactionCount30d: 50}
+		got := DefaultRuleset().ChargebackEstimate(m)
+		if got.AdjustedRate != 2.0 || got.Confidence != 1 {
+			t.Errorf("expected raw rate with full confidence, got %+v", got)
+		}
+	})
+}
+
+func TestEvaluatorSmoothedScoring(t *testing.T) {
+	e := NewEvaluatorFromRuleset(smoothedRuleset())
+
+	// One chargeback on 50 transactions versus 20 on 1,000: same raw rate.
+	lowVolume := &merchant.Merchant{ChargebackRate: decimal.NewFromFloat(2.0), TransactionCount30d: 50, Industry: "RETAIL"}
+	highVolume := &merchant.Merchant{ChargebackRate: decimal.NewFromFloat(2.0), TransactionCount30d: 1000, Industry: "RETAIL"}
+
+	if got := NewEvaluator().CalculateChargebackScore(lowVolume); got != 30 {
+		t.Fatalf("expected unsmoothed score 30, got %d", got)
+	}
+	if got := e.CalculateChargebackScore(lowVolume); got != 10 {
+		t.Errorf("expected low-volume smoothed score 10, got %d", got)
+	}
+	if got := e.CalculateChargebackScore(highVolume); got != 30 {
+		t.Errorf("expected high-volume smoothed score 30, got %d", got)
+	}
+}
+
+func TestExplainerSmoothedRate(t *testing.T) {
+	m := &merchant.Merchant{
+		ChargebackRate:      decimal.NewFromFloat(2.0),
+		RefundRate:          decimal.NewFromFloat(1.0),
+		TransactionCount30d: 50,
+		Industry:            "RETAIL",
+	}
+	e := NewExplainerFromRuleset(smoothedRuleset(), DefaultLanguage)
+
+	reasoning := e.GenerateReasoning(m, 20, FactorScore{Chargeback: 20}, PolicyTier{})
+	chargeback := reasoning.PrimaryFactors[0]
+
+	want := "0.88% rate - Acceptable range (adjusted from raw 2.00% over 50 transactions, 20% confidence)"
+	if chargeback.Contribution != want {
+		t.Errorf("Contribution = %q, want %q", chargeback.Contribution, want)
+	}
+	if chargeback.Adjustment == nil || chargeback.Adjustment.TransactionCount != 50 {
+		t.Errorf("expected adjustment details, got %+v", chargeback.Adjustment)
+	}
+
+	unsmoothed := NewExplainer().GenerateReasoning(m, 30, FactorScore{Chargeback: 30}, PolicyTier{})
+	if unsmoothed.PrimaryFactors[0].Adjustment != nil {
+		t.Error("expected no adjustment without smoothing")
+	}
+}
+
+func TestValidateSmoothing(t *testing.T) {
+	rs := smoothedRuleset()
+	rs.Smoothing.PriorWeight = 0
+	rs.Smoothing.Refund.ByIndustry = map[string]float64{"TRAVEL": 150}
+
+	err := rs.Validate()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{"prior_weight must be positive", "refund prior for TRAVEL"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got %v", want, err)
+		}
+	}
+}
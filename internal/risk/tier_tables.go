@@ -0,0 +1,202 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTierTableNotDraft = errors.New("only draft tier tables can be activated")
+	ErrInvalidEffective  = errors.New("effective_until must be after effective_from")
+)
+
+var validRiskLevels = map[RiskLevel]bool{
+	RiskLevelLow:       true,
+	RiskLevelMediumLow: true,
+	RiskLevelMedium:    true,
+	RiskLevelHigh:      true,
+	RiskLevelCritical:  true,
+}
+
+var validHoldPeriods = map[HoldPeriod]bool{
+	HoldPeriodImmediate: true,
+	HoldPeriod7Days:     true,
+	HoldPeriod14Days:    true,
+	HoldPeriod45Days:    true,
+}
+
+// TierValidationError lists every problem found in a tier table so admins can
+// fix them in one pass.
+type TierValidationError struct {
+	Problems []string
+}
+
+func (e *TierValidationError) Error() string {
+	return "invalid tier table: " + strings.Join(e.Problems, "; ")
+}
+
+// ValidateTiers checks that tiers cover scores 0-100 exactly once, with no
+// gaps or overlaps, and that every tier carries a known policy.
+func ValidateTiers(tiers []PolicyTier) error {
+	var problems []string
+
+	if len(tiers) == 0 {
+		return &TierValidationError{Problems: []string{"at least one tier is required"}}
+	}
+
+	sorted := make([]PolicyTier, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinScore < sorted[j].MinScore
+	})
+
+	for i, tier := range sorted {
+		if tier.MinScore > tier.MaxScore {
+			problems = append(problems, fmt.Sprintf("tier %d-%d: min_score exceeds max_score", tier.MinScore, tier.MaxScore))
+		}
+		if !validRiskLevels[tier.RiskLevel] {
+			problems = append(problems, fmt.Sprintf("tier %d-%d: unknown risk_level %q", tier.MinScore, tier.MaxScore, tier.RiskLevel))
+		}
+		if !validHoldPeriods[tier.HoldPeriod] {
+			problems = append(problems, fmt.Sprintf("tier %d-%d: unknown hold_period %q", tier.MinScore, tier.MaxScore, tier.HoldPeriod))
+		}
+		if tier.ReservePercentage < 0 || tier.ReservePercentage > 100 {
+			problems = append(problems, fmt.Sprintf("tier %d-%d: reserve_percentage %d outside [0, 100]",
+				tier.MinScore, tier.MaxScore, tier.ReservePercentage))
+		}
+		if tier.Label == "" {
+			problems = append(problems, fmt.Sprintf("tier %d-%d: label is required", tier.MinScore, tier.MaxScore))
+		}
+
+		if i == 0 {
+			continue
+		}
+		prev := sorted[i-1]
+		switch {
+		case tier.MinScore <= prev.MaxScore:
+			problems = append(problems, fmt.Sprintf("tiers %d-%d and %d-%d overlap",
+				prev.MinScore, prev.MaxScore, tier.MinScore, tier.MaxScore))
+		case tier.MinScore > prev.MaxScore+1:
+			problems = append(problems, fmt.Sprintf("gap between scores %d and %d",
+				prev.MaxScore, tier.MinScore))
+		}
+	}
+
+	if sorted[0].MinScore != 0 {
+		problems = append(problems, fmt.Sprintf("lowest tier must start at 0, starts at %d", sorted[0].MinScore))
+	}
+	if last := sorted[len(sorted)-1]; last.MaxScore != 100 {
+		problems = append(problems, fmt.Sprintf("highest tier must end at 100, ends at %d", last.MaxScore))
+	}
+
+	if len(problems) > 0 {
+		return &TierValidationError{Problems: problems}
+	}
+	return nil
+}
+
+type TierTableRepository interface {
+	GetActiveAt(ctx context.Context, at time.Time) (*PolicyTierTable, error)
+}
+
+type TierTableStore interface {
+	TierTableRepository
+	Create(ctx context.Context, table *PolicyTierTable) error
+	Get(ctx context.Context, id uuid.UUID) (*PolicyTierTable, error)
+	List(ctx context.Context) ([]PolicyTierTable, error)
+	Activate(ctx context.Context, id uuid.UUID, from time.Time, until *time.Time) error
+}
+
+// TierTableService manages the lifecycle of tier tables: drafting, validating
+// and scheduling their activation.
+type TierTableService struct {
+	store TierTableStore
+}
+
+func NewTierTableService(store TierTableStore) *TierTableService {
+	return &TierTableService{store: store}
+}
+
+// Draft stores a new tier table without activating it. Invalid tiers are
+// accepted so the draft can be reviewed; activation validates them again.
+func (s *TierTableService) Draft(ctx context.Context, version string, tiers []PolicyTier) (*PolicyTierTable, error) {
+	table := &PolicyTierTable{
+		Version: version,
+		Status:  TierTableStatusDraft,
+		Tiers:   tiers,
+	}
+
+	if err := s.store.Create(ctx, table); err != nil {
+		return nil, fmt.Errorf("failed to draft tier table %s: %w", version, err)
+	}
+
+	log.Printf("[INFO] Drafted policy tier table %s (%s)", table.Version, table.ID)
+	return table, nil
+}
+
+func (s *TierTableService) Get(ctx context.Context, id uuid.UUID) (*PolicyTierTable, error) {
+	return s.store.Get(ctx, id)
+}
+
+func (s *TierTableService) List(ctx context.Context) ([]PolicyTierTable, error) {
+	return s.store.List(ctx)
+}
+
+// Activate schedules a draft table to take effect at from (or immediately when
+// from is zero). The tiers are validated again before activation.
+func (s *TierTableService) Activate(ctx context.Context, id uuid.UUID, from time.Time, until *time.Time) (*PolicyTierTable, error) {
+	table, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if table.Status != TierTableStatusDraft {
+		return nil, ErrTierTableNotDraft
+	}
+	if err := ValidateTiers(table.Tiers); err != nil {
+		return nil, err
+	}
+
+	if from.IsZero() {
+		from = time.Now()
+	}
+	if until != nil && !until.After(from) {
+		return nil, ErrInvalidEffective
+	}
+
+	if err := s.store.Activate(ctx, id, from, until); err != nil {
+		return nil, fmt.Errorf("failed to activate tier table %s: %w", id, err)
+	}
+
+	log.Printf("[INFO] Activated policy tier table %s effective from %s", table.Version, from.Format(time.RFC3339))
+	return s.store.Get(ctx, id)
+}
+
+// Active returns the tier table in force at the given time, falling back to
+// the built-in tiers when none is active.
+func (s *TierTableService) Active(ctx context.Context, at time.Time) (*PolicyTierTable, error) {
+	table, err := s.store.GetActiveAt(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		return builtinTierTable(), nil
+	}
+	return table, nil
+}
+
+func builtinTierTable() *PolicyTierTable {
+	mapper := NewPolicyMapper()
+	return &PolicyTierTable{
+		Version: mapper.Version(),
+		Status:  TierTableStatusActive,
+		Tiers:   mapper.Tiers(),
+	}
+}
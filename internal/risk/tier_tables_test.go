@@ -0,0 +1,223 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type mockTierTableStore struct {
+	tables     map[uuid.UUID]*PolicyTierTable
+	activeAt   func(ctx context.Context, at time.Time) (*PolicyTierTable, error)
+	activateFn func(ctx context.Context, id uuid.UUID, from time.Time, until *time.Time) error
+}
+
+func (m *mockTierTableStore) Create(ctx context.Context, table *PolicyTierTable) error {
+	table.ID = uuid.New()
+	m.tables[table.ID] = table
+	return nil
+}
+
+func (m *mockTierTableStore) Get(ctx context.Context, id uuid.UUID) (*PolicyTierTable, error) {
+	table, ok := m.tables[id]
+	if !ok {
+		return nil, errors.New("tier table not found")
+	}
+	return table, nil
+}
+
+func (m *mockTierTableStore) List(ctx context.Context) ([]PolicyTierTable, error) {
+	tables := make([]PolicyTierTable, 0, len(m.tables))
+	for _, table := range m.tables {
+		tables = append(tables, *table)
+	}
+	return tables, nil
+}
+
+func (m *mockTierTableStore) Activate(ctx context.Context, id uuid.UUID, from time.Time, until *time.Time) error {
+	if m.activateFn != nil {
+		return m.activateFn(ctx, id, from, until)
+	}
+	table := m.tables[id]
+	table.Status = TierTableStatusActive
+	table.EffectiveFrom = &from
+	table.EffectiveUntil = until
+	return nil
+}
+
+func (m *mockTierTableStore) GetActiveAt(ctx context.Context, at time.Time) (*PolicyTierTable, error) {
+	if m.activeAt != nil {
+		return m.activeAt(ctx, at)
+	}
+	return nil, nil
+}
+
+func TestValidateTiers(t *testing.T) {
+	withTiers := func(modify func(tiers []PolicyTier) []PolicyTier) []PolicyTier {
+		return modify(DefaultPolicyTiers())
+	}
+
+	tests := []struct {
+		name        string
+		tiers       []PolicyTier
+		wantProblem string
+	}{
+		{
+			name: "gap between tiers",
+			tiers: withTiers(func(tiers []PolicyTier) []PolicyTier {
+				tiers[1].MaxScore = 35
+				return tiers
+			}),
+			wantProblem: "gap between scores 35 and 41",
+		},
+		{
+			name: "overlapping tiers",
+			tiers: withTiers(func(tiers []PolicyTier) []PolicyTier {
+				tiers[2].MinScore = 38
+				return tiers
+			}),
+			wantProblem: "tiers 21-40 and 38-60 overlap",
+		},
+		{
+			name: "does not start at zero",
+			tiers: withTiers(func(tiers []PolicyTier) []PolicyTier {
+				tiers[0].MinScore = 5
+				return tiers
+			}),
+			wantProblem: "lowest tier must start at 0",
+		},
+		{
+			name: "does not reach 100",
+			tiers: withTiers(func(tiers []PolicyTier) []PolicyTier {
+				tiers[4].MaxScore = 95
+				return tiers
+			}),
+			wantProblem: "highest tier must end at 100",
+		},
+		{
+			name: "unknown hold period",
+			tiers: withTiers(func(tiers []PolicyTier) []PolicyTier {
+				tiers[3].HoldPeriod = "90_DAYS"
+				return tiers
+			}),
+			wantProblem: `unknown hold_period "90_DAYS"`,
+		},
+		{
+			name:        "empty table",
+			tiers:       []PolicyTier{},
+			wantProblem: "at least one tier is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTiers(tt.tiers)
+
+			var validationErr *TierValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected TierValidationError, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantProblem) {
+				t.Errorf("expected problem %q, got %v", tt.wantProblem, validationErr.Problems)
+			}
+		})
+	}
+
+	t.Run("default tiers are valid", func(t *testing.T) {
+		if err := ValidateTiers(DefaultPolicyTiers()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("unordered tiers are accepted", func(t *testing.T) {
+		tiers := DefaultPolicyTiers()
+		tiers[0], tiers[4] = tiers[4], tiers[0]
+
+		if err := ValidateTiers(tiers); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestTierTableServiceActivate(t *testing.T) {
+	newService := func() (*TierTableService, *mockTierTableStore) {
+		store := &mockTierTableStore{tables: make(map[uuid.UUID]*PolicyTierTable)}
+		return NewTierTableService(store), store
+	}
+
+	t.Run("activates a valid draft", func(t *testing.T) {
+		service, _ := newService()
+		draft, _ := service.Draft(context.Background(), "2025-Q1", DefaultPolicyTiers())
+		from := time.Now().Add(24 * time.Hour)
+
+		table, err := service.Activate(context.Background(), draft.ID, from, nil)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if table.Status != TierTableStatusActive {
+			t.Errorf("expected ACTIVE status, got %s", table.Status)
+		}
+		if !table.EffectiveFrom.Equal(from) {
+			t.Errorf("expected effective_from %v, got %v", from, table.EffectiveFrom)
+		}
+	})
+
+	t.Run("rejects invalid tiers", func(t *testing.T) {
+		service, _ := newService()
+		tiers := DefaultPolicyTiers()
+		tiers[1].MaxScore = 30
+		draft, _ := service.Draft(context.Background(), "broken", tiers)
+
+		_, err := service.Activate(context.Background(), draft.ID, time.Time{}, nil)
+
+		var validationErr *TierValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected TierValidationError, got %v", err)
+		}
+	})
+
+	t.Run("rejects already active table", func(t *testing.T) {
+		service, _ := newService()
+		draft, _ := service.Draft(context.Background(), "2025-Q2", DefaultPolicyTiers())
+		if _, err := service.Activate(context.Background(), draft.ID, time.Time{}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err := service.Activate(context.Background(), draft.ID, time.Time{}, nil)
+
+		if !errors.Is(err, ErrTierTableNotDraft) {
+			t.Errorf("expected ErrTierTableNotDraft, got %v", err)
+		}
+	})
+
+	t.Run("rejects inverted effective window", func(t *testing.T) {
+		service, _ := newService()
+		draft, _ := service.Draft(context.Background(), "2025-Q3", DefaultPolicyTiers())
+		from := time.Now()
+		until := from.Add(-time.Hour)
+
+		_, err := service.Activate(context.Background(), draft.ID, from, &until)
+
+		if !errors.Is(err, ErrInvalidEffective) {
+			t.Errorf("expected ErrInvalidEffective, got %v", err)
+		}
+	})
+
+	t.Run("falls back to built-in tiers", func(t *testing.T) {
+		service, _ := newService()
+
+		table, err := service.Active(context.Background(), time.Now())
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if table.Version != NewPolicyMapper().Version() {
+			t.Errorf("expected built-in version, got %s", table.Version)
+		}
+	})
+}
@@ -0,0 +1,234 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+const (
+	TrendRising = "rising"
+	TrendDrop   = "drop"
+)
+
+// TrendFactor adds points when a merchant's metric history shows it
+// deteriorating. History is sampled at the end of consecutive PeriodDays-long
+// periods counted back from evaluation time, with the merchant's current
+// metrics as the latest sample. Points from all matching signals are summed
+// and capped at MaxPoints.
+type TrendFactor struct {
+	MaxPoints  int           `json:"max_points"`
+	PeriodDays int           `json:"period_days"`
+	Signals    []TrendSignal `json:"signals"`
+}
+
+// TrendSignal is one trend pattern. A rising signal matches when Metric
+// increased in each of the last Periods periods; a drop signal matches when
+// Metric fell by at least DropPercent over the last Periods periods.
+type TrendSignal struct {
+	Code        string  `json:"code"`
+	Metric      string  `json:"metric"`
+	Kind        string  `json:"kind"`
+	Periods     int     `json:"periods"`
+	DropPercent float64 `json:"drop_percent,omitempty"`
+	Points      int     `json:"points"`
+}
+
+// TrendMatch is a signal that matched, with the metric's value at the start
+// and end of the window it was observed over.
+type TrendMatch struct {
+	Code    string  `json:"code"`
+	Metric  string  `json:"metric"`
+	Kind    string  `json:"kind"`
+	Periods int     `json:"periods"`
+	From    float64 `json:"from"`
+	To      float64 `json:"to"`
+	Points  int     `json:"points"`
+}
+
+// TrendScorer is implemented by scorers that add points for metric trends on
+// top of the point-in-time score.
+type TrendScorer interface {
+	// TrendLookback is how far back history must reach; zero disables trends.
+	TrendLookback() time.Duration
+	ScoreTrends(m *merchant.Merchant, history []merchant.MetricSnapshot, at time.Time) (int, []TrendMatch)
+}
+
+// trendMetrics are the snapshot metrics a trend signal may follow.
+var trendMetrics = map[string]bool{
+	"chargeback_rate":        true,
+	"chargeback_count_30d":   true,
+	"refund_rate":            true,
+	"velocity_multiplier":    true,
+	"transaction_volume_30d": true,
+	"transaction_count_30d":  true,
+}
+
+func (f *TrendFactor) period() time.Duration {
+	return time.Duration(f.PeriodDays) * 24 * time.Hour
+}
+
+// Lookback returns the history window the longest signal needs.
+func (f *TrendFactor) Lookback() time.Duration {
+	if f == nil {
+		return 0
+	}
+	periods := 0
+	for _, signal := range f.Signals {
+		if signal.Periods > periods {
+			periods = signal.Periods
+		}
+	}
+	return time.Duration(periods) * f.period()
+}
+
+// Score evaluates every signal against history, which must be ordered oldest
+// first, and returns the capped points with the signals that matched.
+func (f *TrendFactor) Score(m *merchant.Merchant, history []merchant.MetricSnapshot, at time.Time) (int, []TrendMatch) {
+	if f == nil {
+		return 0, nil
+	}
+
+	var points int
+	var matches []TrendMatch
+	for _, signal := range f.Signals {
+		values, ok := f.series(m, history, at, signal.Periods, signal.Metric)
+		if !ok || !signal.matches(values) {
+			continue
+		}
+		points += signal.Points
+		matches = append(matches, TrendMatch{
+			Code:    signal.Code,
+			Metric:  signal.Metric,
+			Kind:    signal.Kind,
+			Periods: signal.Periods,
+			From:    values[0],
+			To:      values[len(values)-1],
+			Points:  signal.Points,
+		})
+	}
+
+	if points > f.MaxPoints {
+		points = f.MaxPoints
+	}
+	return points, matches
+}
+
+// series samples metric at the end of each of the last periods periods,
+// oldest first, followed by the current value. It reports false when history
+// does not reach back far enough.
+func (f *TrendFactor) series(m *merchant.Merchant, history []merchant.MetricSnapshot, at time.Time, periods int, metric string) ([]float64, bool) {
+	values := make([]float64, 0, periods+1)
+
+	for k := periods; k >= 1; k-- {
+		boundary := at.Add(-time.Duration(k) * f.period())
+
+		idx := -1
+		for i, snapshot := range history {
+			if snapshot.CapturedAt.After(boundary) {
+				break
+			}
+			idx = i
+		}
+		if idx < 0 {
+			return nil, false
+		}
+
+		sample := *m
+		history[idx].ApplyTo(&sample)
+		value, _ := numericFeature(&sample, metric)
+		values = append(values, value)
+	}
+
+	current, _ := numericFeature(m, metric)
+	return append(values, current), true
+}
+
+func (s TrendSignal) matches(values []float64) bool {
+	switch s.Kind {
+	case TrendRising:
+		for i := 1; i < len(values); i++ {
+			if values[i] <= values[i-1] {
+				return false
+			}
+		}
+		return true
+	case TrendDrop:
+		from, to := values[0], values[len(values)-1]
+		return from > 0 && (from-to)/from*100 >= s.DropPercent
+	default:
+		return false
+	}
+}
+
+func (f *TrendFactor) clone() *TrendFactor {
+	if f == nil {
+		return nil
+	}
+	return &TrendFactor{
+		MaxPoints:  f.MaxPoints,
+		PeriodDays: f.PeriodDays,
+		Signals:    append([]TrendSignal(nil), f.Signals...),
+	}
+}
+
+// TrendLookback reports the history the ruleset's trend factor needs.
+func (e *Evaluator) TrendLookback() time.Duration {
+	return e.rules.Trends.Lookback()
+}
+
+// ScoreTrends scores the ruleset's trend signals against the merchant's
+// metric history.
+func (e *Evaluator) ScoreTrends(m *merchant.Merchant, history []merchant.MetricSnapshot, at time.Time) (int, []TrendMatch) {
+	return e.rules.Trends.Score(m, history, at)
+}
+
+func validateTrends(f *TrendFactor) []error {
+	if f == nil {
+		return nil
+	}
+
+	var errs []error
+	if f.MaxPoints <= 0 {
+		errs = append(errs, errors.New("trends: max_points must be positive"))
+	}
+	if f.PeriodDays <= 0 {
+		errs = append(errs, errors.New("trends: period_days must be positive"))
+	}
+
+	codes := make(map[string]bool, len(f.Signals))
+	for i, signal := range f.Signals {
+		name := signal.Code
+		if name == "" {
+			name = fmt.Sprintf("signal %d", i)
+			errs = append(errs, fmt.Errorf("trends: %s has no code", name))
+		} else if codes[signal.Code] {
+			errs = append(errs, fmt.Errorf("trends: duplicate signal code %q", signal.Code))
+		}
+		codes[signal.Code] = true
+
+		if !trendMetrics[signal.Metric] {
+			errs = append(errs, fmt.Errorf("trends: %s: unknown metric %q", name, signal.Metric))
+		}
+		if signal.Periods < 1 {
+			errs = append(errs, fmt.Errorf("trends: %s: periods must be at least 1", name))
+		}
+		if signal.Points < 0 || signal.Points > f.MaxPoints {
+			errs = append(errs, fmt.Errorf("trends: %s: points %d outside [0, %d]", name, signal.Points, f.MaxPoints))
+		}
+
+		switch signal.Kind {
+		case TrendRising:
+		case TrendDrop:
+			if signal.DropPercent <= 0 || signal.DropPercent > 100 {
+				errs = append(errs, fmt.Errorf("trends: %s: drop_percent must be in (0, 100]", name))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("trends: %s: kind must be %s or %s", name, TrendRising, TrendDrop))
+		}
+	}
+
+	return errs
+}
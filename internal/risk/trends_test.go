@@ -0,0 +1,205 @@
+package risk
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+)
+
+type mockMetricHistoryRepository struct {
+	listByMerchantSince func(ctx context.Context, merchantID uuid.UUID, since time.Time) ([]merchant.MetricSnapshot, error)
+}
+
+func (m *mockMetricHistoryRepository) ListByMerchantSince(ctx context.Context, merchantID uuid.UUID, since time.Time) ([]merchant.MetricSnapshot, error) {
+	if m.listByMerchantSince != nil {
+		return m.listByMerchantSince(ctx, merchantID, since)
+	}
+	return nil, nil
+}
+
+func deterioratingMerchant() *merchant.Merchant {
+	return &merchant.Merchant{
+		ID:                   uuid.New(),
+		Industry:             "RETAIL",
+		AccountAgeDays:       800,
+		TransactionVolume30d: decimal.NewFromFloat(20000),
+		ChargebackRate:       decimal.NewFromFloat(0.9),
+		VelocityMultiplier:   decimal.NewFromFloat(1.0),
+		RefundRate:           decimal.NewFromFloat(1.0),
+		KYCVerified:          true,
+		KYCLevel:             "ENHANCED",
+	}
+}
+
+func snapshotAt(at time.Time, chargebackRate, volume float64) merchant.MetricSnapshot {
+	return merchant.MetricSnapshot{
+		ChargebackRate:       decimal.NewFromFloat(chargebackRate),
+		TransactionVolume30d: decimal.NewFromFloat(volume),
+		VelocityMultiplier:   decimal.NewFromFloat(1.0),
+		RefundRate:           decimal.NewFromFloat(1.0),
+		KYCVerified:          true,
+		KYCLevel:             "ENHANCED",
+		CapturedAt:           at,
+	}
+}
+
+func TestTrendFactorScore(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	m := deterioratingMerchant()
+
+	tests := []struct {
+		name      string
+		history   []merchant.MetricSnapshot
+		maxPoints int
+		want      int
+		codes     []string
+	}{
+		{
+			name: "rising chargebacks and volume drop",
+			history: []merchant.MetricSnapshot{
+				snapshotAt(now.Add(-22*day), 0.3, 60000),
+				snapshotAt(now.Add(-15*day), 0.5, 50000),
+				snapshotAt(now.Add(-8*day), 0.7, 30000),
+			},
+			want:  15,
+			codes: []string{"CHARGEBACK_RATE_RISING", "VOLUME_DROP"},
+		},
+		{
+			name: "flat period breaks the rise",
+			history: []merchant.MetricSnapshot{
+				snapshotAt(now.Add(-22*day), 0.3, 20000),
+				snapshotAt(now.Add(-8*day), 0.7, 20000),
+			},
+			want: 0,
+		},
+		{
+			name: "history too short",
+			history: []merchant.MetricSnapshot{
+				snapshotAt(now.Add(-8*day), 0.7, 60000),
+			},
+			want: 0,
+		},
+		{
+			name: "capped at max points",
+			history: []merchant.MetricSnapshot{
+				snapshotAt(now.Add(-22*day), 0.3, 60000),
+				snapshotAt(now.Add(-15*day), 0.5, 50000),
+				snapshotAt(now.Add(-8*day), 0.7, 30000),
+			},
+			maxPoints: 12,
+			want:      12,
+			codes:     []string{"CHARGEBACK_RATE_RISING", "VOLUME_DROP"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trends := DefaultRuleset().Trends
+			if tt.maxPoints > 0 {
+				trends.MaxPoints = tt.maxPoints
+			}
+
+			got, matches := trends.Score(m, tt.history, now)
+			if got != tt.want {
+				t.Errorf("expected %d points, got %d", tt.want, got)
+			}
+			if len(matches) != len(tt.codes) {
+				t.Fatalf("expected matches %v, got %+v", tt.codes, matches)
+			}
+			for i, code := range tt.codes {
+				if matches[i].Code != code {
+					t.Errorf("match %d: expected %s, got %s", i, code, matches[i].Code)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTrends(t *testing.T) {
+	rs := DefaultRuleset()
+	rs.Trends.PeriodDays = 0
+	rs.Trends.Signals = append(rs.Trends.Signals,
+		TrendSignal{Code: "AGE", Metric: "account_age_days", Kind: TrendRising, Periods: 2, Points: 5},
+		TrendSignal{Code: "REFUND_DROP", Metric: "refund_rate", Kind: TrendDrop, Periods: 2, Points: 5},
+	)
+
+	err := rs.Validate()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{"period_days must be positive", `unknown metric "account_age_days"`, "REFUND_DROP: drop_percent"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got %v", want, err)
+		}
+	}
+}
+
+func TestEvaluateMerchantTrends(t *testing.T) {
+	m := deterioratingMerchant()
+	merchantStore := &mockMerchantRepository{
+		getMerchant: func(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
+			return m, nil
+		},
+	}
+
+	var since time.Time
+	history := &mockMetricHistoryRepository{
+		listByMerchantSince: func(ctx context.Context, merchantID uuid.UUID, s time.Time) ([]merchant.MetricSnapshot, error) {
+			since = s
+			now := time.Now()
+			return []merchant.MetricSnapshot{
+				snapshotAt(now.Add(-22*24*time.Hour), 0.3, 20000),
+				snapshotAt(now.Add(-15*24*time.Hour), 0.5, 20000),
+				snapshotAt(now.Add(-8*24*time.Hour), 0.7, 20000),
+			}, nil
+		},
+	}
+
+	t.Run("trend points added with history", func(t *testing.T) {
+		service := NewService(merchantStore, &mockDecisionRepository{}, WithMetricHistory(history))
+
+		decision, err := service.EvaluateMerchant(context.Background(), m.ID, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// 10 chargeback + 5 category, plus 10 for the rising chargeback rate.
+		if decision.RiskScore != 25 {
+			t.Errorf("expected score 25, got %d", decision.RiskScore)
+		}
+		if lookback := time.Since(since); lookback < 21*24*time.Hour || lookback > 22*24*time.Hour {
+			t.Errorf("expected 21-day lookback, got %v", lookback)
+		}
+
+		var found bool
+		for _, factor := range decision.Reasoning.PrimaryFactors {
+			if factor.Factor == "Trend: CHARGEBACK_RATE_RISING" {
+				found = true
+				if factor.Score != 10 || factor.Contribution != "chargeback_rate rose in each of the last 3 periods (0.30 to 0.90)" {
+					t.Errorf("unexpected trend factor %+v", factor)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected trend factor, got %+v", decision.Reasoning.PrimaryFactors)
+		}
+	})
+
+	t.Run("no trends without history store", func(t *testing.T) {
+		service := NewService(merchantStore, &mockDecisionRepository{})
+
+		decision, err := service.EvaluateMerchant(context.Background(), m.ID, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if decision.RiskScore != 15 {
+			t.Errorf("expected score 15, got %d", decision.RiskScore)
+		}
+	})
+}
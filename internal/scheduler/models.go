@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+type RunStatus string
+
+const (
+	RunRunning   RunStatus = "RUNNING"
+	RunCompleted RunStatus = "COMPLETED"
+	RunFailed    RunStatus = "FAILED"
+)
+
+// Run records one re-evaluation cycle executed by the instance holding the
+// scheduler lock.
+type Run struct {
+	ID                 uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstanceID         string     `json:"instance_id" gorm:"not null"`
+	Status             RunStatus  `json:"status" gorm:"not null;default:'RUNNING'"`
+	MerchantsDue       int        `json:"merchants_due" gorm:"not null;default:0"`
+	MerchantsEvaluated int        `json:"merchants_evaluated" gorm:"not null;default:0"`
+	MerchantsFailed    int        `json:"merchants_failed" gorm:"not null;default:0"`
+	Error              string     `json:"error,omitempty" gorm:"not null;default:''"`
+	StartedAt          time.Time  `json:"started_at" gorm:"not null;default:now()"`
+	FinishedAt         *time.Time `json:"finished_at,omitempty"`
+}
+
+func (Run) TableName() string {
+	return "scheduler_runs"
+}
+
+// MerchantState is a merchant's current tier as of its latest persisted
+// decision. RiskLevel is empty and LastEvaluatedAt nil for merchants never
+// evaluated. PendingChangeID and PendingChangeAt identify the latest
+// score-relevant metric change not yet covered by a decision and when it was
+// captured.
+type MerchantState struct {
+	MerchantID      uuid.UUID      `json:"merchant_id"`
+	RiskLevel       risk.RiskLevel `json:"risk_level,omitempty"`
+	LastEvaluatedAt *time.Time     `json:"last_evaluated_at,omitempty"`
+	PendingChangeID *uuid.UUID     `json:"pending_change_id,omitempty"`
+	PendingChangeAt *time.Time     `json:"pending_change_at,omitempty"`
+}
+
+// MerchantSchedule is when a merchant is next due for re-evaluation.
+type MerchantSchedule struct {
+	MerchantState
+	NextEvaluationAt time.Time `json:"next_evaluation_at"`
+	Due              bool      `json:"due"`
+}
+
+// Cadence sets how often merchants in each tier are re-evaluated. Merchants
+// never evaluated are due at once, and merchants with a pending metric change
+// are due from when it was captured; tiers without an entry use the shortest
+// configured interval.
+type Cadence map[risk.RiskLevel]time.Duration
+
+func DefaultCadence() Cadence {
+	return Cadence{
+		risk.RiskLevelCritical:  24 * time.Hour,
+		risk.RiskLevelHigh:      3 * 24 * time.Hour,
+		risk.RiskLevelMedium:    7 * 24 * time.Hour,
+		risk.RiskLevelMediumLow: 14 * 24 * time.Hour,
+		risk.RiskLevelLow:       30 * 24 * time.Hour,
+	}
+}
+
+// ParseCadence reads a cadence such as "CRITICAL=24h,LOW=720h" on top of the
+// defaults. Levels must be known risk levels.
+func ParseCadence(spec string) (Cadence, error) {
+	cadence := DefaultCadence()
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		level, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid cadence entry %q: expected LEVEL=DURATION", entry)
+		}
+		riskLevel := risk.RiskLevel(strings.ToUpper(strings.TrimSpace(level)))
+		if !riskLevel.Valid() {
+			return nil, fmt.Errorf("unknown cadence risk level %q", level)
+		}
+		interval, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid cadence interval %q for %s", value, level)
+		}
+		cadence[riskLevel] = interval
+	}
+	return cadence, nil
+}
+
+func (c Cadence) interval(level risk.RiskLevel) time.Duration {
+	if interval, ok := c[level]; ok {
+		return interval
+	}
+
+	var shortest time.Duration
+	for _, interval := range c {
+		if shortest == 0 || interval < shortest {
+			shortest = interval
+		}
+	}
+	return shortest
+}
+
+// Schedule works out when each merchant is next due, most overdue first.
+func (c Cadence) Schedule(states []MerchantState, now time.Time) []MerchantSchedule {
+	schedules := make([]MerchantSchedule, 0, len(states))
+	for _, state := range states {
+		next := now
+		if state.LastEvaluatedAt != nil {
+			next = state.LastEvaluatedAt.Add(c.interval(state.RiskLevel))
+		}
+		if state.PendingChangeAt != nil && state.PendingChangeAt.Before(next) {
+			next = *state.PendingChangeAt
+		}
+		schedules = append(schedules, MerchantSchedule{
+			MerchantState:    state,
+			NextEvaluationAt: next,
+			Due:              !next.After(now),
+		})
+	}
+
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].NextEvaluationAt.Before(schedules[j].NextEvaluationAt)
+	})
+	return schedules
+}
@@ -0,0 +1,283 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+// lockKey is the Postgres advisory lock that lets only one instance run a
+// cycle at a time.
+const lockKey int64 = 0x70617961_00000001
+
+var ErrCycleLocked = errors.New("another instance is running a re-evaluation cycle")
+
+type Evaluator interface {
+	EvaluateMerchant(ctx context.Context, merchantID uuid.UUID, simulation bool) (*risk.RiskDecision, error)
+	ReevaluateMetricChange(ctx context.Context, change merchant.MetricChange) (*risk.RiskDecision, error)
+}
+
+type Repository interface {
+	ListMerchantStates(ctx context.Context) ([]MerchantState, error)
+	CreateRun(ctx context.Context, run *Run) error
+	UpdateRun(ctx context.Context, run *Run) error
+	ListRuns(ctx context.Context, limit, offset int) ([]Run, int64, error)
+}
+
+// Locker runs fn while holding a cluster-wide lock. It reports false without
+// calling fn when another holder has the lock.
+type Locker interface {
+	WithLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error)
+}
+
+type Config struct {
+	Cadence Cadence
+	// Interval is how often the scheduler checks for due merchants.
+	Interval time.Duration
+	// BatchSize caps how many merchants one cycle re-evaluates; the rest wait
+	// for the next cycle.
+	BatchSize  int
+	InstanceID string
+}
+
+// Status describes the scheduler's configuration and when it next checks for
+// due merchants.
+type Status struct {
+	Running     bool                      `json:"running"`
+	InstanceID  string                    `json:"instance_id"`
+	Interval    string                    `json:"interval"`
+	BatchSize   int                       `json:"batch_size"`
+	Cadence     map[risk.RiskLevel]string `json:"cadence"`
+	NextCycleAt *time.Time                `json:"next_cycle_at,omitempty"`
+}
+
+// Scheduler periodically re-evaluates merchants whose latest decision is
+// older than their tier's cadence.
+type Scheduler struct {
+	evaluator Evaluator
+	store     Repository
+	locker    Locker
+	config    Config
+
+	mu          sync.Mutex
+	nextCycleAt *time.Time
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+func New(evaluator Evaluator, store Repository, locker Locker, config Config) *Scheduler {
+	if config.Cadence == nil {
+		config.Cadence = DefaultCadence()
+	}
+	if config.Interval <= 0 {
+		config.Interval = 15 * time.Minute
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 200
+	}
+
+	return &Scheduler{
+		evaluator: evaluator,
+		store:     store,
+		locker:    locker,
+		config:    config,
+	}
+}
+
+// Start runs a cycle immediately and then every Interval until Stop is called.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.loop(ctx, s.done)
+	log.Printf("[INFO] Scheduler started on %s (interval=%s, batch=%d)",
+		s.config.InstanceID, s.config.Interval, s.config.BatchSize)
+}
+
+// Stop cancels the running cycle, if any, and waits for the loop to exit.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done, s.nextCycleAt = nil, nil, nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	log.Println("[INFO] Scheduler stopped")
+}
+
+func (s *Scheduler) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunCycle(ctx); err != nil && !errors.Is(err, ErrCycleLocked) {
+			log.Printf("[ERROR] Scheduled re-evaluation cycle failed: %v", err)
+		}
+
+		next := time.Now().Add(s.config.Interval)
+		s.mu.Lock()
+		s.nextCycleAt = &next
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunCycle re-evaluates the merchants currently due, up to BatchSize, and
+// records the run. It returns ErrCycleLocked when another instance holds the
+// scheduler lock.
+func (s *Scheduler) RunCycle(ctx context.Context) (*Run, error) {
+	var run *Run
+	acquired, err := s.locker.WithLock(ctx, lockKey, func(ctx context.Context) error {
+		var err error
+		run, err = s.cycle(ctx)
+		return err
+	})
+	if err != nil {
+		return run, err
+	}
+	if !acquired {
+		log.Printf("[INFO] Skipping re-evaluation cycle on %s: lock held by another instance", s.config.InstanceID)
+		return nil, ErrCycleLocked
+	}
+	return run, nil
+}
+
+func (s *Scheduler) cycle(ctx context.Context) (*Run, error) {
+	run := &Run{InstanceID: s.config.InstanceID, Status: RunRunning, StartedAt: time.Now()}
+	if err := s.store.CreateRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to record scheduler run: %w", err)
+	}
+
+	due, err := s.due(ctx, run.StartedAt)
+	if err != nil {
+		return run, s.finish(run, err)
+	}
+	run.MerchantsDue = len(due)
+	if len(due) > s.config.BatchSize {
+		due = due[:s.config.BatchSize]
+	}
+
+	log.Printf("[INFO] Scheduler run %s: %d merchants due, evaluating %d", run.ID, run.MerchantsDue, len(due))
+
+	for _, schedule := range due {
+		if ctx.Err() != nil {
+			return run, s.finish(run, ctx.Err())
+		}
+		if err := s.evaluate(ctx, schedule); err != nil {
+			log.Printf("[ERROR] Scheduled re-evaluation of merchant %s failed: %v", schedule.MerchantID, err)
+			run.MerchantsFailed++
+			continue
+		}
+		run.MerchantsEvaluated++
+	}
+
+	return run, s.finish(run, nil)
+}
+
+// evaluate persists a new decision for the merchant. A pending metric change
+// is re-evaluated as that change, so the decision links to its snapshot even
+// when the change never made it through the re-evaluation queue.
+func (s *Scheduler) evaluate(ctx context.Context, schedule MerchantSchedule) error {
+	if schedule.PendingChangeID != nil {
+		_, err := s.evaluator.ReevaluateMetricChange(ctx, merchant.MetricChange{
+			MerchantID: schedule.MerchantID,
+			SnapshotID: *schedule.PendingChangeID,
+			ChangedAt:  *schedule.PendingChangeAt,
+		})
+		return err
+	}
+	_, err := s.evaluator.EvaluateMerchant(ctx, schedule.MerchantID, false)
+	return err
+}
+
+// finish records the run's outcome. The update uses a fresh context so a
+// cancelled cycle is still recorded as failed.
+func (s *Scheduler) finish(run *Run, cause error) error {
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.Status = RunCompleted
+	if cause != nil {
+		run.Status = RunFailed
+		run.Error = cause.Error()
+	}
+
+	if err := s.store.UpdateRun(context.Background(), run); err != nil {
+		return fmt.Errorf("failed to update scheduler run: %w", err)
+	}
+
+	log.Printf("[INFO] Scheduler run %s %s: %d evaluated, %d failed",
+		run.ID, run.Status, run.MerchantsEvaluated, run.MerchantsFailed)
+	return cause
+}
+
+func (s *Scheduler) due(ctx context.Context, now time.Time) ([]MerchantSchedule, error) {
+	schedules, err := s.Schedules(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, schedule := range schedules {
+		if !schedule.Due {
+			return schedules[:i], nil
+		}
+	}
+	return schedules, nil
+}
+
+// Schedules returns every merchant's next evaluation time as of now, most
+// overdue first.
+func (s *Scheduler) Schedules(ctx context.Context, now time.Time) ([]MerchantSchedule, error) {
+	states, err := s.store.ListMerchantStates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merchant states: %w", err)
+	}
+	return s.config.Cadence.Schedule(states, now), nil
+}
+
+func (s *Scheduler) Runs(ctx context.Context, limit, offset int) ([]Run, int64, error) {
+	return s.store.ListRuns(ctx, limit, offset)
+}
+
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cadence := make(map[risk.RiskLevel]string, len(s.config.Cadence))
+	for level, interval := range s.config.Cadence {
+		cadence[level] = interval.String()
+	}
+
+	return Status{
+		Running:     s.cancel != nil,
+		InstanceID:  s.config.InstanceID,
+		Interval:    s.config.Interval.String(),
+		BatchSize:   s.config.BatchSize,
+		Cadence:     cadence,
+		NextCycleAt: s.nextCycleAt,
+	}
+}
@@ -0,0 +1,215 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+)
+
+type mockRepository struct {
+	states []MerchantState
+	runs   []Run
+}
+
+func (m *mockRepository) ListMerchantStates(ctx context.Context) ([]MerchantState, error) {
+	return m.states, nil
+}
+
+func (m *mockRepository) CreateRun(ctx context.Context, run *Run) error {
+	run.ID = uuid.New()
+	return nil
+}
+
+func (m *mockRepository) UpdateRun(ctx context.Context, run *Run) error {
+	m.runs = append(m.runs, *run)
+	return nil
+}
+
+func (m *mockRepository) ListRuns(ctx context.Context, limit, offset int) ([]Run, int64, error) {
+	return m.runs, int64(len(m.runs)), nil
+}
+
+type mockLocker struct {
+	held bool
+}
+
+func (m *mockLocker) WithLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error) {
+	if m.held {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+type mockEvaluator struct {
+	evaluated []uuid.UUID
+	triggers  map[uuid.UUID]uuid.UUID
+	fail      map[uuid.UUID]bool
+}
+
+func (m *mockEvaluator) EvaluateMerchant(ctx context.Context, merchantID uuid.UUID, simulation bool) (*risk.RiskDecision, error) {
+	if simulation {
+		return nil, errors.New("scheduled evaluations must persist")
+	}
+	m.evaluated = append(m.evaluated, merchantID)
+	if m.fail[merchantID] {
+		return nil, errors.New("merchant not found")
+	}
+	return &risk.RiskDecision{MerchantID: merchantID}, nil
+}
+
+func (m *mockEvaluator) ReevaluateMetricChange(ctx context.Context, change merchant.MetricChange) (*risk.RiskDecision, error) {
+	if m.triggers == nil {
+		m.triggers = make(map[uuid.UUID]uuid.UUID)
+	}
+	m.triggers[change.MerchantID] = change.SnapshotID
+	decision, err := m.EvaluateMerchant(ctx, change.MerchantID, false)
+	if err != nil {
+		return nil, err
+	}
+	decision.TriggerSnapshotID = &change.SnapshotID
+	return decision, nil
+}
+
+func state(level risk.RiskLevel, evaluatedAgo time.Duration, now time.Time) MerchantState {
+	evaluatedAt := now.Add(-evaluatedAgo)
+	return MerchantState{MerchantID: uuid.New(), RiskLevel: level, LastEvaluatedAt: &evaluatedAt}
+}
+
+func TestCadenceSchedule(t *testing.T) {
+	now := time.Now()
+	never := MerchantState{MerchantID: uuid.New()}
+	critical := state(risk.RiskLevelCritical, 30*time.Hour, now)
+	lowRecent := state(risk.RiskLevelLow, 10*24*time.Hour, now)
+	highOverdue := state(risk.RiskLevelHigh, 5*24*time.Hour, now)
+
+	schedules := DefaultCadence().Schedule([]MerchantState{lowRecent, critical, never, highOverdue}, now)
+
+	order := []uuid.UUID{highOverdue.MerchantID, critical.MerchantID, never.MerchantID, lowRecent.MerchantID}
+	for i, id := range order {
+		if schedules[i].MerchantID != id {
+			t.Fatalf("position %d: expected merchant %s, got %s", i, id, schedules[i].MerchantID)
+		}
+	}
+	if !schedules[2].Due || !schedules[2].NextEvaluationAt.Equal(now) {
+		t.Errorf("expected never-evaluated merchant due now, got %+v", schedules[2])
+	}
+	if schedules[3].Due {
+		t.Errorf("expected LOW merchant evaluated 10 days ago not to be due")
+	}
+}
+
+func TestCadenceSchedulePendingChange(t *testing.T) {
+	now := time.Now()
+	changed := state(risk.RiskLevelLow, 10*24*time.Hour, now)
+	changedAt := now.Add(-time.Hour)
+	changed.PendingChangeAt = &changedAt
+	unchanged := state(risk.RiskLevelLow, 10*24*time.Hour, now)
+
+	schedules := DefaultCadence().Schedule([]MerchantState{unchanged, changed}, now)
+
+	if schedules[0].MerchantID != changed.MerchantID || !schedules[0].Due {
+		t.Fatalf("expected merchant with a pending metric change due first, got %+v", schedules[0])
+	}
+	if !schedules[0].NextEvaluationAt.Equal(changedAt) {
+		t.Errorf("expected merchant due from %s, got %s", changedAt, schedules[0].NextEvaluationAt)
+	}
+	if schedules[1].Due {
+		t.Errorf("expected unchanged LOW merchant not to be due")
+	}
+}
+
+func TestParseCadence(t *testing.T) {
+	cadence, err := ParseCadence("critical=12h, LOW=720h")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cadence[risk.RiskLevelCritical] != 12*time.Hour || cadence[risk.RiskLevelLow] != 720*time.Hour {
+		t.Errorf("expected overridden intervals, got %v", cadence)
+	}
+	if cadence[risk.RiskLevelMedium] != 7*24*time.Hour {
+		t.Errorf("expected default MEDIUM interval, got %s", cadence[risk.RiskLevelMedium])
+	}
+
+	for _, spec := range []string{"CRITICAL", "HIGH=soon", "LOW=-1h", "CRTICAL=1h"} {
+		if _, err := ParseCadence(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestRunCycle(t *testing.T) {
+	now := time.Now()
+	failing := state(risk.RiskLevelCritical, 48*time.Hour, now)
+	repo := &mockRepository{states: []MerchantState{
+		failing,
+		state(risk.RiskLevelHigh, 4*24*time.Hour, now),
+		{MerchantID: uuid.New()},
+		state(risk.RiskLevelLow, time.Hour, now),
+	}}
+	evaluator := &mockEvaluator{fail: map[uuid.UUID]bool{failing.MerchantID: true}}
+
+	t.Run("evaluates due merchants up to the batch size", func(t *testing.T) {
+		s := New(evaluator, repo, &mockLocker{}, Config{BatchSize: 2})
+
+		run, err := s.RunCycle(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if run.MerchantsDue != 3 || len(evaluator.evaluated) != 2 {
+			t.Errorf("expected 3 due and 2 evaluated, got %d due and %d evaluated", run.MerchantsDue, len(evaluator.evaluated))
+		}
+		if run.MerchantsEvaluated != 1 || run.MerchantsFailed != 1 || run.Status != RunCompleted || run.FinishedAt == nil {
+			t.Errorf("expected completed run with one failure, got %+v", run)
+		}
+		if len(repo.runs) != 1 {
+			t.Errorf("expected run to be recorded, got %d", len(repo.runs))
+		}
+	})
+
+	t.Run("pending changes are re-evaluated as their snapshot", func(t *testing.T) {
+		changed := state(risk.RiskLevelLow, time.Hour, now)
+		snapshotID, changedAt := uuid.New(), now.Add(-time.Minute)
+		changed.PendingChangeID, changed.PendingChangeAt = &snapshotID, &changedAt
+		scheduled := MerchantState{MerchantID: uuid.New()}
+		evaluator := &mockEvaluator{}
+		s := New(evaluator, &mockRepository{states: []MerchantState{changed, scheduled}}, &mockLocker{}, Config{})
+
+		run, err := s.RunCycle(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if run.MerchantsEvaluated != 2 {
+			t.Fatalf("expected 2 evaluated, got %+v", run)
+		}
+		if len(evaluator.triggers) != 1 || evaluator.triggers[changed.MerchantID] != snapshotID {
+			t.Errorf("expected only the pending change to be re-evaluated with its snapshot, got %v", evaluator.triggers)
+		}
+	})
+
+	t.Run("skips when another instance holds the lock", func(t *testing.T) {
+		s := New(evaluator, repo, &mockLocker{held: true}, Config{})
+
+		if _, err := s.RunCycle(context.Background()); !errors.Is(err, ErrCycleLocked) {
+			t.Errorf("expected ErrCycleLocked, got %v", err)
+		}
+	})
+
+	t.Run("cancelled cycle is recorded as failed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		s := New(&mockEvaluator{}, repo, &mockLocker{}, Config{})
+
+		run, err := s.RunCycle(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if run.Status != RunFailed || run.MerchantsEvaluated != 0 {
+			t.Errorf("expected failed run without evaluations, got %+v", run)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/backtest"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+	"gorm.io/gorm"
+)
+
+type BacktestStore struct {
+	db *gorm.DB
+}
+
+func NewBacktestStore(db *gorm.DB) *BacktestStore {
+	return &BacktestStore{db: db}
+}
+
+func (s *BacktestStore) Create(ctx context.Context, job *backtest.Job) error {
+	if err := s.db.WithContext(ctx).Create(job).Error; err != nil {
+		return fmt.Errorf("failed to create backtest: %w", err)
+	}
+	return nil
+}
+
+func (s *BacktestStore) Get(ctx context.Context, id uuid.UUID) (*backtest.Job, error) {
+	var job backtest.Job
+	if err := s.db.WithContext(ctx).First(&job, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("%w: %s", backtest.ErrJobNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get backtest: %w", err)
+	}
+	return &job, nil
+}
+
+func (s *BacktestStore) List(ctx context.Context, limit, offset int) ([]backtest.Job, int64, error) {
+	var jobs []backtest.Job
+	var total int64
+
+	query := s.db.WithContext(ctx).Model(&backtest.Job{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count backtests: %w", err)
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&jobs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list backtests: %w", err)
+	}
+
+	return jobs, total, nil
+}
+
+func (s *BacktestStore) AppendRows(ctx context.Context, id uuid.UUID, position int, rows []risk.BacktestRow) error {
+	records := make([]backtest.Row, len(rows))
+	for i, row := range rows {
+		records[i] = backtest.Row{BacktestID: id, Position: position + i, BacktestRow: row}
+	}
+	if err := s.db.WithContext(ctx).Create(&records).Error; err != nil {
+		return fmt.Errorf("failed to append backtest rows: %w", err)
+	}
+	return nil
+}
+
+func (s *BacktestStore) ListRows(ctx context.Context, id uuid.UUID, position, limit int) ([]risk.BacktestRow, error) {
+	var records []backtest.Row
+	if err := s.db.WithContext(ctx).
+		Where("backtest_id = ? AND position >= ?", id, position).
+		Order("position").
+		Limit(limit).
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list backtest rows: %w", err)
+	}
+
+	rows := make([]risk.BacktestRow, len(records))
+	for i, record := range records {
+		rows[i] = record.BacktestRow
+	}
+	return rows, nil
+}
+
+func (s *BacktestStore) Finish(ctx context.Context, job *backtest.Job) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&backtest.Job{}).
+			Where("id = ? AND status = ?", job.ID, backtest.StatusRunning).
+			Updates(map[string]interface{}{
+				"status":      job.Status,
+				"summary":     job.Summary,
+				"error":       job.Error,
+				"finished_at": job.FinishedAt,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to finish backtest: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %s", backtest.ErrJobNotRunning, job.ID)
+		}
+
+		// A failed job has no report, so drop whatever it wrote.
+		if job.Status == backtest.StatusFailed {
+			if err := tx.Where("backtest_id = ?", job.ID).Delete(&backtest.Row{}).Error; err != nil {
+				return fmt.Errorf("failed to delete backtest rows: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *BacktestStore) Heartbeat(ctx context.Context, id uuid.UUID) error {
+	result := s.db.WithContext(ctx).
+		Model(&backtest.Job{}).
+		Where("id = ? AND status = ?", id, backtest.StatusRunning).
+		Update("heartbeat_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to record backtest heartbeat: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", backtest.ErrJobNotRunning, id)
+	}
+	return nil
+}
+
+func (s *BacktestStore) FailStale(ctx context.Context, before time.Time, reason string) (int64, error) {
+	var failed int64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Raw(`
+			UPDATE backtests
+			SET status = ?, error = ?, finished_at = ?
+			WHERE status = ? AND heartbeat_at < ?
+			RETURNING id`, backtest.StatusFailed, reason, time.Now(), backtest.StatusRunning, before).
+			Scan(&ids).Error; err != nil {
+			return fmt.Errorf("failed to fail stale backtests: %w", err)
+		}
+		failed = int64(len(ids))
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Where("backtest_id IN ?", ids).Delete(&backtest.Row{}).Error; err != nil {
+			return fmt.Errorf("failed to delete backtest rows: %w", err)
+		}
+		return nil
+	})
+	return failed, err
+}
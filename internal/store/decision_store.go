@@ -3,8 +3,10 @@ package store
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/outbox"
 	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
 	"gorm.io/gorm"
 )
@@ -17,11 +19,73 @@ func NewDecisionStore(db *gorm.DB) *DecisionStore {
 	return &DecisionStore{db: db}
 }
 
+// Create inserts the decision and, for persisted evaluations, its outbox
+// events in the same transaction, so events are written if and only if the
+// decision is. Older decisions for the merchant still pending approval are
+// marked SUPERSEDED, so only the latest can be approved.
 func (s *DecisionStore) Create(ctx context.Context, decision *risk.RiskDecision) error {
-	if err := s.db.WithContext(ctx).Create(decision).Error; err != nil {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createDecision(tx, decision, nil)
+	})
+}
+
+// createDecision inserts decision and its events. previous caches the latest
+// EFFECTIVE decision per merchant across a bulk insert; nil looks it up each
+// time.
+func createDecision(tx *gorm.DB, decision *risk.RiskDecision, previous map[uuid.UUID]*risk.RiskDecision) error {
+	var prior *risk.RiskDecision
+	if !decision.Simulation {
+		var err error
+		if prior, err = lastEffectiveDecision(tx, decision.MerchantID, uuid.Nil, previous); err != nil {
+			return err
+		}
+		if err := tx.Model(&risk.RiskDecision{}).
+			Where("merchant_id = ? AND simulation = false AND status = ? AND evaluated_at <= ?",
+				decision.MerchantID, risk.DecisionPendingApproval, decision.EvaluatedAt).
+			Update("status", risk.DecisionSuperseded).Error; err != nil {
+			return fmt.Errorf("failed to supersede pending decisions: %w", err)
+		}
+	}
+
+	if err := tx.Create(decision).Error; err != nil {
 		return fmt.Errorf("failed to create decision: %w", err)
 	}
-	return nil
+	if decision.Simulation {
+		return nil
+	}
+	if previous != nil && decision.Status == risk.DecisionEffective {
+		previous[decision.MerchantID] = decision
+	}
+
+	events, err := outbox.DecisionEvents(decision, prior)
+	if err != nil {
+		return err
+	}
+	return createEvents(tx, events)
+}
+
+// lastEffectiveDecision returns the merchant's latest persisted EFFECTIVE
+// decision other than exclude, or nil if there is none. Pending and rejected
+// decisions never set the policy in force, so tier changes are measured
+// against this one.
+func lastEffectiveDecision(tx *gorm.DB, merchantID, exclude uuid.UUID, cache map[uuid.UUID]*risk.RiskDecision) (*risk.RiskDecision, error) {
+	if d, ok := cache[merchantID]; ok {
+		return d, nil
+	}
+
+	var d risk.RiskDecision
+	err := tx.
+		Where("merchant_id = ? AND simulation = false AND status = ? AND id <> ?",
+			merchantID, risk.DecisionEffective, exclude).
+		Order("evaluated_at DESC").
+		First(&d).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previous decision: %w", err)
+	}
+	return &d, nil
 }
 
 func (s *DecisionStore) GetLatestByMerchant(ctx context.Context, merchantID uuid.UUID) (*risk.RiskDecision, error) {
@@ -38,6 +102,138 @@ func (s *DecisionStore) GetLatestByMerchant(ctx context.Context, merchantID uuid
 	return &decision, nil
 }
 
+func (s *DecisionStore) Get(ctx context.Context, id uuid.UUID) (*risk.RiskDecision, error) {
+	var decision risk.RiskDecision
+	if err := s.db.WithContext(ctx).First(&decision, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("%w: %s", risk.ErrDecisionNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get decision: %w", err)
+	}
+	return &decision, nil
+}
+
+// GetLatestEffectiveByMerchant returns the merchant's newest persisted
+// decision whose policy is in force, skipping pending and rejected ones.
+func (s *DecisionStore) GetLatestEffectiveByMerchant(ctx context.Context, merchantID uuid.UUID) (*risk.RiskDecision, error) {
+	var decision risk.RiskDecision
+	if err := s.db.WithContext(ctx).
+		Where("merchant_id = ? AND simulation = false AND status = ?", merchantID, risk.DecisionEffective).
+		Order("evaluated_at DESC").
+		First(&decision).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest effective decision: %w", err)
+	}
+	return &decision, nil
+}
+
+// ListPending returns decisions awaiting approval, oldest first.
+func (s *DecisionStore) ListPending(ctx context.Context, limit, offset int) ([]risk.RiskDecision, int64, error) {
+	var decisions []risk.RiskDecision
+	var total int64
+
+	query := s.db.WithContext(ctx).Model(&risk.RiskDecision{}).
+		Where("simulation = false AND status = ?", risk.DecisionPendingApproval)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count pending decisions: %w", err)
+	}
+
+	if err := query.
+		Order("evaluated_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&decisions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list pending decisions: %w", err)
+	}
+
+	return decisions, total, nil
+}
+
+// ListForBacktest returns persisted, non-rejected decisions evaluated in
+// [from, to), oldest first.
+func (s *DecisionStore) ListForBacktest(ctx context.Context, from, to time.Time, limit, offset int) ([]risk.RiskDecision, error) {
+	var decisions []risk.RiskDecision
+	if err := s.db.WithContext(ctx).
+		Where("simulation = false AND status <> ?", risk.DecisionRejected).
+		Where("evaluated_at >= ? AND evaluated_at < ?", from, to).
+		Order("evaluated_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&decisions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list decisions for backtest: %w", err)
+	}
+	return decisions, nil
+}
+
+// Review records the approval or rejection of a pending decision together
+// with its risk.decision.reviewed outbox event. An approval that puts a new
+// policy in force also writes risk.tier.changed; approving a decision older
+// than the one in force does not.
+func (s *DecisionStore) Review(ctx context.Context, id uuid.UUID, status risk.DecisionStatus, reviewedBy, note string, at time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&risk.RiskDecision{}).
+			Where("id = ? AND status = ?", id, risk.DecisionPendingApproval).
+			Updates(map[string]interface{}{
+				"status":      status,
+				"reviewed_by": reviewedBy,
+				"reviewed_at": at,
+				"review_note": note,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to review decision: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %s", risk.ErrDecisionNotPending, id)
+		}
+
+		var decision risk.RiskDecision
+		if err := tx.First(&decision, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to reload decision: %w", err)
+		}
+		event, err := outbox.ReviewEvent(&decision)
+		if err != nil {
+			return err
+		}
+		events := []outbox.Event{event}
+
+		if status == risk.DecisionEffective {
+			prior, err := lastEffectiveDecision(tx, decision.MerchantID, decision.ID, nil)
+			if err != nil {
+				return err
+			}
+			if prior != nil && !prior.EvaluatedAt.After(decision.EvaluatedAt) {
+				changed, ok, err := outbox.TierChangeEvent(&decision, prior, at)
+				if err != nil {
+					return err
+				}
+				if ok {
+					events = append(events, changed)
+				}
+			}
+		}
+		return createEvents(tx, events)
+	})
+}
+
+// ListRecentByMerchant returns up to limit persisted EFFECTIVE decisions for
+// the merchant, newest first. Pending and rejected decisions never set the
+// merchant's tier, so hysteresis must not hold the merchant at them.
+func (s *DecisionStore) ListRecentByMerchant(ctx context.Context, merchantID uuid.UUID, limit int) ([]risk.RiskDecision, error) {
+	var decisions []risk.RiskDecision
+	if err := s.db.WithContext(ctx).
+		Where("merchant_id = ? AND simulation = false AND status = ?", merchantID, risk.DecisionEffective).
+		Order("evaluated_at DESC").
+		Limit(limit).
+		Find(&decisions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list recent decisions: %w", err)
+	}
+	return decisions, nil
+}
+
 func (s *DecisionStore) ListByBatch(ctx context.Context, batchID uuid.UUID) ([]risk.RiskDecision, error) {
 	var decisions []risk.RiskDecision
 	if err := s.db.WithContext(ctx).
@@ -48,9 +244,55 @@ func (s *DecisionStore) ListByBatch(ctx context.Context, batchID uuid.UUID) ([]r
 	return decisions, nil
 }
 
-func (s *DecisionStore) BulkCreate(ctx context.Context, decisions []risk.RiskDecision) error {
-	if err := s.db.WithContext(ctx).Create(&decisions).Error; err != nil {
-		return fmt.Errorf("failed to bulk create decisions: %w", err)
+func (s *DecisionStore) ListByModelVersion(ctx context.Context, version string, limit, offset int) ([]risk.RiskDecision, int64, error) {
+	var decisions []risk.RiskDecision
+	var total int64
+
+	query := s.db.WithContext(ctx).Model(&risk.RiskDecision{}).Where("simulation = false")
+	if version != "" {
+		query = query.Where("model_version = ?", version)
 	}
-	return nil
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count decisions: %w", err)
+	}
+
+	if err := query.
+		Limit(limit).
+		Offset(offset).
+		Order("evaluated_at DESC").
+		Find(&decisions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list decisions by model version: %w", err)
+	}
+
+	return decisions, total, nil
+}
+
+func (s *DecisionStore) ListModelVersions(ctx context.Context) ([]risk.ModelVersionSummary, error) {
+	var versions []risk.ModelVersionSummary
+	if err := s.db.WithContext(ctx).
+		Model(&risk.RiskDecision{}).
+		Select("model_version AS version, COUNT(*) AS decision_count, " +
+			"MIN(evaluated_at) AS first_seen_at, MAX(evaluated_at) AS last_seen_at").
+		Where("simulation = false").
+		Group("model_version").
+		Order("last_seen_at DESC").
+		Scan(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list model versions: %w", err)
+	}
+	return versions, nil
+}
+
+// BulkCreate inserts the decisions and their outbox events in one
+// transaction. Decisions for the same merchant are compared in slice order.
+func (s *DecisionStore) BulkCreate(ctx context.Context, decisions []risk.RiskDecision) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		previous := make(map[uuid.UUID]*risk.RiskDecision)
+		for i := range decisions {
+			if err := createDecision(tx, &decisions[i], previous); err != nil {
+				return fmt.Errorf("failed to bulk create decisions: %w", err)
+			}
+		}
+		return nil
+	})
 }
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ManualOverrideStore struct {
+	db *gorm.DB
+}
+
+func NewManualOverrideStore(db *gorm.DB) *ManualOverrideStore {
+	return &ManualOverrideStore{db: db}
+}
+
+// Create records the override unless the merchant already has an active one,
+// returning risk.ErrActiveManualOverride. The merchant row is locked for the
+// check so concurrent requests cannot both create an override.
+func (s *ManualOverrideStore) Create(ctx context.Context, override *risk.ManualOverride) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked merchant.Merchant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&locked, "id = ?", override.MerchantID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("merchant not found: %s", override.MerchantID)
+			}
+			return fmt.Errorf("failed to lock merchant: %w", err)
+		}
+
+		var active int64
+		if err := tx.Model(&risk.ManualOverride{}).
+			Where("merchant_id = ? AND revoked_at IS NULL AND expires_at > NOW()", override.MerchantID).
+			Count(&active).Error; err != nil {
+			return fmt.Errorf("failed to check active manual override: %w", err)
+		}
+		if active > 0 {
+			return fmt.Errorf("%w: %s", risk.ErrActiveManualOverride, override.MerchantID)
+		}
+
+		if err := tx.Create(override).Error; err != nil {
+			return fmt.Errorf("failed to create manual override: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *ManualOverrideStore) Get(ctx context.Context, id uuid.UUID) (*risk.ManualOverride, error) {
+	var override risk.ManualOverride
+	if err := s.db.WithContext(ctx).First(&override, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("manual override not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get manual override: %w", err)
+	}
+	return &override, nil
+}
+
+// GetActive returns the merchant's most recent override that is neither
+// revoked nor expired at the given time, or nil when there is none.
+func (s *ManualOverrideStore) GetActive(ctx context.Context, merchantID uuid.UUID, at time.Time) (*risk.ManualOverride, error) {
+	var override risk.ManualOverride
+	if err := s.db.WithContext(ctx).
+		Where("merchant_id = ? AND revoked_at IS NULL AND expires_at > ?", merchantID, at).
+		Order("created_at DESC").
+		First(&override).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get active manual override: %w", err)
+	}
+	return &override, nil
+}
+
+// ListByMerchant returns every override recorded for the merchant, including
+// expired and revoked ones, newest first.
+func (s *ManualOverrideStore) ListByMerchant(ctx context.Context, merchantID uuid.UUID) ([]risk.ManualOverride, error) {
+	var overrides []risk.ManualOverride
+	if err := s.db.WithContext(ctx).
+		Where("merchant_id = ?", merchantID).
+		Order("created_at DESC").
+		Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to list manual overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+func (s *ManualOverrideStore) Revoke(ctx context.Context, id uuid.UUID, revokedBy, reason string, at time.Time) error {
+	result := s.db.WithContext(ctx).
+		Model(&risk.ManualOverride{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":    at,
+			"revoked_by":    revokedBy,
+			"revoke_reason": reason,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke manual override: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("manual override not found: %s", id)
+	}
+	return nil
+}
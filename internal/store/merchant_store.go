@@ -3,9 +3,11 @@ package store
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
 	"gorm.io/gorm"
 )
 
@@ -17,18 +19,25 @@ func NewMerchantStore(db *gorm.DB) *MerchantStore {
 	return &MerchantStore{db: db}
 }
 
+// Create inserts the merchant together with its first metric snapshot.
 func (s *MerchantStore) Create(ctx context.Context, m *merchant.Merchant) error {
-	if err := s.db.WithContext(ctx).Create(m).Error; err != nil {
-		return fmt.Errorf("failed to create merchant: %w", err)
-	}
-	return nil
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(m).Error; err != nil {
+			return fmt.Errorf("failed to create merchant: %w", err)
+		}
+		snapshot := merchant.NewMetricSnapshot(m, time.Now())
+		if err := tx.Create(&snapshot).Error; err != nil {
+			return fmt.Errorf("failed to record metric snapshot: %w", err)
+		}
+		return nil
+	})
 }
 
 func (s *MerchantStore) Get(ctx context.Context, id uuid.UUID) (*merchant.Merchant, error) {
 	var m merchant.Merchant
 	if err := s.db.WithContext(ctx).First(&m, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("merchant not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", merchant.ErrMerchantNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to get merchant: %w", err)
 	}
@@ -54,19 +63,75 @@ func (s *MerchantStore) List(ctx context.Context, limit, offset int) ([]merchant
 	return merchants, total, nil
 }
 
-func (s *MerchantStore) Update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
-	if err := s.db.WithContext(ctx).
-		Model(&merchant.Merchant{}).
-		Where("id = ?", id).
-		Updates(updates).Error; err != nil {
-		return fmt.Errorf("failed to update merchant: %w", err)
+// ListForPortfolio pages through the merchants matching filter, ordered by
+// ID so paging is deterministic.
+func (s *MerchantStore) ListForPortfolio(ctx context.Context, filter risk.PortfolioFilter, limit, offset int) ([]merchant.Merchant, error) {
+	query := s.db.WithContext(ctx).Model(&merchant.Merchant{})
+	if len(filter.MerchantIDs) > 0 {
+		query = query.Where("id IN ?", filter.MerchantIDs)
+	}
+	if len(filter.Industries) > 0 {
+		query = query.Where("industry IN ?", filter.Industries)
+	}
+	if len(filter.Countries) > 0 {
+		query = query.Where("country IN ?", filter.Countries)
 	}
-	return nil
+
+	var merchants []merchant.Merchant
+	if err := query.Order("id").Limit(limit).Offset(offset).Find(&merchants).Error; err != nil {
+		return nil, fmt.Errorf("failed to list merchants: %w", err)
+	}
+	return merchants, nil
 }
 
-func (s *MerchantStore) BulkCreate(ctx context.Context, merchants []merchant.Merchant) error {
-	if err := s.db.WithContext(ctx).Create(&merchants).Error; err != nil {
-		return fmt.Errorf("failed to bulk create merchants: %w", err)
+// Update applies the changes and records the resulting metrics as a new
+// snapshot in the same transaction, so history never misses a change. The
+// snapshot is marked reevaluationDue when the scheduler should re-evaluate the
+// merchant. It returns the snapshot.
+func (s *MerchantStore) Update(ctx context.Context, id uuid.UUID, updates map[string]interface{}, reevaluationDue bool) (*merchant.MetricSnapshot, error) {
+	var snapshot merchant.MetricSnapshot
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&merchant.Merchant{}).
+			Where("id = ?", id).
+			Updates(updates)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update merchant: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %s", merchant.ErrMerchantNotFound, id)
+		}
+
+		var m merchant.Merchant
+		if err := tx.First(&m, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to reload merchant: %w", err)
+		}
+		snapshot = merchant.NewMetricSnapshot(&m, time.Now())
+		snapshot.ReevaluationDue = reevaluationDue
+		if err := tx.Create(&snapshot).Error; err != nil {
+			return fmt.Errorf("failed to record metric snapshot: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return &snapshot, nil
+}
+
+func (s *MerchantStore) BulkCreate(ctx context.Context, merchants []merchant.Merchant) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&merchants).Error; err != nil {
+			return fmt.Errorf("failed to bulk create merchants: %w", err)
+		}
+
+		now := time.Now()
+		snapshots := make([]merchant.MetricSnapshot, len(merchants))
+		for i := range merchants {
+			snapshots[i] = merchant.NewMetricSnapshot(&merchants[i], now)
+		}
+		if err := tx.Create(&snapshots).Error; err != nil {
+			return fmt.Errorf("failed to record metric snapshots: %w", err)
+		}
+		return nil
+	})
 }
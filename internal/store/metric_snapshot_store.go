@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/merchant"
+	"gorm.io/gorm"
+)
+
+type MetricSnapshotStore struct {
+	db *gorm.DB
+}
+
+func NewMetricSnapshotStore(db *gorm.DB) *MetricSnapshotStore {
+	return &MetricSnapshotStore{db: db}
+}
+
+// ListByMerchantSince returns the merchant's snapshots captured at or after
+// since, oldest first. The latest snapshot before since is included as well,
+// so callers know the metrics that were in force at since.
+func (s *MetricSnapshotStore) ListByMerchantSince(ctx context.Context, merchantID uuid.UUID, since time.Time) ([]merchant.MetricSnapshot, error) {
+	var snapshots []merchant.MetricSnapshot
+
+	var before merchant.MetricSnapshot
+	err := s.db.WithContext(ctx).
+		Where("merchant_id = ? AND captured_at < ?", merchantID, since).
+		Order("captured_at DESC").
+		First(&before).Error
+	switch {
+	case err == nil:
+		snapshots = append(snapshots, before)
+	case err != gorm.ErrRecordNotFound:
+		return nil, fmt.Errorf("failed to get metric snapshot: %w", err)
+	}
+
+	var recent []merchant.MetricSnapshot
+	if err := s.db.WithContext(ctx).
+		Where("merchant_id = ? AND captured_at >= ?", merchantID, since).
+		Order("captured_at ASC").
+		Find(&recent).Error; err != nil {
+		return nil, fmt.Errorf("failed to list metric snapshots: %w", err)
+	}
+
+	return append(snapshots, recent...), nil
+}
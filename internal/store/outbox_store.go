@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/outbox"
+	"gorm.io/gorm"
+)
+
+type OutboxStore struct {
+	db *gorm.DB
+}
+
+func NewOutboxStore(db *gorm.DB) *OutboxStore {
+	return &OutboxStore{db: db}
+}
+
+// ClaimPending leases due events with SKIP LOCKED, so concurrent relays
+// claim disjoint batches.
+func (s *OutboxStore) ClaimPending(ctx context.Context, limit int, now, leaseUntil time.Time) ([]outbox.Event, error) {
+	var events []outbox.Event
+	if err := s.db.WithContext(ctx).Raw(`
+		UPDATE outbox_events SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE published_at IS NULL AND next_attempt_at <= ?
+			ORDER BY occurred_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, now, limit).
+		Scan(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	// RETURNING does not preserve the subquery's order.
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+	return events, nil
+}
+
+func (s *OutboxStore) MarkPublished(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if err := s.db.WithContext(ctx).
+		Model(&outbox.Event{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"published_at": at,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   "",
+		}).Error; err != nil {
+		return fmt.Errorf("failed to mark outbox event published: %w", err)
+	}
+	return nil
+}
+
+func (s *OutboxStore) MarkFailed(ctx context.Context, id uuid.UUID, cause string, nextAttemptAt time.Time) error {
+	if err := s.db.WithContext(ctx).
+		Model(&outbox.Event{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      cause,
+			"next_attempt_at": nextAttemptAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to reschedule outbox event: %w", err)
+	}
+	return nil
+}
+
+// createEvents writes events inside the caller's transaction.
+func createEvents(tx *gorm.DB, events []outbox.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := tx.Create(&events).Error; err != nil {
+		return fmt.Errorf("failed to write outbox events: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+	"gorm.io/gorm"
+)
+
+type PolicyOverrideStore struct {
+	db *gorm.DB
+}
+
+func NewPolicyOverrideStore(db *gorm.DB) *PolicyOverrideStore {
+	return &PolicyOverrideStore{db: db}
+}
+
+func (s *PolicyOverrideStore) Create(ctx context.Context, override *risk.PolicyOverride) error {
+	if err := s.db.WithContext(ctx).Create(override).Error; err != nil {
+		return fmt.Errorf("failed to create policy override: %w", err)
+	}
+	return nil
+}
+
+func (s *PolicyOverrideStore) Get(ctx context.Context, id uuid.UUID) (*risk.PolicyOverride, error) {
+	var override risk.PolicyOverride
+	if err := s.db.WithContext(ctx).First(&override, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("policy override not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get policy override: %w", err)
+	}
+	return &override, nil
+}
+
+func (s *PolicyOverrideStore) List(ctx context.Context) ([]risk.PolicyOverride, error) {
+	var overrides []risk.PolicyOverride
+	if err := s.db.WithContext(ctx).
+		Order("created_at DESC").
+		Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to list policy overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+func (s *PolicyOverrideStore) ListActive(ctx context.Context) ([]risk.PolicyOverride, error) {
+	var overrides []risk.PolicyOverride
+	if err := s.db.WithContext(ctx).
+		Where("active = true").
+		Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to list active policy overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+func (s *PolicyOverrideStore) Deactivate(ctx context.Context, id uuid.UUID) error {
+	result := s.db.WithContext(ctx).
+		Model(&risk.PolicyOverride{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"active":     false,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to deactivate policy override: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("policy override not found: %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/review"
+	"gorm.io/gorm"
+)
+
+type ReviewStore struct {
+	db *gorm.DB
+}
+
+func NewReviewStore(db *gorm.DB) *ReviewStore {
+	return &ReviewStore{db: db}
+}
+
+// Create returns review.ErrCaseExists when the merchant already has an
+// unresolved case, enforced by idx_review_cases_unresolved_merchant.
+func (s *ReviewStore) Create(ctx context.Context, c *review.Case) error {
+	if err := s.db.WithContext(ctx).Omit("Comments").Create(c).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("%w: %s", review.ErrCaseExists, c.MerchantID)
+		}
+		return fmt.Errorf("failed to create review case: %w", err)
+	}
+	return nil
+}
+
+// Get returns the case with its comments, oldest first.
+func (s *ReviewStore) Get(ctx context.Context, id uuid.UUID) (*review.Case, error) {
+	var c review.Case
+	if err := s.db.WithContext(ctx).
+		Preload("Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		First(&c, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("review case not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get review case: %w", err)
+	}
+	return &c, nil
+}
+
+func (s *ReviewStore) GetUnresolvedByMerchant(ctx context.Context, merchantID uuid.UUID) (*review.Case, error) {
+	var c review.Case
+	if err := s.db.WithContext(ctx).
+		Where("merchant_id = ? AND status IN ?", merchantID, review.UnresolvedStatuses).
+		Order("created_at DESC").
+		First(&c).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get unresolved review case: %w", err)
+	}
+	return &c, nil
+}
+
+func (s *ReviewStore) List(ctx context.Context, filter review.Filter, now time.Time) ([]review.Case, int64, error) {
+	var cases []review.Case
+	var total int64
+
+	query := s.db.WithContext(ctx).Model(&review.Case{})
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.RiskLevel != "" {
+		query = query.Where("risk_level = ?", filter.RiskLevel)
+	}
+	if filter.Assignee != "" {
+		query = query.Where("assignee = ?", filter.Assignee)
+	}
+	if filter.MinAge > 0 {
+		query = query.Where("created_at <= ?", now.Add(-filter.MinAge))
+	}
+	if filter.MaxAge > 0 {
+		query = query.Where("created_at >= ?", now.Add(-filter.MaxAge))
+	}
+	if filter.SLABreached {
+		query = query.Where("sla_due_at < COALESCE(resolved_at, ?)", now)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count review cases: %w", err)
+	}
+
+	if err := query.
+		Order("sla_due_at ASC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&cases).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list review cases: %w", err)
+	}
+
+	return cases, total, nil
+}
+
+func (s *ReviewStore) Update(ctx context.Context, c *review.Case) error {
+	result := s.db.WithContext(ctx).
+		Model(&review.Case{}).
+		Where("id = ?", c.ID).
+		Updates(map[string]interface{}{
+			"decision_id": c.DecisionID,
+			"risk_level":  c.RiskLevel,
+			"risk_score":  c.RiskScore,
+			"status":      c.Status,
+			"assignee":    c.Assignee,
+			"sla_due_at":  c.SLADueAt,
+			"resolved_at": c.ResolvedAt,
+			"updated_at":  c.UpdatedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update review case: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("review case not found: %s", c.ID)
+	}
+	return nil
+}
+
+func (s *ReviewStore) AddComment(ctx context.Context, comment *review.Comment) error {
+	if err := s.db.WithContext(ctx).Create(comment).Error; err != nil {
+		return fmt.Errorf("failed to add review comment: %w", err)
+	}
+	return nil
+}
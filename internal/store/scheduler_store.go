@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/yuno-payments/papaya-payout-engine/internal/scheduler"
+	"gorm.io/gorm"
+)
+
+type SchedulerStore struct {
+	db *gorm.DB
+}
+
+func NewSchedulerStore(db *gorm.DB) *SchedulerStore {
+	return &SchedulerStore{db: db}
+}
+
+// WithLock runs fn while holding the Postgres session advisory lock key. The
+// lock and unlock run on one pooled connection, which is held until fn
+// returns.
+func (s *SchedulerStore) WithLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error) {
+	var acquired bool
+	err := s.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", key).Scan(&acquired).Error; err != nil {
+			return fmt.Errorf("failed to acquire scheduler lock: %w", err)
+		}
+		if !acquired {
+			return nil
+		}
+
+		// Unlock even if ctx was cancelled; otherwise the lock would stay
+		// held by the pooled connection.
+		defer func() {
+			if err := conn.WithContext(context.Background()).Exec("SELECT pg_advisory_unlock(?)", key).Error; err != nil {
+				log.Printf("[ERROR] Failed to release scheduler lock: %v", err)
+			}
+		}()
+
+		return fn(ctx)
+	})
+	return acquired, err
+}
+
+// ListMerchantStates returns every merchant with the risk level of its latest
+// EFFECTIVE decision and the time of its latest persisted decision. Pending
+// and rejected decisions do not set the cadence, but they do count as an
+// evaluation so the merchant is not re-evaluated every cycle while one waits.
+// pending_change_id and pending_change_at identify the latest snapshot marked
+// reevaluation_due that no decision has covered yet.
+func (s *SchedulerStore) ListMerchantStates(ctx context.Context) ([]scheduler.MerchantState, error) {
+	var states []scheduler.MerchantState
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT m.id AS merchant_id,
+		       COALESCE(e.risk_level, '') AS risk_level,
+		       d.evaluated_at AS last_evaluated_at,
+		       p.id AS pending_change_id,
+		       p.captured_at AS pending_change_at
+		FROM merchants m
+		LEFT JOIN LATERAL (
+			SELECT evaluated_at
+			FROM risk_decisions
+			WHERE merchant_id = m.id AND simulation = false
+			ORDER BY evaluated_at DESC
+			LIMIT 1
+		) d ON true
+		LEFT JOIN LATERAL (
+			SELECT risk_level
+			FROM risk_decisions
+			WHERE merchant_id = m.id AND simulation = false AND status = 'EFFECTIVE'
+			ORDER BY evaluated_at DESC
+			LIMIT 1
+		) e ON true
+		LEFT JOIN LATERAL (
+			SELECT id, captured_at
+			FROM merchant_metric_snapshots
+			WHERE merchant_id = m.id AND reevaluation_due
+			  AND (d.evaluated_at IS NULL OR captured_at > d.evaluated_at)
+			ORDER BY captured_at DESC
+			LIMIT 1
+		) p ON true`).
+		Scan(&states).Error; err != nil {
+		return nil, fmt.Errorf("failed to list merchant states: %w", err)
+	}
+	return states, nil
+}
+
+func (s *SchedulerStore) CreateRun(ctx context.Context, run *scheduler.Run) error {
+	if err := s.db.WithContext(ctx).Create(run).Error; err != nil {
+		return fmt.Errorf("failed to create scheduler run: %w", err)
+	}
+	return nil
+}
+
+func (s *SchedulerStore) UpdateRun(ctx context.Context, run *scheduler.Run) error {
+	if err := s.db.WithContext(ctx).Save(run).Error; err != nil {
+		return fmt.Errorf("failed to update scheduler run: %w", err)
+	}
+	return nil
+}
+
+func (s *SchedulerStore) ListRuns(ctx context.Context, limit, offset int) ([]scheduler.Run, int64, error) {
+	var runs []scheduler.Run
+	var total int64
+
+	query := s.db.WithContext(ctx).Model(&scheduler.Run{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count scheduler runs: %w", err)
+	}
+
+	if err := query.
+		Order("started_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&runs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list scheduler runs: %w", err)
+	}
+
+	return runs, total, nil
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+	"gorm.io/gorm"
+)
+
+type ShadowStore struct {
+	db *gorm.DB
+}
+
+func NewShadowStore(db *gorm.DB) *ShadowStore {
+	return &ShadowStore{db: db}
+}
+
+func (s *ShadowStore) CreateShadowDecisions(ctx context.Context, decisions []risk.ShadowDecision) error {
+	if len(decisions) == 0 {
+		return nil
+	}
+	if err := s.db.WithContext(ctx).Create(&decisions).Error; err != nil {
+		return fmt.Errorf("failed to create shadow decisions: %w", err)
+	}
+	return nil
+}
+
+func (s *ShadowStore) SummarizeShadowDecisions(ctx context.Context, challenger string, from, to time.Time) ([]risk.ShadowCell, error) {
+	query := s.db.WithContext(ctx).
+		Model(&risk.ShadowDecision{}).
+		Select("challenger, champion_risk_level, challenger_risk_level, "+
+			"COUNT(*) AS count, "+
+			"SUM(challenger_score - champion_score) AS score_delta, "+
+			"SUM(transaction_volume_30d) AS volume, "+
+			"SUM(CASE WHEN champion_hold_period <> challenger_hold_period "+
+			"THEN transaction_volume_30d ELSE 0 END) AS hold_changed_volume, "+
+			"SUM(transaction_volume_30d * champion_reserve_percentage / 100) AS champion_reserve, "+
+			"SUM(transaction_volume_30d * challenger_reserve_percentage / 100) AS challenger_reserve").
+		Where("evaluated_at >= ? AND evaluated_at < ?", from, to)
+
+	if challenger != "" {
+		query = query.Where("challenger = ?", challenger)
+	}
+
+	var cells []risk.ShadowCell
+	if err := query.
+		Group("challenger, champion_risk_level, challenger_risk_level").
+		Scan(&cells).Error; err != nil {
+		return nil, fmt.Errorf("failed to summarize shadow decisions: %w", err)
+	}
+	return cells, nil
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/risk"
+	"gorm.io/gorm"
+)
+
+type TierTableStore struct {
+	db *gorm.DB
+}
+
+func NewTierTableStore(db *gorm.DB) *TierTableStore {
+	return &TierTableStore{db: db}
+}
+
+func (s *TierTableStore) Create(ctx context.Context, table *risk.PolicyTierTable) error {
+	if err := s.db.WithContext(ctx).Create(table).Error; err != nil {
+		return fmt.Errorf("failed to create tier table: %w", err)
+	}
+	return nil
+}
+
+func (s *TierTableStore) Get(ctx context.Context, id uuid.UUID) (*risk.PolicyTierTable, error) {
+	var table risk.PolicyTierTable
+	if err := s.db.WithContext(ctx).First(&table, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("tier table not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get tier table: %w", err)
+	}
+	return &table, nil
+}
+
+func (s *TierTableStore) List(ctx context.Context) ([]risk.PolicyTierTable, error) {
+	var tables []risk.PolicyTierTable
+	if err := s.db.WithContext(ctx).
+		Order("created_at DESC").
+		Find(&tables).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tier tables: %w", err)
+	}
+	return tables, nil
+}
+
+func (s *TierTableStore) Activate(ctx context.Context, id uuid.UUID, from time.Time, until *time.Time) error {
+	result := s.db.WithContext(ctx).
+		Model(&risk.PolicyTierTable{}).
+		Where("id = ? AND status = ?", id, risk.TierTableStatusDraft).
+		Updates(map[string]interface{}{
+			"status":          risk.TierTableStatusActive,
+			"effective_from":  from,
+			"effective_until": until,
+			"activated_at":    time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to activate tier table: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("tier table %s is not a draft", id)
+	}
+	return nil
+}
+
+// GetActiveAt returns the active table whose effective window contains at.
+// When windows overlap the most recently effective table wins. It returns
+// nil when no table applies.
+func (s *TierTableStore) GetActiveAt(ctx context.Context, at time.Time) (*risk.PolicyTierTable, error) {
+	var table risk.PolicyTierTable
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND effective_from <= ?", risk.TierTableStatusActive, at).
+		Where("effective_until IS NULL OR effective_until > ?", at).
+		Order("effective_from DESC, activated_at DESC").
+		First(&table).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get active tier table: %w", err)
+	}
+	return &table, nil
+}
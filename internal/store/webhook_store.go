@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/webhook"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookStore struct {
+	db *gorm.DB
+}
+
+func NewWebhookStore(db *gorm.DB) *WebhookStore {
+	return &WebhookStore{db: db}
+}
+
+func (s *WebhookStore) CreateSubscription(ctx context.Context, sub *webhook.Subscription) error {
+	if err := s.db.WithContext(ctx).Create(sub).Error; err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *WebhookStore) GetSubscription(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	var sub webhook.Subscription
+	if err := s.db.WithContext(ctx).First(&sub, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("%w: %s", webhook.ErrSubscriptionNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+func (s *WebhookStore) ListSubscriptions(ctx context.Context, activeOnly bool) ([]webhook.Subscription, error) {
+	var subs []webhook.Subscription
+	query := s.db.WithContext(ctx).Order("created_at ASC")
+	if activeOnly {
+		query = query.Where("active = true")
+	}
+	if err := query.Find(&subs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+func (s *WebhookStore) UpdateSubscription(ctx context.Context, sub *webhook.Subscription) error {
+	result := s.db.WithContext(ctx).
+		Model(&webhook.Subscription{}).
+		Where("id = ?", sub.ID).
+		Updates(map[string]interface{}{
+			"url":         sub.URL,
+			"event_types": sub.EventTypes,
+			"secret":      sub.Secret,
+			"description": sub.Description,
+			"active":      sub.Active,
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", webhook.ErrSubscriptionNotFound, sub.ID)
+	}
+	return nil
+}
+
+// DeleteSubscription removes the subscription; its deliveries cascade.
+func (s *WebhookStore) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	result := s.db.WithContext(ctx).Delete(&webhook.Subscription{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", webhook.ErrSubscriptionNotFound, id)
+	}
+	return nil
+}
+
+func (s *WebhookStore) CreateDeliveries(ctx context.Context, deliveries []webhook.Delivery) error {
+	if err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("failed to create webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueDeliveries leases due deliveries with SKIP LOCKED, so concurrent
+// dispatchers claim disjoint batches.
+func (s *WebhookStore) ClaimDueDeliveries(ctx context.Context, limit int, now, leaseUntil time.Time) ([]webhook.Delivery, error) {
+	var deliveries []webhook.Delivery
+	if err := s.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, webhook.DeliveryPending, now, limit).
+		Scan(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	// RETURNING does not preserve the subquery's order.
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+	return deliveries, nil
+}
+
+func (s *WebhookStore) GetDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	var d webhook.Delivery
+	if err := s.db.WithContext(ctx).First(&d, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("%w: %s", webhook.ErrDeliveryNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return &d, nil
+}
+
+func (s *WebhookStore) UpdateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	if err := s.db.WithContext(ctx).
+		Model(&webhook.Delivery{}).
+		Where("id = ?", d.ID).
+		Updates(map[string]interface{}{
+			"status":           d.Status,
+			"attempts":         d.Attempts,
+			"last_status_code": d.LastStatusCode,
+			"last_error":       d.LastError,
+			"next_attempt_at":  d.NextAttemptAt,
+			"delivered_at":     d.DeliveredAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (s *WebhookStore) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]webhook.Delivery, int64, error) {
+	var deliveries []webhook.Delivery
+	var total int64
+
+	query := s.db.WithContext(ctx).Model(&webhook.Delivery{})
+	if filter.SubscriptionID != nil {
+		query = query.Where("subscription_id = ?", *filter.SubscriptionID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&deliveries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return deliveries, total, nil
+}
@@ -0,0 +1,215 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const maxBackoff = 6 * time.Hour
+
+type DispatcherConfig struct {
+	// Interval is how often the dispatcher polls for due deliveries.
+	Interval  time.Duration
+	BatchSize int
+	// MaxAttempts is how many times a delivery is tried before it is
+	// dead-lettered.
+	MaxAttempts int
+	Timeout     time.Duration
+	// Lease is how long claimed deliveries stay hidden from other
+	// dispatchers. It must exceed BatchSize * Timeout.
+	Lease time.Duration
+}
+
+// Dispatcher sends pending deliveries to their subscribers. A delivery
+// succeeds on any 2xx response; otherwise it is retried with exponential
+// backoff until MaxAttempts, then dead-lettered.
+type Dispatcher struct {
+	store  Repository
+	client *http.Client
+	config DispatcherConfig
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewDispatcher(store Repository, config DispatcherConfig) *Dispatcher {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 50
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 8
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.Lease <= 0 {
+		config.Lease = time.Duration(config.BatchSize)*config.Timeout + time.Minute
+	}
+
+	return &Dispatcher{
+		store:  store,
+		client: &http.Client{Timeout: config.Timeout},
+		config: config,
+	}
+}
+
+// Start polls for due deliveries every Interval until Stop is called.
+func (d *Dispatcher) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+
+	go d.loop(ctx, d.done)
+	log.Printf("[INFO] Webhook dispatcher started (interval=%s, max_attempts=%d)", d.config.Interval, d.config.MaxAttempts)
+}
+
+// Stop ends polling and waits for the batch in flight to finish.
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	cancel, done := d.cancel, d.done
+	d.cancel, d.done = nil, nil
+	d.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	log.Println("[INFO] Webhook dispatcher stopped")
+}
+
+func (d *Dispatcher) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverOnce(ctx); err != nil {
+			log.Printf("[ERROR] Webhook dispatch failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverOnce claims one batch of due deliveries and sends them. It returns
+// how many deliveries it claimed.
+func (d *Dispatcher) DeliverOnce(ctx context.Context) (int, error) {
+	now := time.Now()
+	deliveries, err := d.store.ClaimDueDeliveries(ctx, d.config.BatchSize, now, now.Add(d.config.Lease))
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	// Finish claimed deliveries even if ctx is cancelled meanwhile.
+	sendCtx := context.WithoutCancel(ctx)
+	subs := make(map[uuid.UUID]*Subscription)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok {
+			sub, err = d.store.GetSubscription(sendCtx, delivery.SubscriptionID)
+			if err != nil && !errors.Is(err, ErrSubscriptionNotFound) {
+				log.Printf("[ERROR] Failed to load webhook subscription %s: %v", delivery.SubscriptionID, err)
+				continue
+			}
+			subs[delivery.SubscriptionID] = sub
+		}
+
+		d.attempt(sendCtx, delivery, sub)
+		if err := d.store.UpdateDelivery(sendCtx, delivery); err != nil {
+			log.Printf("[ERROR] Failed to record webhook delivery %s: %v", delivery.ID, err)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// attempt sends the delivery once and updates its status in place.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *Delivery, sub *Subscription) {
+	if sub == nil || !sub.Active {
+		delivery.Status = DeliveryDeadLetter
+		delivery.LastError = "subscription is inactive or deleted"
+		return
+	}
+
+	delivery.Attempts++
+	status, err := d.send(ctx, delivery, sub)
+	delivery.LastStatusCode = status
+	if err == nil {
+		delivered := time.Now()
+		delivery.Status = DeliveryDelivered
+		delivery.DeliveredAt = &delivered
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.config.MaxAttempts {
+		delivery.Status = DeliveryDeadLetter
+		log.Printf("[WARN] Webhook delivery %s of event %s to %s dead-lettered after %d attempts: %v",
+			delivery.ID, delivery.EventID, sub.URL, delivery.Attempts, err)
+		return
+	}
+
+	delivery.NextAttemptAt = time.Now().Add(backoff(delivery.Attempts))
+	log.Printf("[WARN] Webhook delivery %s to %s failed (attempt %d), retrying at %s: %v",
+		delivery.ID, sub.URL, delivery.Attempts, delivery.NextAttemptAt.Format(time.RFC3339), err)
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *Delivery, sub *Subscription) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, time.Now(), delivery.Payload))
+	req.Header.Set(EventIDHeader, delivery.EventID.String())
+	req.Header.Set(EventTypeHeader, delivery.EventType)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("subscriber responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff doubles from 30 seconds with each attempt, up to six hours.
+func backoff(attempt int) time.Duration {
+	delay := 30 * time.Second
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending    DeliveryStatus = "PENDING"
+	DeliveryDelivered  DeliveryStatus = "DELIVERED"
+	DeliveryDeadLetter DeliveryStatus = "DEAD_LETTER"
+)
+
+// EventTypes is the set of event types a subscription receives, stored as a
+// JSONB array.
+type EventTypes []string
+
+func (e *EventTypes) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+	return json.Unmarshal(bytes, e)
+}
+
+func (e EventTypes) Value() (driver.Value, error) {
+	return json.Marshal(e)
+}
+
+func (e EventTypes) Contains(eventType string) bool {
+	for _, t := range e {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription sends the listed event types to URL, signed with Secret. The
+// secret is only returned when the subscription is created.
+type Subscription struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	URL         string     `json:"url" gorm:"not null"`
+	EventTypes  EventTypes `json:"event_types" gorm:"type:jsonb;not null"`
+	Secret      string     `json:"-" gorm:"not null"`
+	Description string     `json:"description,omitempty" gorm:"not null;default:''"`
+	Active      bool       `json:"active" gorm:"not null;default:true"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"not null;default:now()"`
+}
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Delivery is one event sent to one subscription. It stays PENDING while
+// attempts remain and becomes DEAD_LETTER after the last one fails.
+type Delivery struct {
+	ID             uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SubscriptionID uuid.UUID       `json:"subscription_id" gorm:"type:uuid;not null"`
+	EventID        uuid.UUID       `json:"event_id" gorm:"type:uuid;not null"`
+	EventType      string          `json:"event_type" gorm:"not null"`
+	Payload        json.RawMessage `json:"payload" gorm:"type:jsonb;not null"`
+	Status         DeliveryStatus  `json:"status" gorm:"not null;default:'PENDING'"`
+	Attempts       int             `json:"attempts" gorm:"not null;default:0"`
+	LastStatusCode int             `json:"last_status_code,omitempty" gorm:"not null;default:0"`
+	LastError      string          `json:"last_error,omitempty" gorm:"not null;default:''"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" gorm:"not null;default:now()"`
+	CreatedAt      time.Time       `json:"created_at" gorm:"not null;default:now()"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+type DeliveryFilter struct {
+	SubscriptionID *uuid.UUID
+	Status         DeliveryStatus
+	Limit          int
+	Offset         int
+}
+
+// SubscriptionInput creates a subscription, or updates one when only some
+// fields are set.
+type SubscriptionInput struct {
+	URL         *string    `json:"url"`
+	EventTypes  EventTypes `json:"event_types"`
+	Description *string    `json:"description"`
+	Active      *bool      `json:"active"`
+	// Secret is generated when left empty on create.
+	Secret *string `json:"secret"`
+}
@@ -0,0 +1,209 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/outbox"
+)
+
+var (
+	ErrInvalidSubscription   = errors.New("invalid webhook subscription")
+	ErrSubscriptionNotFound  = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound      = errors.New("webhook delivery not found")
+	ErrDeliveryNotDeadLetter = errors.New("only dead-lettered deliveries can be retried")
+)
+
+type Repository interface {
+	CreateSubscription(ctx context.Context, sub *Subscription) error
+	GetSubscription(ctx context.Context, id uuid.UUID) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, activeOnly bool) ([]Subscription, error)
+	UpdateSubscription(ctx context.Context, sub *Subscription) error
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+
+	// CreateDeliveries skips deliveries that already exist for the same
+	// subscription and event, so redelivered events are not sent twice.
+	CreateDeliveries(ctx context.Context, deliveries []Delivery) error
+	// ClaimDueDeliveries returns up to limit pending deliveries due at now and
+	// hides them from other dispatchers until leaseUntil.
+	ClaimDueDeliveries(ctx context.Context, limit int, now, leaseUntil time.Time) ([]Delivery, error)
+	GetDelivery(ctx context.Context, id uuid.UUID) (*Delivery, error)
+	UpdateDelivery(ctx context.Context, d *Delivery) error
+	ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]Delivery, int64, error)
+}
+
+// Service manages subscriptions and turns outbox events into deliveries. It
+// implements outbox.Publisher.
+type Service struct {
+	store Repository
+}
+
+func NewService(store Repository) *Service {
+	return &Service{store: store}
+}
+
+// Publish records a pending delivery of event for each active subscription
+// that lists its type. The dispatcher sends them.
+func (s *Service) Publish(ctx context.Context, event outbox.Event) error {
+	subs, err := s.store.ListSubscriptions(ctx, true)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	var body json.RawMessage
+	var deliveries []Delivery
+	for _, sub := range subs {
+		if !sub.EventTypes.Contains(event.Type) {
+			continue
+		}
+		if body == nil {
+			if body, err = json.Marshal(event); err != nil {
+				return fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+			}
+		}
+		deliveries = append(deliveries, Delivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        body,
+			Status:         DeliveryPending,
+			NextAttemptAt:  time.Now(),
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := s.store.CreateDeliveries(ctx, deliveries); err != nil {
+		return fmt.Errorf("failed to record webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) CreateSubscription(ctx context.Context, input SubscriptionInput) (*Subscription, error) {
+	sub := &Subscription{Active: true}
+	if input.URL == nil {
+		return nil, fmt.Errorf("%w: url is required", ErrInvalidSubscription)
+	}
+	if err := apply(sub, input); err != nil {
+		return nil, err
+	}
+	if sub.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return nil, err
+		}
+		sub.Secret = secret
+	}
+
+	if err := s.store.CreateSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	log.Printf("[INFO] Webhook subscription %s created for %s (%v)", sub.ID, sub.URL, sub.EventTypes)
+	return sub, nil
+}
+
+// UpdateSubscription changes the fields set in input. Setting secret rotates
+// it immediately; deliveries still pending are signed with the new one.
+func (s *Service) UpdateSubscription(ctx context.Context, id uuid.UUID, input SubscriptionInput) (*Subscription, error) {
+	sub, err := s.store.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := apply(sub, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.UpdateSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+	return sub, nil
+}
+
+func apply(sub *Subscription, input SubscriptionInput) error {
+	if input.URL != nil {
+		u, err := url.Parse(*input.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidSubscription)
+		}
+		sub.URL = *input.URL
+	}
+	if input.EventTypes != nil {
+		if err := validateEventTypes(input.EventTypes); err != nil {
+			return err
+		}
+		sub.EventTypes = input.EventTypes
+	}
+	if sub.EventTypes == nil {
+		return fmt.Errorf("%w: event_types is required", ErrInvalidSubscription)
+	}
+	if input.Description != nil {
+		sub.Description = *input.Description
+	}
+	if input.Active != nil {
+		sub.Active = *input.Active
+	}
+	if input.Secret != nil {
+		if len(*input.Secret) < 16 {
+			return fmt.Errorf("%w: secret must be at least 16 characters", ErrInvalidSubscription)
+		}
+		sub.Secret = *input.Secret
+	}
+	return nil
+}
+
+func validateEventTypes(types EventTypes) error {
+	if len(types) == 0 {
+		return fmt.Errorf("%w: event_types must not be empty", ErrInvalidSubscription)
+	}
+	known := EventTypes(outbox.EventTypes)
+	for _, t := range types {
+		if !known.Contains(t) {
+			return fmt.Errorf("%w: unknown event type %q (expected one of %v)", ErrInvalidSubscription, t, outbox.EventTypes)
+		}
+	}
+	return nil
+}
+
+func (s *Service) GetSubscription(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	return s.store.GetSubscription(ctx, id)
+}
+
+func (s *Service) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	return s.store.ListSubscriptions(ctx, false)
+}
+
+// DeleteSubscription removes the subscription together with its delivery log.
+func (s *Service) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return s.store.DeleteSubscription(ctx, id)
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]Delivery, int64, error) {
+	return s.store.ListDeliveries(ctx, filter)
+}
+
+// RetryDelivery puts a dead-lettered delivery back in the queue with a fresh
+// set of attempts.
+func (s *Service) RetryDelivery(ctx context.Context, id uuid.UUID) (*Delivery, error) {
+	d, err := s.store.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status != DeliveryDeadLetter {
+		return nil, fmt.Errorf("%w: delivery %s is %s", ErrDeliveryNotDeadLetter, id, d.Status)
+	}
+
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = time.Now()
+	if err := s.store.UpdateDelivery(ctx, d); err != nil {
+		return nil, fmt.Errorf("failed to requeue webhook delivery: %w", err)
+	}
+	return d, nil
+}
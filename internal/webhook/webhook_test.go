@@ -0,0 +1,318 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuno-payments/papaya-payout-engine/internal/outbox"
+)
+
+type mockRepository struct {
+	subscriptions map[uuid.UUID]*Subscription
+	deliveries    map[uuid.UUID]*Delivery
+}
+
+func newMockRepository() *mockRepository {
+	return &mockRepository{
+		subscriptions: make(map[uuid.UUID]*Subscription),
+		deliveries:    make(map[uuid.UUID]*Delivery),
+	}
+}
+
+func (m *mockRepository) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	sub.ID = uuid.New()
+	stored := *sub
+	m.subscriptions[sub.ID] = &stored
+	return nil
+}
+
+func (m *mockRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	sub, ok := m.subscriptions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSubscriptionNotFound, id)
+	}
+	found := *sub
+	return &found, nil
+}
+
+func (m *mockRepository) ListSubscriptions(ctx context.Context, activeOnly bool) ([]Subscription, error) {
+	var subs []Subscription
+	for _, sub := range m.subscriptions {
+		if sub.Active || !activeOnly {
+			subs = append(subs, *sub)
+		}
+	}
+	return subs, nil
+}
+
+func (m *mockRepository) UpdateSubscription(ctx context.Context, sub *Subscription) error {
+	stored := *sub
+	m.subscriptions[sub.ID] = &stored
+	return nil
+}
+
+func (m *mockRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	delete(m.subscriptions, id)
+	return nil
+}
+
+func (m *mockRepository) CreateDeliveries(ctx context.Context, deliveries []Delivery) error {
+	for _, d := range deliveries {
+		duplicate := false
+		for _, existing := range m.deliveries {
+			if existing.SubscriptionID == d.SubscriptionID && existing.EventID == d.EventID {
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			d.ID = uuid.New()
+			m.deliveries[d.ID] = &d
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) ClaimDueDeliveries(ctx context.Context, limit int, now, leaseUntil time.Time) ([]Delivery, error) {
+	var claimed []Delivery
+	for _, d := range m.deliveries {
+		if d.Status == DeliveryPending && !d.NextAttemptAt.After(now) && len(claimed) < limit {
+			d.NextAttemptAt = leaseUntil
+			claimed = append(claimed, *d)
+		}
+	}
+	return claimed, nil
+}
+
+func (m *mockRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*Delivery, error) {
+	d, ok := m.deliveries[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDeliveryNotFound, id)
+	}
+	found := *d
+	return &found, nil
+}
+
+func (m *mockRepository) UpdateDelivery(ctx context.Context, d *Delivery) error {
+	stored := *d
+	m.deliveries[d.ID] = &stored
+	return nil
+}
+
+func (m *mockRepository) ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]Delivery, int64, error) {
+	var deliveries []Delivery
+	for _, d := range m.deliveries {
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, int64(len(deliveries)), nil
+}
+
+// onlyDelivery returns the single delivery recorded in repo.
+func (m *mockRepository) onlyDelivery(t *testing.T) *Delivery {
+	t.Helper()
+	if len(m.deliveries) != 1 {
+		t.Fatalf("expected one delivery, got %d", len(m.deliveries))
+	}
+	for _, d := range m.deliveries {
+		return d
+	}
+	return nil
+}
+
+// dueNow makes every pending delivery due so the test need not wait out the
+// backoff.
+func (m *mockRepository) dueNow() {
+	for _, d := range m.deliveries {
+		d.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+}
+
+// receiver is a local subscriber that verifies signatures and answers with
+// the next queued status, then 200.
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	statuses []int
+	received []outbox.Event
+	errs     []error
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	if err := Verify(r.secret, req.Header.Get(SignatureHeader), body, time.Minute, time.Now()); err != nil {
+		r.errs = append(r.errs, err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var event outbox.Event
+	_ = json.Unmarshal(body, &event)
+	if req.Header.Get(EventIDHeader) != event.ID.String() {
+		r.errs = append(r.errs, errors.New("event ID header does not match body"))
+	}
+	r.received = append(r.received, event)
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func subscribe(t *testing.T, service *Service, url string, types ...string) *Subscription {
+	t.Helper()
+	sub, err := service.CreateSubscription(context.Background(), SubscriptionInput{URL: &url, EventTypes: types})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return sub
+}
+
+func tierChanged() outbox.Event {
+	return outbox.Event{ID: uuid.New(), Type: outbox.EventTierChanged, MerchantID: uuid.New(),
+		Payload: json.RawMessage(`{"risk_level":"HIGH"}`), OccurredAt: time.Now()}
+}
+
+func TestDeliverySignedAndRetried(t *testing.T) {
+	repo := newMockRepository()
+	service := NewService(repo)
+	recv := &receiver{statuses: []int{http.StatusInternalServerError}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	sub := subscribe(t, service, server.URL, outbox.EventTierChanged)
+	recv.secret = sub.Secret
+	subscribe(t, service, server.URL, outbox.EventHighRiskDetected)
+
+	event := tierChanged()
+	if err := service.Publish(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The relay may hand over the same event twice.
+	if err := service.Publish(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.onlyDelivery(t)
+
+	dispatcher := NewDispatcher(repo, DispatcherConfig{MaxAttempts: 3, Timeout: time.Second})
+	if _, err := dispatcher.DeliverOnce(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	delivery := repo.onlyDelivery(t)
+	if delivery.Status != DeliveryPending || delivery.Attempts != 1 || delivery.LastStatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected pending retry after a 500, got %+v", delivery)
+	}
+	if wait := time.Until(delivery.NextAttemptAt); wait < 25*time.Second || wait > 30*time.Second {
+		t.Errorf("expected first retry in ~30s, got %s", wait)
+	}
+
+	repo.dueNow()
+	if _, err := dispatcher.DeliverOnce(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	delivery = repo.onlyDelivery(t)
+	if delivery.Status != DeliveryDelivered || delivery.Attempts != 2 || delivery.DeliveredAt == nil {
+		t.Errorf("expected delivered on the second attempt, got %+v", delivery)
+	}
+	if len(recv.errs) > 0 {
+		t.Errorf("receiver rejected deliveries: %v", recv.errs)
+	}
+	if len(recv.received) != 2 || recv.received[1].ID != event.ID {
+		t.Errorf("expected the event received twice, got %d", len(recv.received))
+	}
+}
+
+func TestDeliveryDeadLetter(t *testing.T) {
+	repo := newMockRepository()
+	service := NewService(repo)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	subscribe(t, service, server.URL, outbox.EventTierChanged)
+	if err := service.Publish(context.Background(), tierChanged()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dispatcher := NewDispatcher(repo, DispatcherConfig{MaxAttempts: 2, Timeout: time.Second})
+	for i := 0; i < 3; i++ {
+		repo.dueNow()
+		if _, err := dispatcher.DeliverOnce(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	delivery := repo.onlyDelivery(t)
+	if delivery.Status != DeliveryDeadLetter || delivery.Attempts != 2 {
+		t.Fatalf("expected dead letter after 2 attempts, got %+v", delivery)
+	}
+
+	if _, err := service.RetryDelivery(context.Background(), delivery.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	delivery = repo.onlyDelivery(t)
+	if delivery.Status != DeliveryPending || delivery.Attempts != 0 {
+		t.Errorf("expected requeued delivery, got %+v", delivery)
+	}
+	if _, err := service.RetryDelivery(context.Background(), delivery.ID); !errors.Is(err, ErrDeliveryNotDeadLetter) {
+		t.Errorf("expected ErrDeliveryNotDeadLetter, got %v", err)
+	}
+}
+
+func TestSubscriptionValidation(t *testing.T) {
+	service := NewService(newMockRepository())
+	valid, relative := "https://partner.example.com/hooks", "/hooks"
+	short := "too-short"
+
+	cases := map[string]SubscriptionInput{
+		"missing url":        {EventTypes: EventTypes{outbox.EventTierChanged}},
+		"relative url":       {URL: &relative, EventTypes: EventTypes{outbox.EventTierChanged}},
+		"missing event type": {URL: &valid},
+		"unknown event type": {URL: &valid, EventTypes: EventTypes{"risk.unknown"}},
+		"short secret":       {URL: &valid, EventTypes: EventTypes{outbox.EventTierChanged}, Secret: &short},
+	}
+	for name, input := range cases {
+		if _, err := service.CreateSubscription(context.Background(), input); !errors.Is(err, ErrInvalidSubscription) {
+			t.Errorf("%s: expected ErrInvalidSubscription, got %v", name, err)
+		}
+	}
+
+	sub, err := service.CreateSubscription(context.Background(), SubscriptionInput{URL: &valid, EventTypes: EventTypes{outbox.EventTierChanged}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sub.Secret) < 16 || !sub.Active {
+		t.Errorf("expected active subscription with a generated secret, got %+v", sub)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event_id":"1"}`)
+	now := time.Now()
+	header := Sign("secret-0123456789", now, body)
+
+	if err := Verify("secret-0123456789", header, body, time.Minute, now); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+	if err := Verify("other-secret-0123", header, body, time.Minute, now); err == nil {
+		t.Error("expected mismatch for the wrong secret")
+	}
+	if err := Verify("secret-0123456789", header, []byte(`{"event_id":"2"}`), time.Minute, now); err == nil {
+		t.Error("expected mismatch for a tampered body")
+	}
+	if err := Verify("secret-0123456789", header, body, time.Minute, now.Add(10*time.Minute)); err == nil {
+		t.Error("expected replayed signature to be rejected")
+	}
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_decisions_model_version;
+ALTER TABLE risk_decisions DROP COLUMN IF EXISTS model_version;
@@ -0,0 +1,3 @@
+ALTER TABLE risk_decisions ADD COLUMN model_version VARCHAR(64) NOT NULL DEFAULT 'legacy';
+
+CREATE INDEX idx_decisions_model_version ON risk_decisions(model_version, evaluated_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE risk_decisions DROP COLUMN IF EXISTS policy_version;
+DROP INDEX IF EXISTS idx_tier_tables_active;
+DROP TABLE IF EXISTS policy_tier_tables;
@@ -0,0 +1,18 @@
+CREATE TABLE policy_tier_tables (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    version VARCHAR(64) NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'DRAFT',
+    tiers JSONB NOT NULL,
+
+    effective_from TIMESTAMPTZ NULL,
+    effective_until TIMESTAMPTZ NULL,
+    activated_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT tier_table_status_valid CHECK (status IN ('DRAFT', 'ACTIVE')),
+    CONSTRAINT tier_table_effective_window_valid CHECK (effective_until IS NULL OR effective_until > effective_from)
+);
+
+CREATE INDEX idx_tier_tables_active ON policy_tier_tables(status, effective_from DESC);
+
+ALTER TABLE risk_decisions ADD COLUMN policy_version VARCHAR(64) NOT NULL DEFAULT 'legacy';
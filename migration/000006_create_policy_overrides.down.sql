@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_policy_overrides_active;
+DROP TABLE IF EXISTS policy_overrides;
@@ -0,0 +1,23 @@
+CREATE TABLE policy_overrides (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+
+    country VARCHAR(2) NOT NULL DEFAULT '',
+    industry VARCHAR(50) NOT NULL DEFAULT '',
+
+    mode VARCHAR(20) NOT NULL,
+    tiers JSONB NULL,
+    hold_adjustment_steps INTEGER NOT NULL DEFAULT 0,
+    reserve_adjustment INTEGER NOT NULL DEFAULT 0,
+
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT policy_override_mode_valid CHECK (mode IN ('REPLACE', 'ADJUST')),
+    CONSTRAINT policy_override_scope_valid CHECK (country <> '' OR industry <> ''),
+    CONSTRAINT policy_override_tiers_valid CHECK (mode <> 'REPLACE' OR tiers IS NOT NULL)
+);
+
+CREATE INDEX idx_policy_overrides_active ON policy_overrides(active, country, industry);
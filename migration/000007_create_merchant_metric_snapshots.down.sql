@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_merchant_metric_snapshots_merchant;
+DROP TABLE IF EXISTS merchant_metric_snapshots;
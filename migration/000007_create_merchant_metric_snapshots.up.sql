@@ -0,0 +1,32 @@
+CREATE TABLE merchant_metric_snapshots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    merchant_id UUID NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+
+    transaction_volume_30d DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    transaction_count_30d INTEGER NOT NULL DEFAULT 0,
+    avg_ticket_size DECIMAL(10, 2) NOT NULL DEFAULT 0,
+
+    chargeback_count_30d INTEGER NOT NULL DEFAULT 0,
+    chargeback_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    refund_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    velocity_multiplier DECIMAL(5, 2) NOT NULL DEFAULT 1.0,
+
+    kyc_verified BOOLEAN NOT NULL DEFAULT false,
+    kyc_level VARCHAR(20) NOT NULL DEFAULT 'NONE',
+
+    captured_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_merchant_metric_snapshots_merchant ON merchant_metric_snapshots(merchant_id, captured_at DESC);
+
+-- Seed history with the current aggregates so trends have a starting point.
+INSERT INTO merchant_metric_snapshots (
+    merchant_id, transaction_volume_30d, transaction_count_30d, avg_ticket_size,
+    chargeback_count_30d, chargeback_rate, refund_rate, velocity_multiplier,
+    kyc_verified, kyc_level, captured_at
+)
+SELECT
+    id, transaction_volume_30d, transaction_count_30d, avg_ticket_size,
+    chargeback_count_30d, chargeback_rate, refund_rate, velocity_multiplier,
+    kyc_verified, kyc_level, updated_at
+FROM merchants;
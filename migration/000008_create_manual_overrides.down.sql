@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_manual_overrides_merchant;
+DROP TABLE IF EXISTS manual_overrides;
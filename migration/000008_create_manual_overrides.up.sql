@@ -0,0 +1,23 @@
+CREATE TABLE manual_overrides (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    merchant_id UUID NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+
+    hold_period VARCHAR(20) NOT NULL,
+    reserve_percentage INTEGER NOT NULL,
+
+    justification TEXT NOT NULL,
+    created_by VARCHAR(100) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    revoked_at TIMESTAMPTZ NULL,
+    revoked_by VARCHAR(100) NOT NULL DEFAULT '',
+    revoke_reason TEXT NOT NULL DEFAULT '',
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT manual_override_hold_valid CHECK (hold_period IN ('IMMEDIATE', '7_DAYS', '14_DAYS', '45_DAYS')),
+    CONSTRAINT manual_override_reserve_valid CHECK (reserve_percentage >= 0 AND reserve_percentage <= 100),
+    CONSTRAINT manual_override_justification_valid CHECK (justification <> '')
+);
+
+CREATE INDEX idx_manual_overrides_merchant ON manual_overrides(merchant_id, expires_at) WHERE revoked_at IS NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_review_case_comments_case;
+DROP TABLE IF EXISTS review_case_comments;
+DROP INDEX IF EXISTS idx_review_cases_merchant;
+DROP INDEX IF EXISTS idx_review_cases_queue;
+DROP TABLE IF EXISTS review_cases;
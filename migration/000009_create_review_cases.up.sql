@@ -0,0 +1,35 @@
+CREATE TABLE review_cases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    merchant_id UUID NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+    decision_id UUID NOT NULL REFERENCES risk_decisions(id) ON DELETE CASCADE,
+
+    risk_level VARCHAR(20) NOT NULL,
+    risk_score INTEGER NOT NULL,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    assignee VARCHAR(100) NOT NULL DEFAULT '',
+
+    sla_due_at TIMESTAMPTZ NOT NULL,
+    resolved_at TIMESTAMPTZ NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT review_case_status_valid CHECK (status IN ('open', 'in_review', 'approved', 'rejected', 'escalated')),
+    CONSTRAINT review_case_risk_level_valid CHECK (risk_level IN ('HIGH', 'CRITICAL'))
+);
+
+CREATE INDEX idx_review_cases_queue ON review_cases(status, risk_level, created_at);
+CREATE INDEX idx_review_cases_merchant ON review_cases(merchant_id, status);
+
+CREATE TABLE review_case_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    case_id UUID NOT NULL REFERENCES review_cases(id) ON DELETE CASCADE,
+    author VARCHAR(100) NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT review_case_comment_body_valid CHECK (body <> '')
+);
+
+CREATE INDEX idx_review_case_comments_case ON review_case_comments(case_id, created_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_decisions_merchant_status;
+ALTER TABLE risk_decisions DROP CONSTRAINT IF EXISTS risk_decision_status_valid;
+ALTER TABLE risk_decisions DROP COLUMN IF EXISTS review_note;
+ALTER TABLE risk_decisions DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE risk_decisions DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE risk_decisions DROP COLUMN IF EXISTS status;
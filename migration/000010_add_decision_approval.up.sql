@@ -0,0 +1,9 @@
+ALTER TABLE risk_decisions ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'EFFECTIVE';
+ALTER TABLE risk_decisions ADD COLUMN reviewed_by VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE risk_decisions ADD COLUMN reviewed_at TIMESTAMPTZ NULL;
+ALTER TABLE risk_decisions ADD COLUMN review_note TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE risk_decisions ADD CONSTRAINT risk_decision_status_valid
+    CHECK (status IN ('EFFECTIVE', 'PENDING_APPROVAL', 'REJECTED'));
+
+CREATE INDEX idx_decisions_merchant_status ON risk_decisions(merchant_id, status, evaluated_at DESC);
@@ -0,0 +1,17 @@
+CREATE TABLE scheduler_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    instance_id VARCHAR(100) NOT NULL,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'RUNNING',
+    merchants_due INTEGER NOT NULL DEFAULT 0,
+    merchants_evaluated INTEGER NOT NULL DEFAULT 0,
+    merchants_failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ NULL,
+
+    CONSTRAINT scheduler_run_status_valid CHECK (status IN ('RUNNING', 'COMPLETED', 'FAILED'))
+);
+
+CREATE INDEX idx_scheduler_runs_started_at ON scheduler_runs(started_at DESC);
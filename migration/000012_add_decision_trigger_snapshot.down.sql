@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_decisions_trigger_snapshot;
+ALTER TABLE risk_decisions DROP COLUMN IF EXISTS trigger_snapshot_id;
@@ -0,0 +1,3 @@
+ALTER TABLE risk_decisions ADD COLUMN trigger_snapshot_id UUID NULL REFERENCES merchant_metric_snapshots(id);
+
+CREATE INDEX idx_decisions_trigger_snapshot ON risk_decisions(trigger_snapshot_id) WHERE trigger_snapshot_id IS NOT NULL;
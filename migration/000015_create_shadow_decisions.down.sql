@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_shadow_decisions_decision;
+DROP INDEX IF EXISTS idx_shadow_decisions_challenger;
+DROP TABLE IF EXISTS shadow_decisions;
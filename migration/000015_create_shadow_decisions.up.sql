@@ -0,0 +1,23 @@
+CREATE TABLE shadow_decisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    decision_id UUID NOT NULL REFERENCES risk_decisions(id),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    challenger VARCHAR(100) NOT NULL,
+    champion_version VARCHAR(100) NOT NULL,
+
+    champion_score INTEGER NOT NULL,
+    champion_risk_level VARCHAR(20) NOT NULL,
+    champion_hold_period VARCHAR(20) NOT NULL,
+    champion_reserve_percentage INTEGER NOT NULL,
+
+    challenger_score INTEGER NOT NULL,
+    challenger_risk_level VARCHAR(20) NOT NULL,
+    challenger_hold_period VARCHAR(20) NOT NULL,
+    challenger_reserve_percentage INTEGER NOT NULL,
+
+    transaction_volume_30d DECIMAL(15, 2) NOT NULL,
+    evaluated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_shadow_decisions_challenger ON shadow_decisions(challenger, evaluated_at);
+CREATE INDEX idx_shadow_decisions_decision ON shadow_decisions(decision_id);
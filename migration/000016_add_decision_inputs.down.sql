@@ -0,0 +1 @@
+ALTER TABLE risk_decisions DROP COLUMN IF EXISTS inputs;
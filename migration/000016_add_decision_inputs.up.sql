@@ -0,0 +1 @@
+ALTER TABLE risk_decisions ADD COLUMN inputs JSONB NULL;
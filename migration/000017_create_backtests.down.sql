@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_backtests_created_at;
+DROP TABLE IF EXISTS backtests;
@@ -0,0 +1,21 @@
+CREATE TABLE backtests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    status VARCHAR(20) NOT NULL DEFAULT 'RUNNING',
+    window_start TIMESTAMPTZ NOT NULL,
+    window_end TIMESTAMPTZ NOT NULL,
+    candidate JSONB NOT NULL,
+    candidate_version VARCHAR(100) NOT NULL,
+    requested_by VARCHAR(100) NOT NULL DEFAULT '',
+
+    summary JSONB NULL,
+    report_rows JSONB NULL,
+    error TEXT NOT NULL DEFAULT '',
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ NULL,
+
+    CONSTRAINT backtest_status_valid CHECK (status IN ('RUNNING', 'COMPLETED', 'FAILED')),
+    CONSTRAINT backtest_window_valid CHECK (window_start < window_end)
+);
+
+CREATE INDEX idx_backtests_created_at ON backtests(created_at DESC);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_review_cases_unresolved_merchant;
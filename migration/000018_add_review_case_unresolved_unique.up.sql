@@ -0,0 +1,5 @@
+-- At most one unresolved case per merchant, so concurrent evaluations cannot
+-- both open one. Fails if duplicates already exist; resolve them first.
+CREATE UNIQUE INDEX idx_review_cases_unresolved_merchant
+    ON review_cases(merchant_id)
+    WHERE status IN ('open', 'in_review', 'escalated');
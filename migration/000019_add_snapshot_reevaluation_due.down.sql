@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_merchant_metric_snapshots_reevaluation_due;
+ALTER TABLE merchant_metric_snapshots DROP COLUMN IF EXISTS reevaluation_due;
@@ -0,0 +1,10 @@
+-- Marks snapshots whose score-relevant change still needs a re-evaluation.
+-- The scheduler treats a merchant as due while such a snapshot is newer than
+-- its latest decision, so a change dropped by the in-memory queue or lost on
+-- restart is still picked up.
+ALTER TABLE merchant_metric_snapshots
+    ADD COLUMN reevaluation_due BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX idx_merchant_metric_snapshots_reevaluation_due
+    ON merchant_metric_snapshots(merchant_id, captured_at DESC)
+    WHERE reevaluation_due;
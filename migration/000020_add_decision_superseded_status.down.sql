@@ -0,0 +1,5 @@
+UPDATE risk_decisions SET status = 'REJECTED' WHERE status = 'SUPERSEDED';
+
+ALTER TABLE risk_decisions DROP CONSTRAINT risk_decision_status_valid;
+ALTER TABLE risk_decisions ADD CONSTRAINT risk_decision_status_valid
+    CHECK (status IN ('EFFECTIVE', 'PENDING_APPROVAL', 'REJECTED'));
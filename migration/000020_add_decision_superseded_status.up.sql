@@ -0,0 +1,3 @@
+ALTER TABLE risk_decisions DROP CONSTRAINT risk_decision_status_valid;
+ALTER TABLE risk_decisions ADD CONSTRAINT risk_decision_status_valid
+    CHECK (status IN ('EFFECTIVE', 'PENDING_APPROVAL', 'REJECTED', 'SUPERSEDED'));
@@ -0,0 +1,10 @@
+ALTER TABLE backtests ADD COLUMN report_rows JSONB NULL;
+
+UPDATE backtests b SET report_rows = (
+    SELECT jsonb_agg(to_jsonb(r) - 'backtest_id' - 'position' ORDER BY r.position)
+    FROM backtest_rows r
+    WHERE r.backtest_id = b.id
+)
+WHERE b.status = 'COMPLETED';
+
+DROP TABLE IF EXISTS backtest_rows;
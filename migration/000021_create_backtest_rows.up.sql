@@ -0,0 +1,33 @@
+CREATE TABLE backtest_rows (
+    backtest_id UUID NOT NULL REFERENCES backtests(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+
+    decision_id UUID NOT NULL,
+    merchant_id UUID NOT NULL,
+    evaluated_at TIMESTAMPTZ NOT NULL,
+    transaction_volume_30d DECIMAL(15,2) NOT NULL,
+
+    baseline_score INTEGER NOT NULL,
+    candidate_score INTEGER NOT NULL,
+    baseline_risk_level VARCHAR(20) NOT NULL,
+    candidate_risk_level VARCHAR(20) NOT NULL,
+    baseline_hold_period VARCHAR(20) NOT NULL,
+    candidate_hold_period VARCHAR(20) NOT NULL,
+    baseline_reserve_percentage INTEGER NOT NULL,
+    candidate_reserve_percentage INTEGER NOT NULL,
+
+    PRIMARY KEY (backtest_id, position)
+);
+
+INSERT INTO backtest_rows
+SELECT b.id, r.ordinality - 1,
+       (r.row->>'decision_id')::UUID, (r.row->>'merchant_id')::UUID,
+       (r.row->>'evaluated_at')::TIMESTAMPTZ, (r.row->>'transaction_volume_30d')::DECIMAL(15,2),
+       (r.row->>'baseline_score')::INTEGER, (r.row->>'candidate_score')::INTEGER,
+       r.row->>'baseline_risk_level', r.row->>'candidate_risk_level',
+       r.row->>'baseline_hold_period', r.row->>'candidate_hold_period',
+       (r.row->>'baseline_reserve_percentage')::INTEGER, (r.row->>'candidate_reserve_percentage')::INTEGER
+FROM backtests b, jsonb_array_elements(b.report_rows) WITH ORDINALITY AS r(row, ordinality)
+WHERE b.report_rows IS NOT NULL;
+
+ALTER TABLE backtests DROP COLUMN report_rows;
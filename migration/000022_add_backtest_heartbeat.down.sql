@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_backtests_running_heartbeat;
+ALTER TABLE backtests DROP COLUMN IF EXISTS heartbeat_at;
@@ -0,0 +1,3 @@
+ALTER TABLE backtests ADD COLUMN heartbeat_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX idx_backtests_running_heartbeat ON backtests(heartbeat_at) WHERE status = 'RUNNING';
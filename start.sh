@@ -28,6 +28,25 @@ CONTAINER_ID=$(docker ps | grep postgres | awk '{print $1}')
 docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000001_create_merchants.up.sql 2>/dev/null || echo "Merchants table already exists"
 docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000002_create_decisions.up.sql 2>/dev/null || echo "Decisions table already exists"
 docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000003_create_batches.up.sql 2>/dev/null || echo "Batches table already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000004_add_decision_model_version.up.sql 2>/dev/null || echo "Decision model_version column already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000005_create_policy_tier_tables.up.sql 2>/dev/null || echo "Policy tier tables already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000006_create_policy_overrides.up.sql 2>/dev/null || echo "Policy overrides table already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000007_create_merchant_metric_snapshots.up.sql 2>/dev/null || echo "Merchant metric snapshots table already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000008_create_manual_overrides.up.sql 2>/dev/null || echo "Manual overrides table already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000009_create_review_cases.up.sql 2>/dev/null || echo "Review cases table already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000010_add_decision_approval.up.sql 2>/dev/null || echo "Decision approval columns already exist"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000011_create_scheduler_runs.up.sql 2>/dev/null || echo "Scheduler runs table already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000012_add_decision_trigger_snapshot.up.sql 2>/dev/null || echo "Decision trigger column already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000013_create_outbox_events.up.sql 2>/dev/null || echo "Outbox events table already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000014_create_webhooks.up.sql 2>/dev/null || echo "Webhook tables already exist"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000015_create_shadow_decisions.up.sql 2>/dev/null || echo "Shadow decisions table already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000016_add_decision_inputs.up.sql 2>/dev/null || echo "Decision inputs column already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000017_create_backtests.up.sql 2>/dev/null || echo "Backtests table already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000018_add_review_case_unresolved_unique.up.sql 2>/dev/null || echo "Review case unresolved index already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000019_add_snapshot_reevaluation_due.up.sql 2>/dev/null || echo "Snapshot reevaluation_due column already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000020_add_decision_superseded_status.up.sql 2>/dev/null || echo "Decision superseded status already allowed"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000021_create_backtest_rows.up.sql 2>/dev/null || echo "Backtest rows table already exists"
+docker exec -i $CONTAINER_ID psql -U postgres -d papaya_payout_engine < migration/000022_add_backtest_heartbeat.up.sql 2>/dev/null || echo "Backtest heartbeat column already exists"
 echo "✓ Migrations complete"
 echo ""
 